@@ -0,0 +1,484 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
+)
+
+// batchOperation is a line of a batch script.
+type batchOperation struct {
+	line int
+	name string
+	mark string
+	// windowID is the window given to the operation, 0 for the focused one
+	windowID int
+}
+
+// batchStep is an applied operation: its event and the window change made
+// once the storage changes are committed, if any.
+type batchStep struct {
+	event  format.OutputEvent
+	window func() error
+}
+
+// BatchCmd applies a script of operations with all storage changes in a
+// single transaction.
+func BatchCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	batchCmd := &cobra.Command{
+		Use:   "batch [file] [flags]",
+		Short: "Apply a script of operations all at once",
+		Long: `Apply a script of operations all at once
+
+Reads a script from the file, or from stdin when the file is omitted or "-",
+with one operation per line. Empty lines and lines starting with # are ignored.
+
+  mark <identifier> [window-id]    replace the marks of the window with identifier
+  add <identifier> [window-id]     add identifier to the marks of the window
+  toggle <identifier> [window-id]  remove identifier if set, otherwise add it
+  unmark <identifier>              remove identifier
+  summon <identifier>              move the marked window to the current workspace
+  focus <identifier>               focus the marked window
+
+The window is the focused one unless window-id is given. The storage changes
+are all-or-nothing: when an operation fails, none is applied and no window is
+moved or focused. Windows are moved and focused after the changes are saved.
+
+Prints one event per operation, as NDJSON by default.
+
+Example:
+
+  aerospace-marks batch < workspace.marks
+  printf 'mark term 12\nmark mail 34\nsummon term\n' | aerospace-marks batch -o text
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			outputFormat, _ := cmd.Flags().GetString("output")
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			path := "-"
+			if len(args) > 0 {
+				path = args[0]
+			}
+			operations, err := readBatchScript(cmd.InOrStdin(), path)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			steps, err := applyBatch(storageClient, aerospaceClient, operations)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			for _, step := range steps {
+				if step.window != nil {
+					if err = step.window(); err != nil {
+						stdout.ErrorAndExit(err)
+						return
+					}
+				}
+				if err = formatter.Format(step.event); err != nil {
+					stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", err))
+					return
+				}
+			}
+		},
+	}
+
+	enableOutputFlag(batchCmd)
+	// Scripts are mostly run by other programs, one JSON event per line
+	outputFlag := batchCmd.Flag("output")
+	outputFlag.DefValue = string(format.OutputFormatNDJSON)
+	_ = outputFlag.Value.Set(outputFlag.DefValue)
+
+	return batchCmd
+}
+
+// readBatchScript reads and validates the operations of a script from path,
+// or from stdin when path is "-".
+func readBatchScript(stdin io.Reader, path string) ([]batchOperation, error) {
+	reader := stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, errkind.Errorf(errkind.InvalidInput, "failed to read '%s': %w", path, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var operations []batchOperation
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		operation, err := parseBatchOperation(line, strings.Fields(text))
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, operation)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errkind.Errorf(errkind.InvalidInput, "failed to read '%s': %w", path, err)
+	}
+
+	return operations, nil
+}
+
+func parseBatchOperation(line int, words []string) (batchOperation, error) {
+	operation := batchOperation{line: line, name: words[0]}
+
+	maxArgs, usage := 1, "an identifier"
+	switch operation.name {
+	case "mark", "add", "toggle":
+		maxArgs, usage = 2, "an identifier and an optional window ID"
+	case "unmark", "summon", "focus":
+	default:
+		return operation, errkind.Errorf(
+			errkind.InvalidInput,
+			"line %d: unknown operation '%s' (valid operations: %s)",
+			line,
+			operation.name,
+			"mark, add, toggle, unmark, summon or focus",
+		)
+	}
+
+	args := words[1:]
+	if len(args) == 0 || len(args) > maxArgs {
+		return operation, errkind.Errorf(
+			errkind.InvalidInput,
+			"line %d: %s takes %s",
+			line,
+			operation.name,
+			usage,
+		)
+	}
+	operation.mark = args[0]
+
+	if len(args) == 2 {
+		windowID, err := strconv.Atoi(args[1])
+		if err != nil || windowID <= 0 {
+			return operation, errkind.Errorf(
+				errkind.InvalidInput,
+				"line %d: invalid window ID '%s'",
+				line,
+				args[1],
+			)
+		}
+		operation.windowID = windowID
+	}
+
+	return operation, nil
+}
+
+// applyBatch applies the storage changes of the operations in a single
+// transaction. The windows are read from AeroSpace before, so the
+// transaction doesn't wait on it, and the window changes are returned to be
+// made once committed.
+func applyBatch(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	operations []batchOperation,
+) ([]batchStep, error) {
+	runner := &batchRunner{
+		storage:   storageClient,
+		aerospace: aerospaceClient,
+	}
+	if err := runner.readWindows(operations); err != nil {
+		return nil, fmt.Errorf("%w (no changes were applied)", err)
+	}
+
+	steps := make([]batchStep, 0, len(operations))
+	err := storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		runner.tx = tx
+		if err := runner.autoPrune(); err != nil {
+			return err
+		}
+
+		for _, operation := range operations {
+			step, err := runner.apply(operation)
+			if err != nil {
+				return fmt.Errorf(
+					"line %d: %s %s: %w",
+					operation.line,
+					operation.name,
+					operation.mark,
+					err,
+				)
+			}
+			steps = append(steps, step)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w (no changes were applied)", err)
+	}
+
+	return steps, nil
+}
+
+// batchRunner applies batch operations with the windows and the workspace
+// that has focus read once, before the transaction.
+type batchRunner struct {
+	// storage is used by the window changes, made after the commit
+	storage   storage.MarkStorage
+	tx        storage.MarkStorage
+	aerospace aerospace.AerosSpaceMarkWindows
+
+	windows          []windows.Window
+	focusedWindow    *windows.Window
+	focusedWorkspace string
+}
+
+// readWindows reads from AeroSpace the windows and the workspace that has
+// focus the operations need.
+func (r *batchRunner) readWindows(operations []batchOperation) error {
+	var needsWindows, needsFocusedWindow, needsWorkspace bool
+	for _, operation := range operations {
+		switch {
+		case operation.name == "summon":
+			needsWindows, needsWorkspace = true, true
+		case operation.name == "focus", operation.windowID != 0:
+			needsWindows = true
+		case operation.name != "unmark":
+			needsFocusedWindow = true
+		}
+	}
+
+	if !needsWindows && !needsFocusedWindow && !needsWorkspace {
+		return nil
+	}
+
+	client, err := r.aerospace.Client()
+	if err != nil {
+		return err
+	}
+
+	if needsWindows {
+		windowsList, err := client.Windows().GetAllWindows()
+		if err != nil {
+			return err
+		}
+		r.windows = windowsList
+	}
+
+	if needsFocusedWindow {
+		focusedWindow, err := client.Windows().GetFocusedWindow()
+		if err != nil {
+			return err
+		}
+		r.focusedWindow = focusedWindow
+	}
+
+	if needsWorkspace {
+		workspace, err := client.Workspaces().GetFocusedWorkspace()
+		if err != nil {
+			return err
+		}
+		r.focusedWorkspace = workspace.Workspace
+	}
+
+	return nil
+}
+
+// autoPrune removes the orphan marks when auto prune is enabled and the
+// windows were read.
+func (r *batchRunner) autoPrune() error {
+	if r.windows == nil || !aerospace.IsAutoPruneEnabled() {
+		return nil
+	}
+
+	marks, err := r.tx.GetMarks()
+	if err != nil {
+		return err
+	}
+	aerospace.AutoPrune(r.tx, marks, r.windows)
+
+	return nil
+}
+
+func (r *batchRunner) apply(operation batchOperation) (batchStep, error) {
+	switch operation.name {
+	case "summon":
+		return r.summon(operation.mark)
+	case "focus":
+		return r.focus(operation.mark)
+	case "unmark":
+		rowsAffected, err := r.tx.DeleteByMark(operation.mark)
+		if err != nil {
+			return batchStep{}, err
+		}
+		if rowsAffected == 0 {
+			return batchStep{}, errkind.Errorf(
+				errkind.MarkNotFound,
+				"mark '%s' not found",
+				operation.mark,
+			)
+		}
+		return batchStep{event: batchEvent(operation, nil, "Removed mark: "+operation.mark)}, nil
+	}
+
+	window, err := r.window(operation.windowID)
+	if err != nil {
+		return batchStep{}, err
+	}
+	metadata := aerospace.NewWindowMetadata(*window)
+
+	var message string
+	switch operation.name {
+	case "add":
+		err = r.tx.AddMark(window.WindowID, operation.mark, metadata)
+		message = "Added mark: " + operation.mark
+	case "toggle":
+		err = r.tx.ToggleMark(window.WindowID, operation.mark, metadata)
+		message = "Toggling mark: " + operation.mark
+	default:
+		var deleted int64
+		deleted, err = r.tx.ReplaceAllMarks(window.WindowID, operation.mark, metadata)
+		message = fmt.Sprintf("Marked window with '%s'", operation.mark)
+		if deleted > 0 {
+			message = fmt.Sprintf("Replaced all marks with '%s'", operation.mark)
+		}
+	}
+	if err != nil {
+		return batchStep{}, err
+	}
+
+	return batchStep{event: batchEvent(operation, window, message)}, nil
+}
+
+// summon records where the window of mark comes from and returns the move
+// to the focused workspace.
+func (r *batchRunner) summon(mark string) (batchStep, error) {
+	window, err := r.resolve(mark)
+	if err != nil {
+		return batchStep{}, err
+	}
+
+	target := r.focusedWorkspace
+
+	// Remember where the window came from, unless it is already here
+	if window.Workspace != "" && window.Workspace != target {
+		if err = r.tx.SetMarkOrigin(mark, window.Workspace); err != nil {
+			return batchStep{}, err
+		}
+	}
+
+	event := batchEvent(
+		batchOperation{name: "summon", mark: mark},
+		window,
+		fmt.Sprintf("Window %d summoned to workspace %s", window.WindowID, target),
+	)
+	event.TargetWorkspace = target
+
+	windowID := window.WindowID
+	return batchStep{
+		event: event,
+		window: func() error {
+			client, err := r.aerospace.Client()
+			if err != nil {
+				return err
+			}
+			return client.Workspaces().MoveWindowToWorkspaceWithOpts(
+				workspaces.MoveWindowToWorkspaceArgs{WorkspaceName: target},
+				workspaces.MoveWindowToWorkspaceOpts{WindowID: &windowID},
+			)
+		},
+	}, nil
+}
+
+// focus returns the focus switch to the window of mark.
+func (r *batchRunner) focus(mark string) (batchStep, error) {
+	window, err := r.resolve(mark)
+	if err != nil {
+		return batchStep{}, err
+	}
+
+	windowID := window.WindowID
+	return batchStep{
+		event: batchEvent(
+			batchOperation{name: "focus", mark: mark},
+			window,
+			fmt.Sprintf("Focused window %d", windowID),
+		),
+		window: func() error {
+			// Remember the window that had focus so `focus --back` returns to it
+			_, focusErr := aerospace.NewFocusSwitcher(r.storage, r.aerospace).Focus(windowID, false)
+			return focusErr
+		},
+	}, nil
+}
+
+// resolve returns the live window of mark among the windows read.
+func (r *batchRunner) resolve(mark string) (*windows.Window, error) {
+	markedWindow, err := r.tx.GetWindowByMark(mark)
+	if err != nil {
+		return nil, err
+	}
+
+	return aerospace.NewMarkResolver(r.tx, r.aerospace).Resolve(*markedWindow, r.windows)
+}
+
+// window returns the window with the given ID, or the focused window for 0.
+func (r *batchRunner) window(windowID int) (*windows.Window, error) {
+	if windowID == 0 {
+		return r.focusedWindow, nil
+	}
+
+	for i := range r.windows {
+		if r.windows[i].WindowID == windowID {
+			return &r.windows[i], nil
+		}
+	}
+
+	return nil, errkind.Errorf(errkind.WindowGone, "window with ID %d not found", windowID)
+}
+
+func batchEvent(
+	operation batchOperation,
+	window *windows.Window,
+	message string,
+) format.OutputEvent {
+	event := format.OutputEvent{
+		Command: "batch",
+		Action:  operation.name,
+		Result:  "success",
+		Message: message,
+	}
+	if window != nil {
+		event.WindowID = window.WindowID
+		event.AppName = window.AppName
+		event.Workspace = window.Workspace
+	}
+
+	return event
+}
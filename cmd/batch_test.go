@@ -0,0 +1,182 @@
+package cmd_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+func TestBatchCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "nvim", AppName: "Alacritty", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "inbox", AppName: "Mail", Workspace: "2"},
+		{WindowID: 3, WindowTitle: "docs", AppName: "Brave", Workspace: "3"},
+	}
+
+	t.Run("applies a script from stdin - `batch`", func(t *testing.T) {
+		args := []string{"batch"}
+		script := `# set up the workspace
+mark term
+add mail 2
+toggle web 3
+
+unmark old
+summon mail
+`
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		transaction := mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().
+			ReplaceAllMarks(1, "term", storage.WindowMetadata{
+				AppName:     "Alacritty",
+				WindowTitle: "nvim",
+				Workspace:   "1",
+			}).
+			Return(int64(0), nil).
+			Times(1)
+		strg.EXPECT().AddMark(2, "mail", gomock.Any()).Return(nil).Times(1)
+		strg.EXPECT().ToggleMark(3, "web", gomock.Any()).Return(nil).Times(1)
+		strg.EXPECT().DeleteByMark("old").Return(int64(1), nil).Times(1)
+		strg.EXPECT().
+			GetWindowByMark("mail").
+			Return(&queries.Mark{WindowID: 2, Mark: "mail"}, nil).
+			Times(1)
+		strg.EXPECT().SetMarkOrigin("mail", "2").Return(nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		allWindows := mocks.ExpectGetAllWindows(conn, windows).Times(1)
+		focusedWindow := mocks.ExpectGetFocusedWindow(conn, windows[0]).Times(1)
+		focusedWorkspace := mocks.ExpectGetFocusedWorkspace(conn, "1").Times(1)
+		// AeroSpace is read before the transaction, which doesn't wait on it
+		transaction.After(allWindows).After(focusedWindow).After(focusedWorkspace)
+		mocks.ExpectCommand(conn, "move-node-to-workspace", gomock.Any()).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecuteWithStdin(rootCmd, script, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("stdin", script),
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("applies a script file - `batch setup.marks -o text`", func(t *testing.T) {
+		args := []string{"batch", "setup.marks", "-o", "text"}
+		script := "mark term 1\nfocus term\n"
+		t.Chdir(t.TempDir())
+		require.NoError(t, os.WriteFile("setup.marks", []byte(script), 0o600))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().ReplaceAllMarks(1, "term", gomock.Any()).Return(int64(2), nil).Times(1)
+		strg.EXPECT().
+			GetWindowByMark("term").
+			Return(&queries.Mark{WindowID: 1, Mark: "term"}, nil).
+			Times(1)
+		strg.EXPECT().PushFocusHistory(2).Return(nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(conn, windows[1]).Times(1)
+		mocks.ExpectCommand(conn, "focus", gomock.Any()).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("setup.marks", script),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("applies nothing when an operation fails", func(t *testing.T) {
+		args := []string{"batch"}
+		script := "mark term 1\nunmark missing\nsummon term\n"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().ReplaceAllMarks(1, "term", gomock.Any()).Return(int64(0), nil).Times(1)
+		strg.EXPECT().DeleteByMark("missing").Return(int64(0), nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(conn, "1").Times(1)
+
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecuteWithStdin(rootCmd, script, args...)
+		require.Error(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stderr:  err.Error(),
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("stdin", script),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("validates the whole script first - `batch -o text`", func(t *testing.T) {
+		args := []string{"batch", "-o", "text"}
+		script := "mark term\nrename term editor\n"
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecuteWithStdin(rootCmd, script, args...)
+		require.Error(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stderr:  err.Error(),
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("stdin", script),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
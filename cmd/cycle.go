@@ -0,0 +1,344 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+const (
+	cycleOrderName   = "name"
+	cycleOrderRecent = "recent"
+)
+
+// cycleCandidate is a marked window that focus can cycle to.
+type cycleCandidate struct {
+	mark   string
+	window *windows.Window
+}
+
+// FocusNextCmd represents the focus-next command.
+func FocusNextCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	return focusCycleCmd(storageClient, aerospaceClient, "focus-next", 1)
+}
+
+// FocusPrevCmd represents the focus-prev command.
+func FocusPrevCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	return focusCycleCmd(storageClient, aerospaceClient, "focus-prev", -1)
+}
+
+//nolint:funlen // focusCycleCmd builds both focus-next and focus-prev
+func focusCycleCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	name string,
+	step int,
+) *cobra.Command {
+	direction := "next"
+	if step < 0 {
+		direction = "previous"
+	}
+
+	cycleCmd := &cobra.Command{
+		Use:   name + " <prefix|glob> [flags]",
+		Short: "Focus the " + direction + " window among the marks matching a prefix or glob",
+		Long: `Focus the ` + direction + ` window among the marks matching a prefix or glob
+
+Marks like 'web:docs', 'web:jira' and 'web:ci' can be cycled with one key
+using the prefix 'web:', the glob 'web:*' or the regex '/^web:/'. Marks whose
+window is gone are skipped, and cycling wraps around.
+
+Marks are ordered by name, or with --order recent by the last time their
+window had focus (most recent first). That order is kept while cycling, until
+another window gets focus.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+
+Example:
+
+  alt-w = 'exec-and-forget aerospace-marks focus-next web:'
+  alt-shift-w = 'exec-and-forget aerospace-marks focus-prev web:'
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateArgIsNotEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+			pattern := args[0]
+			logger.LogDebug("FocusCycleCmd called", "command", name, "pattern", pattern)
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			order, err := cmd.Flags().GetString("order")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			if order != cycleOrderName && order != cycleOrderRecent {
+				stdout.ErrorAndExit(errkind.Errorf(
+					errkind.InvalidInput,
+					"invalid order '%s', expected %s or %s",
+					order,
+					cycleOrderName,
+					cycleOrderRecent,
+				))
+				return
+			}
+
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			focusedID := 0
+			client, err := aerospaceClient.Client()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			focusedWindow, err := client.Windows().GetFocusedWindow()
+			if err == nil {
+				focusedID = focusedWindow.WindowID
+			}
+
+			candidates, err := cycleCandidates(
+				storageClient,
+				aerospaceClient,
+				pattern,
+				order,
+				focusedID,
+			)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			if len(candidates) == 0 {
+				stdout.ErrorAndExit(errkind.Errorf(
+					errkind.MarkNotFound,
+					"no live window marked with '%s'",
+					pattern,
+				))
+				return
+			}
+
+			// Start before the first (or after the last) if no candidate has focus
+			current := -1
+			if step < 0 {
+				current = len(candidates)
+			}
+			for i, candidate := range candidates {
+				if candidate.window.WindowID == focusedID {
+					current = i
+					break
+				}
+			}
+
+			target := candidates[(current+step+len(candidates))%len(candidates)]
+			windowID := target.window.WindowID
+
+			_, err = aerospace.NewFocusSwitcher(storageClient, aerospaceClient).Focus(windowID, false)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			logger.LogDebug("Focus cycled", "mark", target.mark, "windowID", windowID)
+
+			if order == cycleOrderRecent {
+				recordFocusCycle(storageClient, pattern, candidates, windowID)
+			}
+
+			event := format.OutputEvent{
+				Command:   name,
+				Action:    "focus",
+				WindowID:  windowID,
+				AppName:   target.window.AppName,
+				Workspace: target.window.Workspace,
+				Result:    "success",
+				Message:   fmt.Sprintf("Focus moved to window ID %d (%s)", windowID, target.mark),
+			}
+
+			if formatErr := formatter.Format(event); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+		},
+	}
+
+	cycleCmd.Flags().String(
+		"order",
+		cycleOrderName,
+		"Order of the marks: "+cycleOrderName+" or "+cycleOrderRecent+" (last focused first)",
+	)
+
+	return cycleCmd
+}
+
+// cycleCandidates returns the live windows of the marks matching pattern,
+// one per window, in the given order.
+func cycleCandidates(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	pattern string,
+	order string,
+	focusedID int,
+) ([]cycleCandidate, error) {
+	match, err := markPatternMatcher(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	marks, err := storageClient.GetMarks()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []queries.Mark{}
+	for _, mark := range marks {
+		if match(mark.Mark) {
+			matched = append(matched, mark)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Mark < matched[j].Mark
+	})
+
+	client, err := aerospaceClient.Client()
+	if err != nil {
+		return nil, err
+	}
+	windowsList, err := client.Windows().GetAllWindows()
+	if err != nil {
+		return nil, err
+	}
+	if aerospace.IsAutoPruneEnabled() {
+		aerospace.AutoPrune(storageClient, marks, windowsList)
+	}
+
+	resolver := aerospace.NewMarkResolver(storageClient, aerospaceClient)
+	seen := map[int]bool{}
+	candidates := []cycleCandidate{}
+	for _, mark := range matched {
+		window, resolveErr := resolver.Resolve(mark, windowsList)
+		if errors.Is(resolveErr, aerospace.ErrWindowGone) {
+			logger.GetDefaultLogger().LogDebug("Skipping orphaned mark", "mark", mark.Mark)
+			continue
+		}
+		if resolveErr != nil {
+			return nil, resolveErr
+		}
+
+		// A window with several matching marks is visited once
+		if seen[window.WindowID] {
+			continue
+		}
+		seen[window.WindowID] = true
+
+		candidates = append(candidates, cycleCandidate{mark: mark.Mark, window: window})
+	}
+
+	if order == cycleOrderRecent {
+		if err = sortByRecency(storageClient, candidates, pattern, focusedID); err != nil {
+			return nil, err
+		}
+	}
+
+	return candidates, nil
+}
+
+// sortByRecency sorts the candidates by the last time their window had focus.
+//
+// Every step changes the recency of the windows, so while cycling, i.e. the
+// focused window is the one the last step of the same pattern focused, the
+// order recorded by the first step is kept. Windows marked since then go
+// last.
+func sortByRecency(
+	storageClient storage.MarkStorage,
+	candidates []cycleCandidate,
+	pattern string,
+	focusedID int,
+) error {
+	cycle, err := storageClient.GetFocusCycle()
+	if err != nil {
+		return err
+	}
+
+	position := map[int]int{}
+	if cycle != nil && focusedID != 0 && cycle.Pattern == pattern && cycle.WindowID == focusedID {
+		for i, windowID := range cycle.WindowIDs {
+			position[windowID] = i + 1
+		}
+	}
+
+	recency, err := storageClient.GetFocusRecency()
+	if err != nil {
+		return err
+	}
+
+	// Stable, so windows never focused keep the name order
+	sort.SliceStable(candidates, func(i, j int) bool {
+		left := position[candidates[i].window.WindowID]
+		right := position[candidates[j].window.WindowID]
+		if left != right {
+			return right == 0 || (left != 0 && left < right)
+		}
+		return recency[candidates[i].window.WindowID] > recency[candidates[j].window.WindowID]
+	})
+
+	return nil
+}
+
+// recordFocusCycle records the order of the candidates for the next step of
+// the cycle, see sortByRecency.
+func recordFocusCycle(
+	storageClient storage.MarkStorage,
+	pattern string,
+	candidates []cycleCandidate,
+	windowID int,
+) {
+	windowIDs := make([]int, 0, len(candidates))
+	for _, candidate := range candidates {
+		windowIDs = append(windowIDs, candidate.window.WindowID)
+	}
+
+	err := storageClient.SetFocusCycle(storage.FocusCycle{
+		Pattern:   pattern,
+		WindowIDs: windowIDs,
+		WindowID:  windowID,
+	})
+	if err != nil {
+		logger.GetDefaultLogger().LogError("failed to record the focus cycle", "err", err)
+	}
+}
@@ -0,0 +1,276 @@
+package cmd_test
+
+import (
+	"maps"
+	"strconv"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+func TestFocusCycleCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{WindowID: 2, Mark: "web:jira"},
+		{WindowID: 1, Mark: "web:docs"},
+		{WindowID: 3, Mark: "web:ci"},
+		{WindowID: 4, Mark: "chat"},
+	}
+	// Window 3 is gone, web:ci is skipped
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "docs", AppName: "Brave", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "jira", AppName: "Brave", Workspace: "2"},
+		{WindowID: 4, WindowTitle: "chat", AppName: "Slack", Workspace: "3"},
+	}
+
+	t.Run("validate missing pattern", func(t *testing.T) {
+		args := []string{"focus-next"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	tests := []struct {
+		name      string
+		args      []string
+		focused   aerospace.Window
+		recency   map[int]int64
+		windowID  string
+		pushedID  int
+		usesOrder bool
+	}{
+		{
+			name:     "focuses the next mark by name - `focus-next web:`",
+			args:     []string{"focus-next", "web:"},
+			focused:  windows[0],
+			windowID: "2",
+			pushedID: 1,
+		},
+		{
+			name:     "wraps around after the last mark - `focus-next web:`",
+			args:     []string{"focus-next", "web:"},
+			focused:  windows[1],
+			windowID: "1",
+			pushedID: 2,
+		},
+		{
+			name:     "starts from the last mark when none has focus - `focus-prev 'web:*' -o json`",
+			args:     []string{"focus-prev", "web:*", "-o", "json"},
+			focused:  windows[2],
+			windowID: "2",
+			pushedID: 4,
+		},
+		{
+			name:     "wraps around before the first mark - `focus-prev web: -o csv`",
+			args:     []string{"focus-prev", "web:", "-o", "csv"},
+			focused:  windows[0],
+			windowID: "2",
+			pushedID: 1,
+		},
+		{
+			name:      "orders by last focused - `focus-next web: --order recent`",
+			args:      []string{"focus-next", "web:", "--order", "recent"},
+			focused:   windows[2],
+			recency:   map[int]int64{1: 5, 2: 10},
+			usesOrder: true,
+			windowID:  "2",
+			pushedID:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+			strg.EXPECT().PushFocusHistory(tt.pushedID).Return(nil).Times(1)
+			if tt.usesOrder {
+				strg.EXPECT().GetFocusCycle().Return(nil, nil).Times(1)
+				strg.EXPECT().GetFocusRecency().Return(tt.recency, nil).Times(1)
+				strg.EXPECT().SetFocusCycle(storage.FocusCycle{
+					Pattern:   "web:",
+					WindowIDs: []int{2, 1},
+					WindowID:  2,
+				}).Return(nil).Times(1)
+			}
+
+			mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+			// Once to find the current mark, once to record the focus history
+			mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, tt.focused).Times(2)
+			mocks.ExpectCommand(
+				mockAeroSpaceConnection,
+				"focus",
+				[]string{"--window-id", tt.windowID},
+			).Times(1)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tt.args...),
+				Stdout:  out,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("marks", marks),
+					testutils.Context("windows", windows),
+					testutils.Context("focused", tt.focused),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	t.Run("fails when no live window matches - `focus-next term`", func(t *testing.T) {
+		args := []string{"focus-next", "term"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails with an invalid glob - `focus-next web:[`", func(t *testing.T) {
+		args := []string{"focus-next", "web:["}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
+
+func TestFocusCycleCmdRecentOrder(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{WindowID: 1, Mark: "web:docs"},
+		{WindowID: 2, Mark: "web:jira"},
+		{WindowID: 3, Mark: "web:ci"},
+	}
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "docs", AppName: "Brave", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "jira", AppName: "Brave", Workspace: "2"},
+		{WindowID: 3, WindowTitle: "ci", AppName: "Brave", Workspace: "3"},
+	}
+
+	t.Run("keeps the order while cycling - `focus-next web: --order recent`", func(t *testing.T) {
+		args := []string{"focus-next", "web:", "--order", "recent"}
+
+		// The focus history and the focus cycle, updated by every press
+		focused := windows[0]
+		recency := map[int]int64{1: 3, 2: 2, 3: 1}
+		lastID := int64(3)
+		var cycle *storage.FocusCycle
+
+		focusedIDs := []int{}
+		for range 4 {
+			ctrl := gomock.NewController(t)
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+			strg.EXPECT().GetFocusCycle().Return(cycle, nil).Times(1)
+			strg.EXPECT().GetFocusRecency().Return(maps.Clone(recency), nil).Times(1)
+			strg.EXPECT().PushFocusHistory(gomock.Any()).DoAndReturn(func(windowID int) error {
+				lastID++
+				recency[windowID] = lastID
+				return nil
+			}).Times(1)
+			strg.EXPECT().SetFocusCycle(gomock.Any()).DoAndReturn(func(c storage.FocusCycle) error {
+				cycle = &c
+				return nil
+			}).Times(1)
+
+			mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+			mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, focused).Times(2)
+			mocks.ExpectCommand(mockAeroSpaceConnection, "focus", gomock.Any()).
+				Do(func(_ string, args []string) {
+					windowID, err := strconv.Atoi(args[1])
+					require.NoError(t, err)
+					focused = windows[windowID-1]
+				}).
+				Times(1)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			if _, err := testutils.CmdExecute(rootCmd, args...); err != nil {
+				t.Fatal(err)
+			}
+			ctrl.Finish()
+
+			focusedIDs = append(focusedIDs, focused.WindowID)
+		}
+
+		assert.Equal(t, []int{2, 3, 1, 2}, focusedIDs)
+	})
+}
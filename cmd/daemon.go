@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+const defaultSyncInterval = 2 * time.Second
+
+// DaemonCmd represents the daemon command.
+func DaemonCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	daemonCmd := &cobra.Command{
+		Use:   "daemon [flags]",
+		Short: "Run in background keeping marks consistent with AeroSpace windows",
+		Long: `Run in background keeping marks consistent with AeroSpace windows
+
+Keeps a single database and AeroSpace connection open and periodically:
+ - refreshes the window metadata (app, title, workspace) stored with marks
+ - re-binds marks (and tags) of windows that got a new ID
+ - removes marks and tags of windows that no longer exist
+
+It also listens on a Unix socket. Set ` + "`AEROSPACE_MARKS_SOCKET`" + ` to the same
+path to make mark, list, get, focus and summon delegate to the daemon.
+
+Add it to your AeroSpace config:
+
+  after-startup-command = ['exec-and-forget aerospace-marks daemon']
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+
+			socketPath, err := cmd.Flags().GetString("socket")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			interval, err := cmd.Flags().GetDuration("interval")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			if interval <= 0 {
+				stdout.ErrorAndExit(errkind.Errorf(
+					errkind.InvalidInput,
+					"interval must be greater than 0, got %s",
+					interval,
+				))
+				return
+			}
+
+			listener, err := daemon.Listen(socketPath)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			marksDaemon := daemon.New(storageClient, aerospaceClient)
+			server := daemon.NewServer()
+			marksDaemon.Register(server)
+
+			go marksDaemon.Run(ctx, interval)
+
+			logger.LogInfo("Daemon started", "socket", socketPath, "interval", interval)
+			fmt.Fprintf(os.Stdout, "Listening on %s\n", socketPath)
+
+			if serveErr := server.Serve(ctx, listener); serveErr != nil {
+				stdout.ErrorAndExit(serveErr)
+				return
+			}
+
+			logger.LogInfo("Daemon stopped")
+		},
+	}
+
+	daemonCmd.Flags().String("socket", daemon.SocketPath(), "Path of the Unix socket to listen on")
+	daemonCmd.Flags().Duration("interval", defaultSyncInterval, "Interval between syncs with AeroSpace")
+
+	return daemonCmd
+}
@@ -0,0 +1,34 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"errors"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+)
+
+// callDaemon calls a method of the running daemon when commands delegate
+// to it, see daemon.SetDefaultClient, and decodes its result into result.
+//
+// Returns false when the command must run itself: no daemon is set or it
+// isn't running. Any other error is returned with true, even failing to read
+// the answer: the daemon may have run the method already, running it again
+// would e.g. toggle a mark twice.
+func callDaemon(method string, params any, result any) (bool, error) {
+	client := daemon.GetDefaultClient()
+	if client == nil {
+		return false, nil
+	}
+
+	err := client.Call(method, params, result)
+	if errors.Is(err, daemon.ErrNotRunning) {
+		logger.GetDefaultLogger().
+			LogInfo("Running without the daemon", "method", method, "err", err)
+		return false, nil
+	}
+
+	return true, err
+}
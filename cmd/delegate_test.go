@@ -0,0 +1,161 @@
+package cmd_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	aerospaceipc "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+// delegateToDaemon serves the daemon on a temporary socket, with the given
+// storage and AeroSpace clients, and makes the commands delegate to it.
+func delegateToDaemon(
+	t *testing.T,
+	strg storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "marks")
+	require.NoError(t, err)
+	socketPath := filepath.Join(dir, "d.sock")
+
+	listener, err := daemon.Listen(socketPath)
+	require.NoError(t, err)
+	server := daemon.NewServer()
+	daemon.New(strg, aerospaceClient).Register(server)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = server.Serve(ctx, listener)
+	}()
+
+	daemon.SetDefaultClient(daemon.NewClient(socketPath))
+	t.Cleanup(func() {
+		daemon.SetDefaultClient(nil)
+		cancel()
+		<-done
+		os.RemoveAll(dir)
+	})
+}
+
+func TestCommandsWithDaemon(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	window := aerospaceipc.Window{
+		WindowID:    1,
+		WindowTitle: "nvim",
+		AppName:     "Alacritty",
+		Workspace:   "1",
+	}
+
+	t.Run("delegates mark to the daemon", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(conn, window).Times(1)
+		strg.EXPECT().
+			ReplaceAllMarks(1, "term", aerospace.NewWindowMetadata(window)).
+			Return(int64(0), nil).
+			Times(1)
+		delegateToDaemon(t, strg, aerospaceClient)
+
+		// The command itself doesn't touch the storage nor AeroSpace
+		out, err := testutils.CmdExecute(
+			cmd.NewRootCmd(nil, &testutils.MockEmptyAerspaceMarkWindows{}),
+			"mark", "term",
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "Marked window with 'term'\n", out)
+	})
+
+	t.Run("delegates list to the daemon", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		strg.EXPECT().GetMarks().Return([]queries.Mark{{WindowID: 1, Mark: "term"}}, nil).Times(1)
+		mocks.ExpectGetAllWindows(conn, []aerospaceipc.Window{window}).Times(1)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
+		delegateToDaemon(t, strg, aerospaceClient)
+
+		out, err := testutils.CmdExecute(
+			cmd.NewRootCmd(nil, &testutils.MockEmptyAerspaceMarkWindows{}),
+			"list",
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "term | 1 | Alacritty | nvim | 1 | _ | _\n", out)
+	})
+
+	t.Run("delegates get to the daemon", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("term").
+			Return(&queries.Mark{WindowID: 1, Mark: "term"}, nil).
+			Times(1)
+		mocks.ExpectGetAllWindows(conn, []aerospaceipc.Window{window}).Times(1)
+		delegateToDaemon(t, strg, aerospaceClient)
+
+		out, err := testutils.CmdExecute(
+			cmd.NewRootCmd(nil, &testutils.MockEmptyAerspaceMarkWindows{}),
+			"get", "term", "--window-id",
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "1", out)
+	})
+
+	t.Run("doesn't run the command again when the daemon hangs up", func(t *testing.T) {
+		dir, err := os.MkdirTemp("", "marks")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		socketPath := filepath.Join(dir, "d.sock")
+		listener, err := net.Listen("unix", socketPath)
+		require.NoError(t, err)
+		defer listener.Close()
+		go func() {
+			conn, acceptErr := listener.Accept()
+			if acceptErr == nil {
+				conn.Close()
+			}
+		}()
+
+		daemon.SetDefaultClient(daemon.NewClient(socketPath))
+		defer daemon.SetDefaultClient(nil)
+
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		// The daemon may have toggled the mark already, toggling it here
+		// would remove it again
+		_, err = testutils.CmdExecute(
+			cmd.NewRootCmd(nil, &testutils.MockEmptyAerspaceMarkWindows{}),
+			"mark", "--toggle", "term",
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read response")
+	})
+}
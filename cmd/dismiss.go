@@ -0,0 +1,127 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// DismissCmd represents the dismiss command.
+func DismissCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	dismissCmd := &cobra.Command{
+		Use:   "dismiss <identifier> [flags]",
+		Short: "Send a summoned window back to its original workspace",
+		Long: `Send a summoned window back to its original workspace
+
+summon records the workspace a marked window was taken from, dismiss moves
+it back there. Same as 'summon --return'.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateArgIsNotEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			mark := args[0]
+			logger.GetDefaultLogger().LogDebug("DismissCmd called", "mark", mark)
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			event, err := returnMarkedWindow(storageClient, aerospaceClient, mark)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			event.Command = "dismiss"
+			event.Action = "dismiss"
+
+			if formatErr := formatter.Format(*event); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+		},
+	}
+
+	return dismissCmd
+}
+
+// returnMarkedWindow moves the window of a mark back to the workspace
+// recorded when it was summoned, then forgets that workspace.
+func returnMarkedWindow(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	mark string,
+) (*format.OutputEvent, error) {
+	originWorkspace, err := storageClient.GetMarkOrigin(mark)
+	if err != nil {
+		return nil, err
+	}
+	if originWorkspace == "" {
+		return nil, errkind.Errorf(
+			errkind.InvalidInput,
+			"no original workspace recorded for mark '%s'",
+			mark,
+		)
+	}
+
+	window, err := aerospace.NewMarkResolver(storageClient, aerospaceClient).ResolveMark(mark)
+	if err != nil {
+		return nil, err
+	}
+	windowID := window.WindowID
+
+	err = moveWindowToWorkspace(aerospaceClient, windowID, originWorkspace)
+	if err != nil {
+		return nil, err
+	}
+
+	err = storageClient.DeleteMarkOrigin(mark)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.GetDefaultLogger().LogDebug(
+		"Window returned",
+		"windowID", windowID,
+		"workspace", window.Workspace,
+		"targetWorkspace", originWorkspace,
+	)
+
+	return &format.OutputEvent{
+		WindowID:        windowID,
+		AppName:         window.AppName,
+		Workspace:       window.Workspace,
+		TargetWorkspace: originWorkspace,
+		Result:          "success",
+		Message:         fmt.Sprintf("Window %d returned to workspace %s", windowID, originWorkspace),
+	}, nil
+}
@@ -0,0 +1,124 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+func TestDismissCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("sends a summoned window back - `dismiss mark1`", func(t *testing.T) {
+		args := []string{"dismiss", "mark1"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		windows := []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1", Workspace: "1"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarkOrigin("mark1").Return("3", nil).Times(1)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+		strg.EXPECT().DeleteMarkOrigin("mark1").Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"3", "--window-id", "1"},
+		).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("sends a summoned window back - `dismiss mark1 -o csv`", func(t *testing.T) {
+		args := []string{"dismiss", "mark1", "-o", "csv"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		windows := []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1", Workspace: "1"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarkOrigin("mark1").Return("3", nil).Times(1)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+		strg.EXPECT().DeleteMarkOrigin("mark1").Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "move-node-to-workspace", gomock.Any()).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails when the window wasn't summoned", func(t *testing.T) {
+		args := []string{"dismiss", "mark1", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarkOrigin("mark1").Return("", nil).Times(1)
+
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
@@ -0,0 +1,435 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/constants"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// Statuses of a doctor check. Only failures make doctor exit with an error.
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// doctorCheck is the result of a diagnostic check.
+type doctorCheck struct {
+	Name    string `json:"name" yaml:"name"`
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+	// Hint tells how to fix a check that didn't pass
+	Hint string `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+type doctorReport struct {
+	Checks []doctorCheck `json:"checks" yaml:"checks"`
+	Failed int           `json:"failed" yaml:"failed"`
+}
+
+// DoctorCmd diagnoses the setup of aerospace-marks.
+func DoctorCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the setup of aerospace-marks",
+		Long: `Check the setup of aerospace-marks and report problems with hints to fix them.
+
+Checks that the AeroSpace socket is reachable, the database is writable and
+migrated, no marks point to closed windows, no mark is duplicated or invalid and
+the log file is writable. Nothing is created: a database or log file that
+doesn't exist yet passes when its directory is writable.
+
+Exits with an error when a check fails, warnings don't fail. The error is
+ipc_unavailable when AeroSpace can't be reached, otherwise storage_failure.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputFlag, _ := cmd.Flags().GetString("output")
+			outputFormat, err := format.ParseDocumentFormat(outputFlag)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			report := runDoctorChecks(storageClient, aerospaceClient)
+
+			if outputFormat == format.OutputFormatText {
+				writeDoctorText(report)
+			} else if err = format.WriteDocument(os.Stdout, outputFormat, report); err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			if report.Failed > 0 {
+				stdout.ErrorAndExit(errkind.Errorf(
+					report.errorKind(),
+					"%d of %d checks failed: %s",
+					report.Failed,
+					len(report.Checks),
+					strings.Join(report.failedChecks(), ", "),
+				))
+			}
+		},
+	}
+
+	enableDocumentOutputFlag(doctorCmd)
+
+	return doctorCmd
+}
+
+// failedChecks returns the names of the checks that failed.
+func (r doctorReport) failedChecks() []string {
+	names := make([]string, 0, r.Failed)
+	for _, check := range r.Checks {
+		if check.Status == checkFail {
+			names = append(names, check.Name)
+		}
+	}
+
+	return names
+}
+
+// errorKind returns the kind of the error doctor exits with when checks
+// failed: IPCUnavailable if the socket check failed, otherwise Storage as
+// the other checks are about the files of aerospace-marks.
+func (r doctorReport) errorKind() errkind.Kind {
+	for _, check := range r.Checks {
+		if check.Name == "socket" && check.Status == checkFail {
+			return errkind.IPCUnavailable
+		}
+	}
+
+	return errkind.Storage
+}
+
+func runDoctorChecks(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) doctorReport {
+	// The database is checked before reading the marks, which creates and
+	// migrates it
+	dbClient := storageClient.Client()
+	dbConfig := dbClient.GetStorageConfig()
+	_, statErr := os.Stat(filepath.Join(dbConfig.DBPath, dbConfig.DBName))
+	databaseCheck := checkDatabaseWritable(dbConfig)
+	// A fresh install, the database doesn't exist until first used
+	fresh := errors.Is(statErr, fs.ErrNotExist) && databaseCheck.Status == checkPass
+	migrationsCheck := checkMigrations(dbClient, fresh)
+	marks, marksErr := storageClient.GetMarks()
+
+	checks := []doctorCheck{
+		checkSocket(aerospaceClient),
+		databaseCheck,
+		migrationsCheck,
+		checkOrphanMarks(aerospaceClient, marks, marksErr),
+		checkMarks(marks, marksErr),
+		checkLogFile(logger.GetDefaultLogger().GetConfig()),
+	}
+
+	report := doctorReport{Checks: checks}
+	for _, check := range checks {
+		if check.Status == checkFail {
+			report.Failed++
+		}
+	}
+
+	return report
+}
+
+func checkSocket(aerospaceClient aerospace.AerosSpaceMarkWindows) doctorCheck {
+	check := doctorCheck{Name: "socket"}
+	aerospaceWM, err := aerospaceClient.Client()
+	if err != nil {
+		check.Status = checkFail
+		check.Message = "AeroSpace is unreachable: " + err.Error()
+		check.Hint = fmt.Sprintf("start AeroSpace or set %s", constants.EnvAeroSpaceSock)
+		return check
+	}
+	client := aerospaceWM.Connection()
+
+	socketPath, err := client.GetSocketPath()
+	if err != nil {
+		check.Status = checkFail
+		check.Message = "AeroSpace socket not found: " + err.Error()
+		check.Hint = fmt.Sprintf("start AeroSpace or set %s", constants.EnvAeroSpaceSock)
+		return check
+	}
+
+	if err = client.CheckServerVersion(); err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("AeroSpace at %s is incompatible: %s", socketPath, err)
+		check.Hint = "upgrade AeroSpace and aerospace-marks to compatible versions"
+		return check
+	}
+
+	version, err := client.GetServerVersion()
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("AeroSpace at %s doesn't answer: %s", socketPath, err)
+		check.Hint = "restart AeroSpace"
+		return check
+	}
+
+	check.Status = checkPass
+	check.Message = fmt.Sprintf("AeroSpace %s reachable at %s", version, socketPath)
+	return check
+}
+
+// checkDatabaseWritable checks the database file can be written or, when it
+// doesn't exist yet, created.
+func checkDatabaseWritable(dbConfig storage.StorageConfig) doctorCheck {
+	check := doctorCheck{Name: "database"}
+	dbPath := filepath.Join(dbConfig.DBPath, dbConfig.DBName)
+
+	err := openForWriting(dbPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err = checkDirWritable(dbConfig.DBPath); err == nil {
+			check.Status = checkPass
+			check.Message = fmt.Sprintf(
+				"database %s not created yet, %s is writable",
+				dbPath,
+				dbConfig.DBPath,
+			)
+			return check
+		}
+	}
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("database %s is not writable: %s", dbPath, err)
+		check.Hint = fmt.Sprintf(
+			"fix the permissions of %s or set %s to a writable directory",
+			dbConfig.DBPath,
+			constants.EnvAeroSpaceMarksDBPath,
+		)
+		return check
+	}
+
+	check.Status = checkPass
+	check.Message = fmt.Sprintf("database %s is writable", dbPath)
+	return check
+}
+
+// checkMigrations checks the database is at the latest migration version.
+// The migrations of a fresh database, not created yet, are reported pending.
+func checkMigrations(dbClient storage.StorageDBClient, fresh bool) doctorCheck {
+	check := doctorCheck{Name: "migrations"}
+
+	latest, err := storage.LatestMigrationVersion()
+	if err != nil {
+		check.Status = checkFail
+		check.Message = "failed to read the migrations: " + err.Error()
+		check.Hint = "reinstall aerospace-marks"
+		return check
+	}
+
+	if fresh {
+		check.Status = checkPass
+		check.Message = fmt.Sprintf(
+			"migrations up to version %d pending, they run when the database is created",
+			latest,
+		)
+		return check
+	}
+
+	version, err := dbClient.GetVersion()
+	switch {
+	case err != nil:
+		check.Status = checkFail
+		check.Message = "failed to read the database version: " + err.Error()
+		check.Hint = "check the database is a valid aerospace-marks database"
+	case version < latest:
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("database at version %d, expected %d", version, latest)
+		check.Hint = "run any aerospace-marks command to migrate the database"
+	case version > latest:
+		check.Status = checkFail
+		check.Message = fmt.Sprintf(
+			"database at version %d, newer than the version %d of aerospace-marks",
+			version,
+			latest,
+		)
+		check.Hint = "upgrade aerospace-marks"
+	default:
+		check.Status = checkPass
+		check.Message = fmt.Sprintf("database at version %d", version)
+	}
+
+	return check
+}
+
+func checkOrphanMarks(
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	marks []queries.Mark,
+	marksErr error,
+) doctorCheck {
+	check := doctorCheck{Name: "orphans"}
+	if marksErr != nil {
+		check.Status = checkFail
+		check.Message = "failed to read the marks: " + marksErr.Error()
+		return check
+	}
+
+	client, err := aerospaceClient.Client()
+	var windowsList []windows.Window
+	if err == nil {
+		windowsList, err = client.Windows().GetAllWindows()
+	}
+	if err != nil {
+		check.Status = checkFail
+		check.Message = "failed to list the windows: " + err.Error()
+		check.Hint = "check the socket check passes"
+		return check
+	}
+
+	orphans := aerospace.FindOrphanMarks(marks, windowsList)
+	if len(orphans) > 0 {
+		check.Status = checkWarn
+		check.Message = fmt.Sprintf(
+			"%d of %d marks point to closed windows",
+			len(orphans),
+			len(marks),
+		)
+		check.Hint = "run 'aerospace-marks prune' to remove them"
+		return check
+	}
+
+	check.Status = checkPass
+	check.Message = "every mark points to an open window"
+	return check
+}
+
+func checkMarks(marks []queries.Mark, marksErr error) doctorCheck {
+	check := doctorCheck{Name: "marks"}
+	if marksErr != nil {
+		check.Status = checkFail
+		check.Message = "failed to read the marks: " + marksErr.Error()
+		return check
+	}
+
+	seen := make(map[string]bool, len(marks))
+	var duplicated, invalid []string
+	for _, mark := range marks {
+		if strings.TrimSpace(mark.Mark) == "" || mark.WindowID <= 0 {
+			invalid = append(invalid, fmt.Sprintf("'%s' (window ID %d)", mark.Mark, mark.WindowID))
+			continue
+		}
+		if seen[mark.Mark] {
+			duplicated = append(duplicated, fmt.Sprintf("'%s'", mark.Mark))
+		}
+		seen[mark.Mark] = true
+	}
+
+	if len(duplicated) == 0 && len(invalid) == 0 {
+		check.Status = checkPass
+		check.Message = fmt.Sprintf("%d marks, none duplicated or invalid", len(marks))
+		return check
+	}
+
+	problems := make([]string, 0, 2)
+	if len(duplicated) > 0 {
+		problems = append(problems, "duplicated marks: "+strings.Join(duplicated, ", "))
+	}
+	if len(invalid) > 0 {
+		problems = append(problems, "invalid marks: "+strings.Join(invalid, ", "))
+	}
+
+	check.Status = checkFail
+	check.Message = strings.Join(problems, "; ")
+	check.Hint = "remove them with 'aerospace-marks unmark <mark>' and mark the windows again"
+	return check
+}
+
+// checkLogFile checks the log file can be written or, when it doesn't exist
+// yet, created. Nothing is checked when logging is off.
+func checkLogFile(logConfig logger.LogConfig) doctorCheck {
+	check := doctorCheck{Name: "logs"}
+
+	if logConfig.Level == logger.LevelDisabled {
+		check.Status = checkPass
+		check.Message = fmt.Sprintf(
+			"logging is off, set %s to turn it on",
+			constants.EnvAeroSpaceMarksLogsLevel,
+		)
+		return check
+	}
+
+	logDir := filepath.Dir(logConfig.Path)
+	err := openForWriting(logConfig.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err = checkDirWritable(logDir); err == nil {
+			check.Status = checkPass
+			check.Message = fmt.Sprintf(
+				"log file %s not created yet, %s is writable",
+				logConfig.Path,
+				logDir,
+			)
+			return check
+		}
+	}
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("log file %s is not writable: %s", logConfig.Path, err)
+		check.Hint = fmt.Sprintf(
+			"fix the permissions of %s or set %s to a writable file",
+			logConfig.Path,
+			constants.EnvAeroSpaceMarksLogsPath,
+		)
+		return check
+	}
+
+	check.Status = checkPass
+	check.Message = fmt.Sprintf("log file %s is writable", logConfig.Path)
+	return check
+}
+
+// openForWriting checks path can be written without changing it.
+func openForWriting(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// checkDirWritable checks files can be created in dir, without creating any.
+func checkDirWritable(dir string) error {
+	if err := unix.Access(dir, unix.W_OK); err != nil {
+		return &fs.PathError{Op: "access", Path: dir, Err: err}
+	}
+
+	return nil
+}
+
+func writeDoctorText(report doctorReport) {
+	for _, check := range report.Checks {
+		status := strings.ToUpper(check.Status)
+		fmt.Fprintf(os.Stdout, "[%s] %s: %s\n", status, check.Name, check.Message)
+		if check.Hint != "" && check.Status != checkPass {
+			fmt.Fprintf(os.Stdout, "       hint: %s\n", check.Hint)
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, "\n%d checks, %d failed\n", len(report.Checks), report.Failed)
+}
@@ -0,0 +1,304 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+func TestDoctorCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	latestVersion, err := storage.LatestMigrationVersion()
+	require.NoError(t, err)
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "nvim", AppName: "Alacritty"},
+		{WindowID: 2, WindowTitle: "inbox", AppName: "Mail"},
+	}
+
+	// setup mocks a database in the relative directory "db" of a temporary
+	// working directory, so paths in snapshots don't change between runs
+	setup := func(
+		t *testing.T,
+		marks []queries.Mark,
+		dbVersion int64,
+	) (*gomock.Controller, storage.MarkStorage) {
+		t.Chdir(t.TempDir())
+		require.NoError(t, os.Mkdir("db", 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join("db", "storage.db"), nil, 0o600))
+
+		ctrl := gomock.NewController(t)
+		dbClient, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().Client().Return(dbClient).Times(1)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+		dbClient.EXPECT().
+			GetStorageConfig().
+			Return(storage.StorageConfig{DBPath: "db", DBName: "storage.db"}).
+			Times(1)
+		dbClient.EXPECT().GetVersion().Return(dbVersion, nil).Times(1)
+
+		return ctrl, strg
+	}
+
+	t.Run("passes every check - `doctor`", func(t *testing.T) {
+		args := []string{"doctor"}
+		marks := []queries.Mark{
+			{WindowID: 1, Mark: "term"},
+			{WindowID: 2, Mark: "mail"},
+		}
+
+		ctrl, strg := setup(t, marks, latestVersion)
+		defer ctrl.Finish()
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		conn.EXPECT().GetSocketPath().Return("/tmp/aerospace.sock", nil).Times(1)
+		conn.EXPECT().CheckServerVersion().Return(nil).Times(1)
+		conn.EXPECT().GetServerVersion().Return("0.19.2", nil).Times(1)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("warns about orphaned marks - `doctor -o yaml`", func(t *testing.T) {
+		args := []string{"doctor", "-o", "yaml"}
+		marks := []queries.Mark{
+			{WindowID: 1, Mark: "term"},
+			{WindowID: 3, Mark: "closed"},
+		}
+
+		ctrl, strg := setup(t, marks, latestVersion)
+		defer ctrl.Finish()
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		conn.EXPECT().GetSocketPath().Return("/tmp/aerospace.sock", nil).Times(1)
+		conn.EXPECT().CheckServerVersion().Return(nil).Times(1)
+		conn.EXPECT().GetServerVersion().Return("0.19.2", nil).Times(1)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails on duplicated marks and an old database - `doctor -o json`", func(t *testing.T) {
+		args := []string{"doctor", "-o", "json"}
+		marks := []queries.Mark{
+			{WindowID: 1, Mark: "term"},
+			{WindowID: 2, Mark: "term"},
+			{WindowID: 0, Mark: " "},
+		}
+
+		ctrl, strg := setup(t, marks, latestVersion-1)
+		defer ctrl.Finish()
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		conn.EXPECT().GetSocketPath().Return("/tmp/aerospace.sock", nil).Times(1)
+		conn.EXPECT().CheckServerVersion().Return(nil).Times(1)
+		conn.EXPECT().GetServerVersion().Return("0.19.2", nil).Times(1)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecute(rootCmd, args...)
+		require.Error(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails when nothing is reachable - `doctor -o json`", func(t *testing.T) {
+		args := []string{"doctor", "-o", "json"}
+		t.Chdir(t.TempDir())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dbErr := errkind.New(errkind.Storage, "unable to open database file")
+		dbClient, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().Client().Return(dbClient).Times(1)
+		strg.EXPECT().GetMarks().Return(nil, dbErr).Times(1)
+		dbClient.EXPECT().
+			GetStorageConfig().
+			Return(storage.StorageConfig{DBPath: "db", DBName: "storage.db"}).
+			Times(1)
+		dbClient.EXPECT().GetVersion().Return(int64(0), dbErr).Times(1)
+
+		aerospaceClient := &testutils.MockUnavailableAerospaceMarkWindows{
+			Err: errkind.New(errkind.IPCUnavailable, "dial unix /tmp/aerospace.sock: no such file"),
+		}
+
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.Error(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("passes on a fresh install - `doctor`", func(t *testing.T) {
+		args := []string{"doctor"}
+		t.Chdir(t.TempDir())
+		// The directory is created when the database is opened, the file
+		// only once it is used
+		require.NoError(t, os.Mkdir("db", 0o755))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dbClient, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().Client().Return(dbClient).Times(1)
+		strg.EXPECT().GetMarks().Return(nil, nil).Times(1)
+		dbClient.EXPECT().
+			GetStorageConfig().
+			Return(storage.StorageConfig{DBPath: "db", DBName: "storage.db"}).
+			Times(1)
+		dbClient.EXPECT().GetVersion().Times(0)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		conn.EXPECT().GetSocketPath().Return("/tmp/aerospace.sock", nil).Times(1)
+		conn.EXPECT().CheckServerVersion().Return(nil).Times(1)
+		conn.EXPECT().GetServerVersion().Return("0.19.2", nil).Times(1)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+		require.NoFileExists(t, filepath.Join("db", "storage.db"))
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
+
+// configLogger is a logger that logs nothing with the given configuration.
+type configLogger struct {
+	logger.EmptyLogger
+
+	config logger.LogConfig
+}
+
+func (l *configLogger) GetConfig() logger.LogConfig {
+	return l.config
+}
+
+func TestDoctorCmdLogs(t *testing.T) {
+	latestVersion, err := storage.LatestMigrationVersion()
+	require.NoError(t, err)
+	t.Cleanup(func() { logger.SetDefaultLogger(&logger.EmptyLogger{}) })
+
+	tests := []struct {
+		name    string
+		logPath string
+		setup   func(t *testing.T)
+		wantErr bool
+	}{
+		{
+			name:    "passes without creating a missing log file",
+			logPath: filepath.Join("logs", "marks.log"),
+			setup: func(t *testing.T) {
+				require.NoError(t, os.Mkdir("logs", 0o755))
+			},
+		},
+		{
+			name:    "fails when the log directory doesn't exist",
+			logPath: filepath.Join("missing", "marks.log"),
+			setup:   func(_ *testing.T) {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"doctor"}
+			t.Chdir(t.TempDir())
+			require.NoError(t, os.Mkdir("db", 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join("db", "storage.db"), nil, 0o600))
+			tt.setup(t)
+
+			logger.SetDefaultLogger(&configLogger{
+				config: logger.LogConfig{Path: tt.logPath, Level: "DEBUG"},
+			})
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			dbClient, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().Client().Return(dbClient).Times(1)
+			strg.EXPECT().GetMarks().Return(nil, nil).Times(1)
+			dbClient.EXPECT().
+				GetStorageConfig().
+				Return(storage.StorageConfig{DBPath: "db", DBName: "storage.db"}).
+				Times(1)
+			dbClient.EXPECT().GetVersion().Return(latestVersion, nil).Times(1)
+
+			conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			conn.EXPECT().GetSocketPath().Return("/tmp/aerospace.sock", nil).Times(1)
+			conn.EXPECT().CheckServerVersion().Return(nil).Times(1)
+			conn.EXPECT().GetServerVersion().Return("0.19.2", nil).Times(1)
+			mocks.ExpectGetAllWindows(conn, nil).Times(1)
+
+			//nolint:reassign // Test utility needs to modify package variable
+			stdout.ShouldExit = false
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, args...)
+			require.NoFileExists(t, tt.logPath)
+
+			spec := testutils.SnapshotSpec{
+				Command: testutils.CommandString(args...),
+				Stdout:  out,
+			}
+			if tt.wantErr {
+				require.Error(t, err)
+				spec.Stderr = err.Error()
+			} else {
+				require.NoError(t, err)
+			}
+			snaps.MatchSnapshot(t, testutils.RenderSnapshotSpec(spec))
+		})
+	}
+}
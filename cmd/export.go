@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// marksDocumentVersion is the version of the export document, bumped when
+// its fields change in a way older versions can't import.
+const marksDocumentVersion = 1
+
+// marksDocument is the portable backup of the marks written by export and
+// read by import.
+type marksDocument struct {
+	Version int            `json:"version" yaml:"version"`
+	Marks   []exportedMark `json:"marks" yaml:"marks"`
+}
+
+// exportedMark is a mark with the metadata of its window, used on import to
+// find the same window again.
+type exportedMark struct {
+	Mark        string `json:"mark" yaml:"mark"`
+	WindowID    int    `json:"window_id" yaml:"window_id"`
+	AppBundleID string `json:"app_bundle_id" yaml:"app_bundle_id"`
+	AppName     string `json:"app_name" yaml:"app_name"`
+	WindowTitle string `json:"window_title" yaml:"window_title"`
+	Workspace   string `json:"workspace" yaml:"workspace"`
+}
+
+// ExportCmd writes the marks as a JSON or YAML document.
+func ExportCmd(storageClient storage.MarkStorage) *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export [flags]",
+		Short: "Export the marks as a JSON or YAML document",
+		Long: `Export the marks as a JSON or YAML document
+
+Writes every mark with the metadata of its window (app bundle ID, app name,
+title and workspace) to stdout, to back the marks up or move them to another
+machine with import.
+
+Example:
+
+  aerospace-marks export > marks.json
+  aerospace-marks export --output yaml > marks.yaml
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputFlag, _ := cmd.Flags().GetString("output")
+			outputFormat, err := format.ParseDocumentFormat(outputFlag)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			if outputFormat == format.OutputFormatText {
+				stdout.ErrorAndExit(
+					errkind.New(errkind.InvalidInput, "export supports the json and yaml formats"),
+				)
+				return
+			}
+
+			marks, err := storageClient.GetMarks()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			document := marksDocument{
+				Version: marksDocumentVersion,
+				Marks:   make([]exportedMark, 0, len(marks)),
+			}
+			for _, mark := range marks {
+				document.Marks = append(document.Marks, exportedMark{
+					Mark:        mark.Mark,
+					WindowID:    mark.WindowID,
+					AppBundleID: mark.AppBundleID,
+					AppName:     mark.AppName,
+					WindowTitle: mark.WindowTitle,
+					Workspace:   mark.Workspace,
+				})
+			}
+
+			if err = format.WriteDocument(os.Stdout, outputFormat, document); err != nil {
+				stdout.ErrorAndExit(err)
+			}
+		},
+	}
+
+	exportCmd.Flags().StringP("output", "o", "json", "Output format: json or yaml")
+
+	return exportCmd
+}
@@ -0,0 +1,81 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{
+			WindowID:    10,
+			Mark:        "term",
+			AppBundleID: "io.alacritty",
+			AppName:     "Alacritty",
+			WindowTitle: "nvim",
+			Workspace:   "1",
+		},
+		{WindowID: 2, Mark: "mail", AppBundleID: "com.apple.mail", AppName: "Mail"},
+	}
+
+	for _, args := range [][]string{
+		{"export"},
+		{"export", "--output", "yaml"},
+	} {
+		t.Run(testutils.CommandString(args...), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+			_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, args...)
+			require.NoError(t, err)
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(args...),
+				Stdout:  out,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("marks", marks),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	t.Run("fails with the text format", func(t *testing.T) {
+		args := []string{"export", "-o", "text"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecute(rootCmd, args...)
+		require.Error(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
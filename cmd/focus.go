@@ -5,22 +5,28 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
 	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
 	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/launcher"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
 )
 
 //nolint:gochecknoglobals // focusDelay is a configuration constant
 var focusDelay = 100 * time.Millisecond // Default delay to wait for the window to be ready
 
 // FocusCmd represents the focus command.
+//
+//nolint:funlen // FocusCmd handles marks, going back and the daemon
 func FocusCmd(
 	storageClient storage.MarkStorage,
 	aerospaceClient aerospace.AerosSpaceMarkWindows,
@@ -31,15 +37,29 @@ func FocusCmd(
 		Long: `Move focus to a window by mark (identifier)
 
 Moves focus to the first window marked with the specified identifier.
-Output format can be controlled with --output flag (text, json, csv).
+If the marked window already has focus, focus goes back to the window that had
+focus before (like i3's 'workspace back_and_forth'), unless --no-toggle is set.
+Use --back to go back without a mark.
+With --launch, if the marked window is gone the launch command set with
+'mark --launch' is run, and the new window is marked and focused.
+When ` + "`AEROSPACE_MARKS_SOCKET`" + ` is set, focus is delegated to the running daemon.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
 	`,
-		Args: cobra.MatchAll(
-			cobra.ExactArgs(1),
-			cli.ValidateArgIsNotEmpty,
-		),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if back, _ := cmd.Flags().GetBool("back"); back {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MatchAll(
+				cobra.ExactArgs(1),
+				cli.ValidateArgIsNotEmpty,
+			)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			logger := logger.GetDefaultLogger()
-			mark := args[0]
+			mark := ""
+			if len(args) > 0 {
+				mark = args[0]
+			}
 			logger.LogDebug("FocusCmd called", "mark", mark)
 
 			// Get and validate output format early
@@ -54,43 +74,110 @@ Output format can be controlled with --output flag (text, json, csv).
 				outputFormat = string(format.OutputFormatText)
 			}
 
-			windowID, err := storageClient.GetWindowIDByMark(mark)
+			shouldGoBack, err := cmd.Flags().GetBool("back")
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
 
-			if windowID == 0 {
-				stdout.ErrorAndExitf("empty window id for mark '%s'", mark)
+			noToggle, err := cmd.Flags().GetBool("no-toggle")
+			if err != nil {
+				stdout.ErrorAndExit(err)
 				return
 			}
-			logger.LogDebug("Window found", "windowID", windowID)
 
-			// The program is too fast, what a problem to have!
-			// Delay setting focus to ensure the window is ready
-			time.Sleep(focusDelay)
-			err = aerospaceClient.Client().Focus().SetFocusByWindowID(windowID)
+			shouldLaunch, err := cmd.Flags().GetBool("launch")
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
 
-			logger.LogDebug("Focus set", "windowID", windowID)
+			launchTimeout, err := cmd.Flags().GetDuration("launch-timeout")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
 
 			// Format output using OutputEvent
-			formatter, err := format.NewOutputEventFormatter(os.Stdout, outputFormat)
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
 
-			message := fmt.Sprintf("Focus moved to window ID %d", windowID)
-			event := format.OutputEvent{
+			var event format.OutputEvent
+			params := daemon.FocusParams{Mark: mark, Back: shouldGoBack, NoToggle: noToggle}
+			delegated, err := callDaemon(daemon.MethodFocus, params, &event)
+			switch {
+			case delegated && err == nil:
+				if formatErr := formatter.Format(event); formatErr != nil {
+					stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				}
+				return
+			case delegated && !(shouldLaunch && canLaunch(err)):
+				stdout.ErrorAndExit(err)
+				return
+			case delegated:
+				// The window must be launched, which the daemon doesn't do
+				logger.LogInfo("Focusing without the daemon", "err", err)
+			}
+
+			switcher := aerospace.NewFocusSwitcher(storageClient, aerospaceClient)
+
+			var window *windows.Window
+			var windowID, focusedID int
+			launched := false
+			if shouldGoBack {
+				focusedID, err = switcher.Back()
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+			} else {
+				window, err = aerospace.NewMarkResolver(storageClient, aerospaceClient).ResolveMark(mark)
+				if err != nil {
+					if !shouldLaunch || !canLaunch(err) {
+						stdout.ErrorAndExit(err)
+						return
+					}
+
+					window, err = launchMarkedWindow(storageClient, aerospaceClient, mark, launchTimeout, err)
+					if err != nil {
+						stdout.ErrorAndExit(err)
+						return
+					}
+					launched = true
+				}
+				windowID = window.WindowID
+				logger.LogDebug("Window found", "windowID", windowID)
+
+				// The program is too fast, what a problem to have!
+				// Delay setting focus to ensure the window is ready
+				time.Sleep(focusDelay)
+				focusedID, err = switcher.Focus(windowID, !noToggle && !launched)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+			}
+
+			logger.LogDebug("Focus set", "windowID", focusedID)
+
+			event = format.OutputEvent{
 				Command:  "focus",
 				Action:   "focus",
-				WindowID: windowID,
+				WindowID: focusedID,
 				Result:   "success",
-				Message:  message,
+				Message:  fmt.Sprintf("Focus moved to window ID %d", focusedID),
+			}
+			if focusedID != windowID {
+				event.Action = "focus_back"
+				event.Message = fmt.Sprintf("Focus moved back to window ID %d", focusedID)
+			}
+			if launched {
+				event.Action = "launch"
+				event.AppName = window.AppName
+				event.Message = fmt.Sprintf("Launched window ID %d and moved focus to it", focusedID)
 			}
 
 			if formatErr := formatter.Format(event); formatErr != nil {
@@ -100,5 +187,51 @@ Output format can be controlled with --output flag (text, json, csv).
 		},
 	}
 
+	focusCmd.Flags().BoolP("back", "b", false, "Focus the window that had focus before the last focus switch")
+	focusCmd.Flags().Bool("no-toggle", false, "Keep focus on the marked window if it already has focus")
+	focusCmd.Flags().BoolP("launch", "l", false, "Run the launch command of the mark if its window is gone")
+	focusCmd.Flags().Duration("launch-timeout", launcher.DefaultTimeout, "How long to wait for the launched window")
+	focusCmd.MarkFlagsMutuallyExclusive("back", "no-toggle")
+	focusCmd.MarkFlagsMutuallyExclusive("back", "launch")
+
 	return focusCmd
 }
+
+// canLaunch reports whether focus --launch runs the launch command of a
+// mark that failed to resolve with err: its window or the mark is gone.
+func canLaunch(err error) bool {
+	kind := errkind.Of(err)
+	return kind == errkind.WindowGone || kind == errkind.MarkNotFound
+}
+
+// launchMarkedWindow runs the launch command of a mark whose window is gone,
+// then marks and returns the new window.
+func launchMarkedWindow(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	mark string,
+	timeout time.Duration,
+	resolveErr error,
+) (*windows.Window, error) {
+	markLauncher, err := storageClient.GetMarkLauncher(mark)
+	if err != nil {
+		return nil, err
+	}
+	if markLauncher == nil {
+		return nil, fmt.Errorf("%w (no launch command set for mark '%s')", resolveErr, mark)
+	}
+
+	appLauncher := launcher.New(aerospaceClient, launcher.GetDefaultRunner())
+	appLauncher.Timeout = timeout
+	window, err := appLauncher.Launch(markLauncher.Command, markLauncher.AppBundleID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = storageClient.ReplaceAllMarks(window.WindowID, mark, aerospace.NewWindowMetadata(*window))
+	if err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
@@ -1,19 +1,30 @@
 package cmd_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/launcher"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
 	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
 	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
 	"github.com/gkampitakis/go-snaps/snaps"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
 	aerospacecli "github.com/cristianoliveira/aerospace-ipc/pkg/client"
 )
 
@@ -52,11 +63,17 @@ func TestFocusCmd(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			GetWindowIDByMark("mark1").
-			Return(1, nil).
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
 			Times(1)
 
 		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
+		// Window 2 had focus before
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, aerospace.Window{WindowID: 2, AppName: "app2"}).Times(1)
+		strg.EXPECT().PushFocusHistory(2).Return(nil).Times(1)
 		mockAeroSpaceConnection.EXPECT().
 			SendCommand("focus", []string{"--window-id", "1"}).
 			Return(
@@ -90,8 +107,8 @@ func TestFocusCmd(t *testing.T) {
 		_, strg := mocks.MockStorageDBClient(ctrl)
 
 		strg.EXPECT().
-			GetWindowIDByMark("nonexistent-mark").
-			Return(0, nil).
+			GetWindowByMark("nonexistent-mark").
+			Return(nil, errors.New("no window found for mark nonexistent-mark")).
 			Times(1)
 
 		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
@@ -115,17 +132,160 @@ func TestFocusCmd(t *testing.T) {
 		snaps.MatchSnapshot(t, snapshot)
 	})
 
+	t.Run("prints a JSON error when the output is json", func(t *testing.T) {
+		args := []string{"focus", "nonexistent-mark", "--output", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		_, strg := mocks.MockStorageDBClient(ctrl)
+
+		notFound := errkind.New(errkind.MarkNotFound, "no window found for mark nonexistent-mark")
+		strg.EXPECT().
+			GetWindowByMark("nonexistent-mark").
+			Return(nil, notFound).
+			Times(1)
+
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("re-binds a stale mark to a live window of the same app", func(t *testing.T) {
+		args := []string{"focus", "term"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stale := queries.Mark{
+			WindowID:    10,
+			Mark:        "term",
+			AppBundleID: "io.alacritty",
+			AppName:     "Alacritty",
+			WindowTitle: "nvim",
+			Workspace:   "1",
+		}
+		windows := []aerospace.Window{
+			{WindowID: 20, WindowTitle: "zsh", AppName: "Alacritty", AppBundleID: "io.alacritty"},
+			{WindowID: 21, WindowTitle: "nvim", AppName: "Alacritty", AppBundleID: "io.alacritty", Workspace: "2"},
+			{WindowID: 22, WindowTitle: "nvim", AppName: "Brave", AppBundleID: "com.brave.Browser"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("term").
+			Return(&stale, nil).
+			Times(1)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().
+			RefreshMarkWindow("term", 21, storage.WindowMetadata{
+				AppBundleID: "io.alacritty",
+				AppName:     "Alacritty",
+				WindowTitle: "nvim",
+				Workspace:   "2",
+			}).
+			Return(nil).
+			Times(1)
+		// The tags of the closed window go along with the mark
+		strg.EXPECT().MoveWindowTags(stale.WindowID, 21).Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		strg.EXPECT().PushFocusHistory(20).Return(nil).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "21"}).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", []queries.Mark{stale}),
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails when the marked window is gone", func(t *testing.T) {
+		args := []string{"focus", "term"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stale := queries.Mark{
+			WindowID:    10,
+			Mark:        "term",
+			AppBundleID: "io.alacritty",
+			WindowTitle: "nvim",
+		}
+		windows := []aerospace.Window{
+			{WindowID: 22, WindowTitle: "nvim", AppName: "Brave", AppBundleID: "com.brave.Browser"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("term").
+			Return(&stale, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", []queries.Mark{stale}),
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
 	t.Run("outputs JSON format", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			GetWindowIDByMark("mark1").
-			Return(1, nil).
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
 			Times(1)
 
 		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
+		// Window 2 had focus before
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, aerospace.Window{WindowID: 2, AppName: "app2"}).Times(1)
+		strg.EXPECT().PushFocusHistory(2).Return(nil).Times(1)
 		mockAeroSpaceConnection.EXPECT().
 			SendCommand("focus", []string{"--window-id", "1"}).
 			Return(
@@ -162,11 +322,17 @@ func TestFocusCmd(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			GetWindowIDByMark("mark1").
-			Return(1, nil).
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
 			Times(1)
 
 		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
+		// Window 2 had focus before
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, aerospace.Window{WindowID: 2, AppName: "app2"}).Times(1)
+		strg.EXPECT().PushFocusHistory(2).Return(nil).Times(1)
 		mockAeroSpaceConnection.EXPECT().
 			SendCommand("focus", []string{"--window-id", "1"}).
 			Return(
@@ -197,3 +363,433 @@ func TestFocusCmd(t *testing.T) {
 		assert.Contains(t, lines[1], "Focus moved")
 	})
 }
+
+func TestFocusCmdWithDaemon(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	t.Cleanup(func() { daemon.SetDefaultClient(nil) })
+
+	t.Run("delegates focus to the daemon", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
+		// Window 2 had focus before
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, aerospace.Window{WindowID: 2, AppName: "app2"}).Times(1)
+		strg.EXPECT().PushFocusHistory(2).Return(nil).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "1"}).Times(1)
+
+		// The daemon owns the connections, the command is only a client
+		dir, err := os.MkdirTemp("", "marks")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		socketPath := filepath.Join(dir, "d.sock")
+
+		listener, err := daemon.Listen(socketPath)
+		require.NoError(t, err)
+		server := daemon.NewServer()
+		daemon.New(strg, aerospaceClient).Register(server)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = server.Serve(ctx, listener)
+		}()
+		defer func() {
+			cancel()
+			<-done
+		}()
+
+		daemon.SetDefaultClient(daemon.NewClient(socketPath))
+
+		args := []string{"focus", "mark1", "-o", "json"}
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("focuses directly when the daemon isn't running", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
+		// Window 2 had focus before
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, aerospace.Window{WindowID: 2, AppName: "app2"}).Times(1)
+		strg.EXPECT().PushFocusHistory(2).Return(nil).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "1"}).Times(1)
+
+		daemon.SetDefaultClient(
+			daemon.NewClient(filepath.Join(os.TempDir(), "aerospace-marks-missing.sock")),
+		)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, "focus", "mark1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "Focus moved to window ID 1\n", out)
+	})
+}
+
+func TestFocusCmdBackAndForth(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "title1", AppName: "app1", Workspace: "1"},
+		{WindowID: 5, WindowTitle: "title5", AppName: "app5", Workspace: "2"},
+	}
+
+	t.Run("goes back when the marked window already has focus - `focus mark1`", func(t *testing.T) {
+		args := []string{"focus", "mark1"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+		strg.EXPECT().GetPreviousFocus(1).Return(5, nil).Times(1)
+		strg.EXPECT().PushFocusHistory(1).Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "5"}).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("keeps focus with --no-toggle - `focus mark1 --no-toggle`", func(t *testing.T) {
+		args := []string{"focus", "mark1", "--no-toggle"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "1"}).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("focuses the previous window - `focus --back -o json`", func(t *testing.T) {
+		args := []string{"focus", "--back", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetPreviousFocus(5).Return(1, nil).Times(1)
+		strg.EXPECT().PushFocusHistory(5).Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[1]).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "1"}).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails to go back without history - `focus --back`", func(t *testing.T) {
+		args := []string{"focus", "--back"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetPreviousFocus(5).Return(0, nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[1]).Times(1)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("validate --back doesn't take a mark", func(t *testing.T) {
+		args := []string{"focus", "mark1", "--back"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
+
+// fakeRunner records launch commands instead of running them.
+type fakeRunner struct {
+	commands []string
+}
+
+func (r *fakeRunner) Run(command string) error {
+	r.commands = append(r.commands, command)
+	return nil
+}
+
+func TestFocusCmdLaunch(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	t.Cleanup(func() { launcher.SetDefaultRunner(&launcher.ShellRunner{}) })
+
+	stale := queries.Mark{
+		WindowID:    10,
+		Mark:        "term",
+		AppBundleID: "io.alacritty",
+		AppName:     "Alacritty",
+	}
+	markLauncher := queries.MarkLauncher{
+		Mark:        "term",
+		Command:     "open -a Alacritty",
+		AppBundleID: "io.alacritty",
+	}
+	before := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "title1", AppName: "Brave", AppBundleID: "com.brave.Browser", Workspace: "1"},
+	}
+
+	t.Run("launches the app when the window is gone - `focus term --launch`", func(t *testing.T) {
+		args := []string{"focus", "term", "--launch"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		runner := &fakeRunner{}
+		launcher.SetDefaultRunner(runner)
+
+		launched := aerospace.Window{
+			WindowID:    30,
+			WindowTitle: "zsh",
+			AppName:     "Alacritty",
+			AppBundleID: "io.alacritty",
+			Workspace:   "1",
+		}
+		after := append([]aerospace.Window{launched}, before...)
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetWindowByMark("term").Return(&stale, nil).Times(1)
+		strg.EXPECT().GetMarkLauncher("term").Return(&markLauncher, nil).Times(1)
+		strg.EXPECT().
+			ReplaceAllMarks(30, "term", storage.WindowMetadata{
+				AppBundleID: "io.alacritty",
+				AppName:     "Alacritty",
+				WindowTitle: "zsh",
+				Workspace:   "1",
+			}).
+			Return(int64(0), nil).
+			Times(1)
+		strg.EXPECT().PushFocusHistory(1).Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		// Once to resolve the mark, once before launching, then polling
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, before).Times(2)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, after).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, before[0]).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "30"}).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{"open -a Alacritty"}, runner.commands)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", []queries.Mark{stale}),
+				testutils.Context("windows", after),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails when the mark has no launch command", func(t *testing.T) {
+		args := []string{"focus", "term", "--launch"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		runner := &fakeRunner{}
+		launcher.SetDefaultRunner(runner)
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetWindowByMark("term").Return(&stale, nil).Times(1)
+		strg.EXPECT().GetMarkLauncher("term").Return(nil, nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, before).Times(1)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		assert.Empty(t, runner.commands)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("doesn't launch when the mark fails to resolve otherwise", func(t *testing.T) {
+		args := []string{"focus", "term", "--launch", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		runner := &fakeRunner{}
+		launcher.SetDefaultRunner(runner)
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("term").
+			Return(nil, errkind.New(errkind.Storage, "database is locked")).
+			Times(1)
+		strg.EXPECT().GetMarkLauncher(gomock.Any()).Times(0)
+
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		assert.Empty(t, runner.commands)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails when no window shows up in time", func(t *testing.T) {
+		args := []string{"focus", "term", "--launch", "--launch-timeout", "500ms"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		launcher.SetDefaultRunner(&fakeRunner{})
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetWindowByMark("term").Return(&stale, nil).Times(1)
+		strg.EXPECT().GetMarkLauncher("term").Return(&markLauncher, nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, before).AnyTimes()
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
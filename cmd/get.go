@@ -5,56 +5,42 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"strconv"
+	"slices"
 
 	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
 	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
 	"github.com/cristianoliveira/aerospace-marks/internal/format"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/spf13/cobra"
-)
 
-// formatSingleFieldOutput formats a single field output based on output format.
-// For json/csv formats, uses OutputEvent. For text/default, outputs plain value.
-//
-//nolint:unparam // fieldName is used conditionally for app_name field
-func formatSingleFieldOutput(
-	outputFormat string,
-	command string,
-	windowID int,
-	fieldValue string,
-	fieldName string,
-) error {
-	// text format or default - output plain value (backward compatibility)
-	if outputFormat == string(format.OutputFormatText) || outputFormat == "" {
-		fmt.Fprint(os.Stdout, fieldValue)
-		return nil
-	}
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
 
-	// json/csv formats - use OutputEvent
-	formatter, formatErr := format.NewOutputEventFormatter(os.Stdout, outputFormat)
-	if formatErr != nil {
-		return formatErr
-	}
-	event := format.OutputEvent{
-		Command:  command,
-		WindowID: windowID,
-		Result:   fieldValue,
-		Message:  fieldValue,
+// getFieldsFromFlags returns the fields selected with --fields or one of its
+// single field shortcuts.
+func getFieldsFromFlags(cmd *cobra.Command) []string {
+	shortcuts := []struct {
+		flag  string
+		field string
+	}{
+		{"window-id", "window_id"},
+		{"window-title", "window_title"},
+		{"app-name", "app_name"},
+		{"app-bundle-id", "app_bundle_id"},
 	}
-	if fieldName == "app_name" {
-		event.AppName = fieldValue
+	for _, shortcut := range shortcuts {
+		if enabled, _ := cmd.Flags().GetBool(shortcut.flag); enabled {
+			return []string{shortcut.field}
+		}
 	}
-	if fmtErr := formatter.Format(event); fmtErr != nil {
-		return fmt.Errorf("failed to format output: %w", fmtErr)
-	}
-	return nil
+
+	fields, _ := cmd.Flags().GetStringSlice("fields")
+	return fields
 }
 
-//nolint:gocognit,funlen // GetCmd has high complexity and length due to multiple field output options
 func GetCmd(
 	storageClient storage.MarkStorage,
 	aerospaceClient aerospace.AerosSpaceMarkWindows,
@@ -67,6 +53,10 @@ func GetCmd(
 This command retrieves a window by its mark (identifier). Print in the following format:
 
 <window_id> | <window_title> | <app_name>
+
+Select the fields to print with --fields, e.g. --fields mark,workspace.
+The single field flags (-i, -t, -a and -b) are shortcuts for it. A single
+field is printed bare in text format, without a new line.
 `,
 		Args: cobra.MatchAll(
 			cobra.ExactArgs(1),
@@ -90,93 +80,24 @@ This command retrieves a window by its mark (identifier). Print in the following
 				outputFormat = string(format.OutputFormatText)
 			}
 
-			markedWindow, err := storageClient.GetWindowByMark(mark)
+			window, err := getMarkedWindow(storageClient, aerospaceClient, mark)
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
 
-			windowID := markedWindow.WindowID
-			if windowID == 0 {
-				stdout.ErrorAndExit(fmt.Errorf("no window found for mark %s", mark))
-				return
-			}
+			windowID := window.WindowID
 
-			getWinID, _ := cmd.Flags().GetBool("window-id")
-			if getWinID {
-				// Single field flag - respect --output flag for json/csv, plain value for text/default
-				if formatErr := formatSingleFieldOutput(
-					outputFormat,
-					"get",
-					windowID,
-					strconv.Itoa(windowID),
-					"",
-				); formatErr != nil {
-					stdout.ErrorAndExit(formatErr)
-					return
-				}
-				return
-			}
-
-			window, err := aerospaceClient.GetWindowByID(windowID)
-			if err != nil {
-				stdout.ErrorAndExit(err)
-				return
-			}
-			if window == nil {
-				stdout.ErrorAndExit(fmt.Errorf("no window found for ID %d", windowID))
-				return
-			}
 			logger.LogDebug(
-				"Get window by ID",
+				"Get window by mark",
 				"windowID", windowID,
 				"window", *window,
 				"windowTitle", window.WindowTitle,
-				"err", err,
 			)
 
-			getWinTitle, _ := cmd.Flags().GetBool("window-title")
-			if getWinTitle {
-				// Single field flag - respect --output flag for json/csv, plain value for text/default
-				if formatErr := formatSingleFieldOutput(
-					outputFormat,
-					"get",
-					windowID,
-					window.WindowTitle,
-					"",
-				); formatErr != nil {
-					stdout.ErrorAndExit(formatErr)
-					return
-				}
-				return
-			}
-
-			getWinApp, _ := cmd.Flags().GetBool("app-name")
-			if getWinApp {
-				// Single field flag - respect --output flag for json/csv, plain value for text/default
-				if formatErr := formatSingleFieldOutput(
-					outputFormat,
-					"get",
-					windowID,
-					window.AppName,
-					"app_name",
-				); formatErr != nil {
-					stdout.ErrorAndExit(formatErr)
-					return
-				}
-				return
-			}
-
-			getWinAppBundleID, _ := cmd.Flags().GetBool("app-bundle-id")
-			if getWinAppBundleID {
-				// Single field flag - respect --output flag for json/csv, plain value for text/default
-				if formatErr := formatSingleFieldOutput(
-					outputFormat,
-					"get",
-					windowID,
-					window.AppBundleID,
-					"",
-				); formatErr != nil {
+			if fields := getFieldsFromFlags(cmd); len(fields) > 0 {
+				formatErr := formatWindowFields(cmd, outputFormat, storageClient, mark, window, fields)
+				if formatErr != nil {
 					stdout.ErrorAndExit(formatErr)
 					return
 				}
@@ -184,7 +105,7 @@ This command retrieves a window by its mark (identifier). Print in the following
 			}
 
 			// Full window output - use OutputEvent
-			formatter, err := format.NewOutputEventFormatter(os.Stdout, outputFormat)
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
@@ -217,10 +138,76 @@ This command retrieves a window by its mark (identifier). Print in the following
 		},
 	}
 
-	getCmd.Flags().BoolP("window-id", "i", false, "Get only window [i]D")
-	getCmd.Flags().BoolP("window-title", "t", false, "Get only window [t]itle")
-	getCmd.Flags().BoolP("app-name", "a", false, "Get only window [a]pp name")
-	getCmd.Flags().BoolP("app-bundle-id", "b", false, "Get only window app [b]undle ID")
+	getCmd.Flags().BoolP("window-id", "i", false, "Get only window [i]D (--fields window_id)")
+	getCmd.Flags().BoolP("window-title", "t", false, "Get only window [t]itle (--fields window_title)")
+	getCmd.Flags().BoolP("app-name", "a", false, "Get only window [a]pp name (--fields app_name)")
+	getCmd.Flags().
+		BoolP("app-bundle-id", "b", false, "Get only window app [b]undle ID (--fields app_bundle_id)")
+	enableFieldsFlag(getCmd, format.MarkedWindowFields())
+	getCmd.MarkFlagsMutuallyExclusive(
+		"fields",
+		"window-id",
+		"window-title",
+		"app-name",
+		"app-bundle-id",
+	)
 
 	return getCmd
 }
+
+// getMarkedWindow returns the live window of a mark, from the daemon when
+// commands delegate to it.
+func getMarkedWindow(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	mark string,
+) (*windows.Window, error) {
+	var markedWindow format.MarkedWindow
+	delegated, err := callDaemon(daemon.MethodGet, daemon.MarkParams{Mark: mark}, &markedWindow)
+	if !delegated {
+		return aerospace.NewMarkResolver(storageClient, aerospaceClient).ResolveMark(mark)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &windows.Window{
+		WindowID:    markedWindow.WindowID,
+		WindowTitle: markedWindow.WindowTitle,
+		AppName:     markedWindow.AppName,
+		AppBundleID: markedWindow.AppBundleID,
+		Workspace:   markedWindow.Workspace,
+	}, nil
+}
+
+// formatWindowFields writes the selected fields of the marked window.
+func formatWindowFields(
+	cmd *cobra.Command,
+	outputFormat string,
+	storageClient storage.MarkStorage,
+	mark string,
+	window *windows.Window,
+	fields []string,
+) error {
+	formatter, err := newListOutputFormatter(cmd, outputFormat, format.WithFields(fields))
+	if err != nil {
+		return err
+	}
+
+	markedWindow := aerospace.NewMarkedWindow(mark, window)
+
+	// Tags are only looked up when asked for
+	if slices.Contains(fields, "tags") {
+		tagsByWindow, tagsErr := aerospace.TagsByWindow(storageClient, []windows.Window{*window})
+		if tagsErr != nil {
+			return tagsErr
+		}
+		markedWindow.Tags = tagsByWindow[window.WindowID]
+	}
+
+	if formatErr := formatter.FormatWindow(markedWindow); formatErr != nil {
+		return fmt.Errorf("failed to format output: %w", formatErr)
+	}
+
+	return nil
+}
@@ -118,7 +118,7 @@ func TestGetCommand(t *testing.T) {
 			Return(&marks[0], nil).
 			Times(1)
 
-		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		connectionMock, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
 		windows := []aerospace.Window{
 			{
 				WindowID:    1,
@@ -136,6 +136,7 @@ func TestGetCommand(t *testing.T) {
 				AppName:     "app3",
 			},
 		}
+		mocks.ExpectGetAllWindows(connectionMock, windows).Times(1)
 
 		cmd := cmd.NewRootCmd(strg, aerospaceClient)
 		out, err := testutils.CmdExecute(cmd, args...)
@@ -443,8 +444,8 @@ func TestGetCommand(t *testing.T) {
 		assert.Contains(t, lines[1], ",title1")
 	})
 
-	t.Run("single field with JSON format uses OutputEvent", func(t *testing.T) {
-		// Single field flags (-i, -t, -a, -b) with --output json/csv use OutputEvent format
+	t.Run("single field with JSON format outputs only the field", func(t *testing.T) {
+		// Single field flags (-i, -t, -a, -b) are shortcuts for --fields
 		args := []string{"get", "mark1", "--window-id", "-o", "json"}
 
 		ctrl := gomock.NewController(t)
@@ -463,20 +464,20 @@ func TestGetCommand(t *testing.T) {
 			Return(&marks[0], nil).
 			Times(1)
 
-		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		connectionMock, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(connectionMock, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
 
 		cmd := cmd.NewRootCmd(strg, aerospaceClient)
 		out, err := testutils.CmdExecute(cmd, args...)
 		require.NoError(t, err)
 
 		result := strings.TrimSpace(out)
-		// Should output OutputEvent JSON structure
 		var jsonResult map[string]interface{}
 		err = json.Unmarshal([]byte(result), &jsonResult)
 		require.NoError(t, err)
-		assert.Equal(t, "get", jsonResult["command"])
-		assert.InDelta(t, 1.0, jsonResult["window_id"], 0.0)
-		assert.Equal(t, "1", jsonResult["result"])
+		assert.Equal(t, map[string]interface{}{"window_id": 1.0}, jsonResult)
 	})
 
 	t.Run("single field without output flag outputs plain value", func(t *testing.T) {
@@ -499,7 +500,10 @@ func TestGetCommand(t *testing.T) {
 			Return(&marks[0], nil).
 			Times(1)
 
-		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		connectionMock, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(connectionMock, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
 
 		cmd := cmd.NewRootCmd(strg, aerospaceClient)
 		out, err := testutils.CmdExecute(cmd, args...)
@@ -851,3 +855,163 @@ func TestGetCommand(t *testing.T) {
 		snaps.MatchSnapshot(t, snapshot)
 	})
 }
+
+func TestGetCommandTemplate(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	args := []string{
+		"get", "mark1", "-o", "template",
+		"--template", "{{.WindowID}} {{.AppName}} {{.Message | truncate 8}}",
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "a long window title", AppName: "app1", Workspace: "1"},
+	}
+
+	_, strg := mocks.MockStorageDBClient(ctrl)
+	strg.EXPECT().
+		GetWindowByMark("mark1").
+		Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+		Times(1)
+
+	mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+	mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+	rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+	out, err := testutils.CmdExecute(rootCmd, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+		Command: testutils.CommandString(args...),
+		Stdout:  out,
+		Contexts: []testutils.SnapshotContext{
+			testutils.Context("windows", windows),
+		},
+	})
+	snaps.MatchSnapshot(t, snapshot)
+}
+
+func TestGetCommandFields(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "GitHub", AppName: "Brave Browser", Workspace: "web"},
+	}
+	tags := []queries.WindowTag{
+		{WindowID: 1, Tag: "work"},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		withTags bool
+	}{
+		{"text", []string{"get", "mark1", "--fields", "mark,workspace"}, false},
+		{"single field text is bare", []string{"get", "mark1", "--fields", "workspace"}, false},
+		{"json object", []string{"get", "mark1", "--fields", "mark,workspace", "-o", "json"}, false},
+		{"tags", []string{"get", "mark1", "--fields", "window_id,tags", "-o", "json"}, true},
+		{"shortcut", []string{"get", "mark1", "-t", "-o", "csv"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" - `"+strings.Join(tt.args, " ")+"`", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().
+				GetWindowByMark("mark1").
+				Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+				Times(1)
+			if tt.withTags {
+				strg.EXPECT().GetTags().Return(tags, nil).Times(1)
+			}
+
+			mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tt.args...),
+				Stdout:  out,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("windows", windows),
+					testutils.Context("tags", tags),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	t.Run("shortcuts and --fields are mutually exclusive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecute(rootCmd, "get", "mark1", "-i", "--fields", "mark")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "none of the others can be")
+	})
+}
+
+func TestGetCommandAutoPrune(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	t.Setenv("AEROSPACE_MARKS_AUTO_PRUNE", "true")
+
+	marks := []queries.Mark{
+		{WindowID: 1, Mark: "live"},
+		{WindowID: 10, Mark: "dead", AppName: "Zoom", AppBundleID: "us.zoom.xos"},
+	}
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+	}
+	args := []string{"get", "live"}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, strg := mocks.MockStorageDBClient(ctrl)
+	strg.EXPECT().GetWindowByMark("live").Return(&marks[0], nil).Times(1)
+	gomock.InOrder(
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1),
+		strg.EXPECT().GetMarks().Return(marks[:1], nil).Times(1),
+	)
+	mocks.ExpectTransaction(strg).Times(2)
+	strg.EXPECT().DeleteByWindow(10).Return(int64(1), nil).Times(1)
+	// The tags of the closed window are pruned along with its marks
+	strg.EXPECT().GetTags().Return([]queries.WindowTag{
+		{WindowID: 1, Tag: "work"},
+		{WindowID: 10, Tag: "meeting"},
+	}, nil).Times(1)
+	strg.EXPECT().DeleteWindowTag(10, "meeting").Return(int64(1), nil).Times(1)
+
+	conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+	mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+	rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+	out, err := testutils.CmdExecute(rootCmd, args...)
+	require.NoError(t, err)
+
+	snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+		Command: testutils.CommandString(args...),
+		Stdout:  out,
+		Contexts: []testutils.SnapshotContext{
+			testutils.Context("marks", marks),
+			testutils.Context("windows", windows),
+		},
+	})
+	snaps.MatchSnapshot(t, snapshot)
+}
@@ -0,0 +1,256 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// SummonGroupCmd represents the summon-group command.
+//
+//nolint:funlen // SummonGroupCmd moves and optionally focuses the group
+func SummonGroupCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	summonGroupCmd := &cobra.Command{
+		Use:   "summon-group <tag> [flags]",
+		Short: "Move every window with a tag to the current workspace",
+		Long: `Move every window with a tag to the current workspace
+
+Tagged windows that no longer exist are skipped.
+With --focus, the first tagged window gets focus afterwards.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+
+Example:
+
+  aerospace-marks summon-group meeting --focus
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateArgIsNotEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+			tag := args[0]
+			logger.LogDebug("SummonGroupCmd called", "tag", tag)
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			shouldFocus, err := cmd.Flags().GetBool("focus")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			formatter, err := newListOutputFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			tagged, err := aerospace.FindTaggedWindows(storageClient, aerospaceClient, tag)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			if len(tagged) == 0 {
+				stdout.ErrorAndExit(
+					errkind.Errorf(errkind.TagNotFound, "no live window tagged with '%s'", tag),
+				)
+				return
+			}
+
+			client, err := aerospaceClient.Client()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			workspace, err := client.Workspaces().GetFocusedWorkspace()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			for _, window := range tagged {
+				if window.Workspace == workspace.Workspace {
+					continue
+				}
+
+				err = moveWindowToWorkspace(aerospaceClient, window.WindowID, workspace.Workspace)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+				logger.LogDebug("Tagged window summoned", "windowID", window.WindowID)
+			}
+
+			if shouldFocus {
+				_, err = aerospace.NewFocusSwitcher(storageClient, aerospaceClient).
+					Focus(tagged[0].WindowID, false)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+			}
+
+			summoned := taggedMarkedWindows(tagged, tag, workspace.Workspace)
+			if formatErr := formatter.Format(summoned); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+
+			if outputFormat == string(format.OutputFormatText) {
+				fmt.Fprintf(
+					os.Stdout,
+					"Summoned %d windows tagged '%s' to workspace %s\n",
+					len(tagged),
+					tag,
+					workspace.Workspace,
+				)
+			}
+		},
+	}
+
+	summonGroupCmd.Flags().BoolP("focus", "f", false, "Focus the first tagged window after summoning")
+
+	return summonGroupCmd
+}
+
+// FocusGroupCmd represents the focus-group command.
+func FocusGroupCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	focusGroupCmd := &cobra.Command{
+		Use:   "focus-group <tag> [flags]",
+		Short: "Focus every window with a tag",
+		Long: `Focus every window with a tag
+
+Focuses the tagged windows one after the other, so each of them is brought
+to front on its workspace (e.g. one per monitor). The first tagged window
+ends up with focus, and 'focus --back' returns to the window focused before.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateArgIsNotEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			tag := args[0]
+			logger.GetDefaultLogger().LogDebug("FocusGroupCmd called", "tag", tag)
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			formatter, err := newListOutputFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			tagged, err := aerospace.FindTaggedWindows(storageClient, aerospaceClient, tag)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			if len(tagged) == 0 {
+				stdout.ErrorAndExit(
+					errkind.Errorf(errkind.TagNotFound, "no live window tagged with '%s'", tag),
+				)
+				return
+			}
+
+			// Last to first, so the first tagged window ends up focused. Only the
+			// first switch is recorded, to go back to where the group was focused from
+			last := len(tagged) - 1
+			_, err = aerospace.NewFocusSwitcher(storageClient, aerospaceClient).
+				Focus(tagged[last].WindowID, false)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			client, err := aerospaceClient.Client()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			for i := last - 1; i >= 0; i-- {
+				err = client.Focus().SetFocusByWindowID(tagged[i].WindowID)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+			}
+
+			if formatErr := formatter.Format(taggedMarkedWindows(tagged, tag, "")); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+
+			if outputFormat == string(format.OutputFormatText) {
+				fmt.Fprintf(os.Stdout, "Focused %d windows tagged '%s'\n", len(tagged), tag)
+			}
+		},
+	}
+
+	return focusGroupCmd
+}
+
+// taggedMarkedWindows converts tagged windows for the list formatter. When
+// workspace is set, it replaces the workspace of every window.
+func taggedMarkedWindows(
+	tagged []windows.Window,
+	tag string,
+	workspace string,
+) []format.MarkedWindow {
+	markedWindows := make([]format.MarkedWindow, 0, len(tagged))
+	for _, window := range tagged {
+		if workspace != "" {
+			window.Workspace = workspace
+		}
+
+		markedWindows = append(markedWindows, format.MarkedWindow{
+			WindowID:    window.WindowID,
+			AppName:     window.AppName,
+			WindowTitle: window.WindowTitle,
+			Workspace:   window.Workspace,
+			AppBundleID: window.AppBundleID,
+			Tags:        []string{tag},
+		})
+	}
+
+	return markedWindows
+}
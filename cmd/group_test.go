@@ -0,0 +1,185 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+// meetingTags returns the windows tagged with "meeting", in tagging order.
+func meetingTags(windowIDs ...int) []queries.WindowTag {
+	tags := make([]queries.WindowTag, 0, len(windowIDs))
+	for _, windowID := range windowIDs {
+		tags = append(tags, queries.WindowTag{WindowID: windowID, Tag: "meeting"})
+	}
+
+	return tags
+}
+
+func TestSummonGroupCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "Meeting", AppName: "zoom.us", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "notes", AppName: "Notes", Workspace: "2"},
+		{WindowID: 3, WindowTitle: "Calendar", AppName: "Calendar", Workspace: "3"},
+	}
+
+	t.Run("moves the live tagged windows - `summon-group meeting`", func(t *testing.T) {
+		args := []string{"summon-group", "meeting"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		// Window 9 is gone, window 3 is already on the focused workspace
+		strg.EXPECT().GetWindowTagsByTag("meeting").Return(meetingTags(1, 9, 3), nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(mockAeroSpaceConnection, "3").Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"3", "--window-id", "1"},
+		).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("focuses the first tagged window - `summon-group meeting --focus -o json`", func(t *testing.T) {
+		args := []string{"summon-group", "meeting", "--focus", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetWindowTagsByTag("meeting").Return(meetingTags(1, 2), nil).Times(1)
+		strg.EXPECT().PushFocusHistory(3).Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(mockAeroSpaceConnection, "3").Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"3", "--window-id", "1"},
+		).Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"3", "--window-id", "2"},
+		).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[2]).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "1"}).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails when no tagged window is alive", func(t *testing.T) {
+		args := []string{"summon-group", "meeting"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetWindowTagsByTag("meeting").Return(meetingTags(9), nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
+
+func TestFocusGroupCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "Meeting", AppName: "zoom.us", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "notes", AppName: "Notes", Workspace: "2"},
+		{WindowID: 3, WindowTitle: "Calendar", AppName: "Calendar", Workspace: "3"},
+	}
+
+	t.Run("focuses the first tagged window last - `focus-group meeting`", func(t *testing.T) {
+		args := []string{"focus-group", "meeting"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetWindowTagsByTag("meeting").Return(meetingTags(1, 2), nil).Times(1)
+		strg.EXPECT().PushFocusHistory(3).Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[2]).Times(1)
+		gomock.InOrder(
+			mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "2"}),
+			mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "1"}),
+		)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
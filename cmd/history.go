@@ -0,0 +1,199 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/spf13/cobra"
+)
+
+// historyReport is the journal of mark changes printed by history.
+type historyReport struct {
+	Changes []historyChange `json:"changes" yaml:"changes"`
+}
+
+type historyChange struct {
+	ID        int64  `json:"id" yaml:"id"`
+	Operation string `json:"operation" yaml:"operation"`
+	// TargetID is the change reverted by an undo or applied again by a redo
+	TargetID  int64          `json:"target_id,omitempty" yaml:"target_id,omitempty"`
+	CreatedAt string         `json:"created_at" yaml:"created_at"`
+	Undone    bool           `json:"undone" yaml:"undone"`
+	Marks     []historyEntry `json:"marks" yaml:"marks"`
+}
+
+// historyEntry is the state of a mark before and after a change, a missing
+// state means the mark didn't exist.
+type historyEntry struct {
+	Mark     string        `json:"mark" yaml:"mark"`
+	Previous *historyState `json:"previous" yaml:"previous"`
+	Current  *historyState `json:"current" yaml:"current"`
+}
+
+type historyState struct {
+	WindowID    int    `json:"window_id" yaml:"window_id"`
+	AppBundleID string `json:"app_bundle_id" yaml:"app_bundle_id"`
+	AppName     string `json:"app_name" yaml:"app_name"`
+	WindowTitle string `json:"window_title" yaml:"window_title"`
+	Workspace   string `json:"workspace" yaml:"workspace"`
+}
+
+// HistoryCmd prints the journal of mark changes.
+func HistoryCmd(storageClient storage.MarkStorage) *cobra.Command {
+	historyCmd := &cobra.Command{
+		Use:   "history [flags]",
+		Short: "Show the recent changes of the marks",
+		Long: `Show the recent changes of the marks
+
+Every change of the marks is recorded in a journal, with the state of each
+changed mark before and after it. Prints the most recent changes first, the
+ones reverted by undo are flagged as undone.
+
+Marks are shown as +mark when added, -mark when removed and mark when moved to
+another window or refreshed.
+
+Example:
+
+  aerospace-marks history
+  aerospace-marks history --limit 5 --output json
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputFlag, _ := cmd.Flags().GetString("output")
+			outputFormat, err := format.ParseDocumentFormat(outputFlag)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			limit, _ := cmd.Flags().GetInt("limit")
+			if limit < 0 {
+				stdout.ErrorAndExit(errkind.Errorf(
+					errkind.InvalidInput,
+					"invalid limit %d, expected 0 or more",
+					limit,
+				))
+				return
+			}
+
+			changes, err := storageClient.GetHistory(limit)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			if outputFormat == format.OutputFormatText {
+				if len(changes) == 0 {
+					fmt.Fprintln(os.Stdout, "No changes")
+				}
+				for _, change := range changes {
+					line := changeText(change)
+					if change.Undone {
+						line += " (undone)"
+					}
+					fmt.Fprintln(os.Stdout, line)
+				}
+				return
+			}
+
+			report := historyReport{Changes: make([]historyChange, 0, len(changes))}
+			for _, change := range changes {
+				report.Changes = append(report.Changes, newHistoryChange(change))
+			}
+			if err = format.WriteDocument(os.Stdout, outputFormat, report); err != nil {
+				stdout.ErrorAndExit(err)
+			}
+		},
+	}
+
+	historyCmd.Flags().IntP("limit", "n", 20, "Number of changes to show, 0 shows all")
+	enableDocumentOutputFlag(historyCmd)
+
+	return historyCmd
+}
+
+func newHistoryChange(change storage.JournalChange) historyChange {
+	entries := make([]historyEntry, 0, len(change.Entries))
+	for _, entry := range change.Entries {
+		historyEntry := historyEntry{Mark: entry.Mark}
+		if entry.PreviousWindowID != 0 {
+			historyEntry.Previous = &historyState{
+				WindowID:    entry.PreviousWindowID,
+				AppBundleID: entry.PreviousAppBundleID,
+				AppName:     entry.PreviousAppName,
+				WindowTitle: entry.PreviousWindowTitle,
+				Workspace:   entry.PreviousWorkspace,
+			}
+		}
+		if entry.WindowID != 0 {
+			historyEntry.Current = &historyState{
+				WindowID:    entry.WindowID,
+				AppBundleID: entry.AppBundleID,
+				AppName:     entry.AppName,
+				WindowTitle: entry.WindowTitle,
+				Workspace:   entry.Workspace,
+			}
+		}
+		entries = append(entries, historyEntry)
+	}
+
+	return historyChange{
+		ID:        change.ID,
+		Operation: change.Operation,
+		TargetID:  change.TargetID,
+		CreatedAt: change.CreatedAt.Format(time.RFC3339),
+		Undone:    change.Undone,
+		Marks:     entries,
+	}
+}
+
+// changeText describes a change in a line, e.g.
+// "#3 2025-06-01 10:00:00 delete: -term (window 12)".
+func changeText(change storage.JournalChange) string {
+	operation := change.Operation
+	if change.TargetID != 0 {
+		operation = fmt.Sprintf("%s #%d", operation, change.TargetID)
+	}
+
+	marks := make([]string, 0, len(change.Entries))
+	for _, entry := range change.Entries {
+		marks = append(marks, describeJournalEntry(entry))
+	}
+
+	return fmt.Sprintf(
+		"#%d %s %s: %s",
+		change.ID,
+		change.CreatedAt.Format(time.DateTime),
+		operation,
+		strings.Join(marks, ", "),
+	)
+}
+
+// describeJournalEntry describes the change of a mark, e.g. "+term (window 3)"
+// when added or "term (window 3 -> 4)" when moved to another window.
+func describeJournalEntry(entry queries.MarkJournal) string {
+	switch {
+	case entry.PreviousWindowID == 0:
+		return fmt.Sprintf("+%s (window %d)", entry.Mark, entry.WindowID)
+	case entry.WindowID == 0:
+		return fmt.Sprintf("-%s (window %d)", entry.Mark, entry.PreviousWindowID)
+	case entry.PreviousWindowID != entry.WindowID:
+		return fmt.Sprintf(
+			"%s (window %d -> %d)",
+			entry.Mark,
+			entry.PreviousWindowID,
+			entry.WindowID,
+		)
+	default:
+		return fmt.Sprintf("%s (window %d)", entry.Mark, entry.WindowID)
+	}
+}
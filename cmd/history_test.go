@@ -0,0 +1,123 @@
+package cmd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// journal is a history of mark changes, the most recent first.
+func journal() []storage.JournalChange {
+	at := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	return []storage.JournalChange{
+		{
+			ID:        4,
+			Operation: storage.OperationUndo,
+			TargetID:  3,
+			CreatedAt: at.Add(3 * time.Minute),
+			Entries: []queries.MarkJournal{
+				{Mark: "mail", WindowID: 2, AppName: "Mail"},
+				{Mark: "term", WindowID: 1, AppName: "Alacritty", Workspace: "1"},
+			},
+		},
+		{
+			ID:        3,
+			Operation: "delete_all",
+			CreatedAt: at.Add(2 * time.Minute),
+			Undone:    true,
+			Entries: []queries.MarkJournal{
+				{Mark: "mail", PreviousWindowID: 2, PreviousAppName: "Mail"},
+				{
+					Mark:              "term",
+					PreviousWindowID:  1,
+					PreviousAppName:   "Alacritty",
+					PreviousWorkspace: "1",
+				},
+			},
+		},
+		{
+			ID:        2,
+			Operation: "replace",
+			CreatedAt: at.Add(time.Minute),
+			Entries: []queries.MarkJournal{
+				{Mark: "mail", PreviousWindowID: 5, WindowID: 2, AppName: "Mail"},
+			},
+		},
+		{
+			ID:        1,
+			Operation: "add",
+			CreatedAt: at,
+			Entries: []queries.MarkJournal{
+				{Mark: "term", WindowID: 1, AppName: "Alacritty", Workspace: "1"},
+			},
+		},
+	}
+}
+
+func TestHistoryCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	for _, tc := range []struct {
+		args  []string
+		limit int
+	}{
+		{args: []string{"history"}, limit: 20},
+		{args: []string{"history", "--limit", "2", "--output", "json"}, limit: 2},
+		{args: []string{"history", "-n", "0", "-o", "yaml"}, limit: 0},
+	} {
+		t.Run(testutils.CommandString(tc.args...), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			changes := journal()
+			if tc.limit > 0 && len(changes) > tc.limit {
+				changes = changes[:tc.limit]
+			}
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().GetHistory(tc.limit).Return(changes, nil).Times(1)
+			_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tc.args...)
+			require.NoError(t, err)
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tc.args...),
+				Stdout:  out,
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	t.Run("prints when there are no changes", func(t *testing.T) {
+		args := []string{"history"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetHistory(20).Return(nil, nil).Times(1)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
@@ -0,0 +1,367 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// Statuses of an imported mark.
+const (
+	importImported = "imported"
+	importConflict = "conflict"
+	importSkipped  = "skipped"
+	importFailed   = "failed"
+)
+
+// importResult is what happened to a mark of the imported document.
+type importResult struct {
+	Mark     string `json:"mark" yaml:"mark"`
+	Status   string `json:"status" yaml:"status"`
+	WindowID int    `json:"window_id" yaml:"window_id"`
+	AppName  string `json:"app_name" yaml:"app_name"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+type importReport struct {
+	DryRun  bool           `json:"dry_run" yaml:"dry_run"`
+	Mode    string         `json:"mode" yaml:"mode"`
+	Removed int64          `json:"removed" yaml:"removed"`
+	Results []importResult `json:"results" yaml:"results"`
+}
+
+// count returns how many marks ended with the given status.
+func (r importReport) count(status string) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// ImportCmd reads marks written by export and binds them to live windows.
+func ImportCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	importCmd := &cobra.Command{
+		Use:   "import <file> [flags]",
+		Short: "Import marks from a document written by export",
+		Long: `Import marks from a document written by export
+
+Reads a JSON or YAML document written by export, "-" reads it from stdin, and
+binds each mark to a live window: the window with the same ID when it is of the
+same app, otherwise the window of the same app and title. Marks without a live
+window are skipped, so open the apps before importing.
+
+With --merge (default) the imported marks are added to the current ones, with
+--replace the current marks are removed first, unless no mark would be imported.
+Marks that are already set are reported as conflicts and skipped, the rest is
+still imported.
+
+Use --dry-run to only report what would be imported.
+
+Example:
+
+  aerospace-marks import marks.json --dry-run
+  aerospace-marks import marks.yaml --replace
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			outputFlag, _ := cmd.Flags().GetString("output")
+			outputFormat, err := format.ParseDocumentFormat(outputFlag)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			replace, _ := cmd.Flags().GetBool("replace")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			document, err := readMarksDocument(cmd.InOrStdin(), args[0])
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			client, err := aerospaceClient.Client()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			windowsList, err := client.Windows().GetAllWindows()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			report, err := importMarks(storageClient, document, windowsList, replace, dryRun)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			if outputFormat == format.OutputFormatText {
+				writeImportText(report)
+			} else if err = format.WriteDocument(os.Stdout, outputFormat, report); err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			if failed := report.count(importFailed); failed > 0 {
+				stdout.ErrorAndExit(
+					errkind.Errorf(errkind.Storage, "failed to import %d marks", failed),
+				)
+			}
+		},
+	}
+
+	importCmd.Flags().Bool("merge", false, "Add the imported marks to the current ones (default)")
+	importCmd.Flags().Bool("replace", false, "Remove the current marks first, unless none is imported")
+	importCmd.Flags().Bool("dry-run", false, "Only report what would be imported")
+	importCmd.MarkFlagsMutuallyExclusive("merge", "replace")
+	enableDocumentOutputFlag(importCmd)
+
+	return importCmd
+}
+
+// readMarksDocument reads a JSON or YAML document written by export from
+// path, or from stdin when path is "-".
+func readMarksDocument(stdin io.Reader, path string) (*marksDocument, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if path == "-" {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, errkind.Errorf(errkind.InvalidInput, "failed to read '%s': %w", path, err)
+	}
+
+	var document marksDocument
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		err = json.Unmarshal(content, &document)
+	} else {
+		err = yaml.Unmarshal(content, &document)
+	}
+	if err != nil {
+		return nil, errkind.Errorf(
+			errkind.InvalidInput,
+			"invalid marks document '%s': %w",
+			path,
+			err,
+		)
+	}
+	if document.Version != marksDocumentVersion {
+		return nil, errkind.Errorf(
+			errkind.InvalidInput,
+			"unsupported marks document version %d in '%s', expected %d",
+			document.Version,
+			path,
+			marksDocumentVersion,
+		)
+	}
+
+	return &document, nil
+}
+
+// importMarks adds the marks of document bound to live windows. Conflicts
+// and marks without a live window are reported per mark, not as errors.
+// With replace, the current marks are removed first unless no mark would be
+// imported.
+func importMarks(
+	storageClient storage.MarkStorage,
+	document *marksDocument,
+	windowsList []windows.Window,
+	replace bool,
+	dryRun bool,
+) (importReport, error) {
+	report := importReport{
+		DryRun: dryRun,
+		Mode:   "merge",
+	}
+	if replace {
+		report.Mode = "replace"
+	}
+
+	err := storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		currentMarks, err := tx.GetMarks()
+		if err != nil {
+			return err
+		}
+
+		taken := make(map[string]bool, len(currentMarks)+len(document.Marks))
+		if replace {
+			// The current marks are only removed when a mark replaces them
+			planned := importEntries(tx, document, windowsList, map[string]bool{}, true)
+			if (importReport{Results: planned}).count(importImported) == 0 {
+				report.Results = planned
+				return nil
+			}
+
+			report.Removed = int64(len(currentMarks))
+			if !dryRun {
+				if report.Removed, err = tx.DeleteAllMarks(); err != nil {
+					return err
+				}
+			}
+		} else {
+			for _, mark := range currentMarks {
+				taken[mark.Mark] = true
+			}
+		}
+
+		report.Results = importEntries(tx, document, windowsList, taken, dryRun)
+		return nil
+	})
+
+	return report, err
+}
+
+// importEntries imports every mark of document, see importMark.
+func importEntries(
+	storageClient storage.MarkStorage,
+	document *marksDocument,
+	windowsList []windows.Window,
+	taken map[string]bool,
+	dryRun bool,
+) []importResult {
+	results := make([]importResult, 0, len(document.Marks))
+	for _, entry := range document.Marks {
+		result := importMark(storageClient, entry, windowsList, taken, dryRun)
+		if result.Status == importImported {
+			taken[entry.Mark] = true
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func importMark(
+	storageClient storage.MarkStorage,
+	entry exportedMark,
+	windowsList []windows.Window,
+	taken map[string]bool,
+	dryRun bool,
+) importResult {
+	result := importResult{Mark: entry.Mark, AppName: entry.AppName}
+
+	switch {
+	case strings.TrimSpace(entry.Mark) == "":
+		result.Status = importSkipped
+		result.Message = "empty mark"
+		return result
+	case taken[entry.Mark]:
+		result.Status = importConflict
+		result.Message = fmt.Sprintf("mark '%s' is already set", entry.Mark)
+		return result
+	}
+
+	window := findImportedWindow(entry, windowsList)
+	if window == nil {
+		result.Status = importSkipped
+		result.Message = fmt.Sprintf("no live window of %s", appOf(entry))
+		return result
+	}
+
+	result.WindowID = window.WindowID
+	result.AppName = window.AppName
+	result.Status = importImported
+	result.Message = fmt.Sprintf("bound to window %d", window.WindowID)
+	if dryRun {
+		return result
+	}
+
+	err := storageClient.AddMark(window.WindowID, entry.Mark, aerospace.NewWindowMetadata(*window))
+	switch {
+	case errors.Is(err, storage.ErrMarkExists):
+		result.Status = importConflict
+		result.Message = fmt.Sprintf("mark '%s' is already set", entry.Mark)
+	case err != nil:
+		result.Status = importFailed
+		result.Message = err.Error()
+	}
+
+	return result
+}
+
+// findImportedWindow finds the live window of an exported mark. The window
+// with the exported ID is only trusted when it is of the same app, IDs of
+// another machine or session can belong to any window.
+func findImportedWindow(entry exportedMark, windowsList []windows.Window) *windows.Window {
+	for i := range windowsList {
+		sameApp := entry.AppBundleID == "" || windowsList[i].AppBundleID == entry.AppBundleID
+		if entry.WindowID != 0 && windowsList[i].WindowID == entry.WindowID && sameApp {
+			return &windowsList[i]
+		}
+	}
+
+	return aerospace.FindWindowByMetadata(queries.Mark{
+		Mark:        entry.Mark,
+		WindowID:    entry.WindowID,
+		AppBundleID: entry.AppBundleID,
+		WindowTitle: entry.WindowTitle,
+	}, windowsList)
+}
+
+// appOf names the app of an exported mark for messages.
+func appOf(entry exportedMark) string {
+	switch {
+	case entry.AppName != "":
+		return entry.AppName
+	case entry.AppBundleID != "":
+		return entry.AppBundleID
+	default:
+		return fmt.Sprintf("window %d", entry.WindowID)
+	}
+}
+
+func writeImportText(report importReport) {
+	for _, result := range report.Results {
+		fmt.Fprintf(os.Stdout, "%-8s %s: %s\n", result.Status, result.Mark, result.Message)
+	}
+
+	verb := "Imported"
+	if report.DryRun {
+		verb = "Would import"
+	}
+	summary := fmt.Sprintf(
+		"%s %d marks, %d conflicts, %d skipped",
+		verb,
+		report.count(importImported),
+		report.count(importConflict),
+		report.count(importSkipped),
+	)
+	if failed := report.count(importFailed); failed > 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+	}
+	if report.Mode == "replace" {
+		summary += fmt.Sprintf(" (replacing %d marks)", report.Removed)
+	}
+
+	fmt.Fprintln(os.Stdout, summary)
+}
@@ -0,0 +1,217 @@
+package cmd_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+func TestImportCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	document := `{
+  "version": 1,
+  "marks": [
+    {
+      "mark": "term",
+      "window_id": 10,
+      "app_bundle_id": "io.alacritty",
+      "app_name": "Alacritty",
+      "window_title": "nvim"
+    },
+    {"mark": "mail", "window_id": 2, "app_bundle_id": "com.apple.mail", "app_name": "Mail"},
+    {"mark": "web", "window_id": 3, "app_bundle_id": "com.brave.Browser", "app_name": "Brave"},
+    {"mark": "zoom", "window_id": 4, "app_bundle_id": "us.zoom.xos", "app_name": "Zoom"}
+  ]
+}`
+	windows := []aerospace.Window{
+		{WindowID: 2, WindowTitle: "inbox", AppName: "Mail", AppBundleID: "com.apple.mail"},
+		{WindowID: 3, WindowTitle: "docs", AppName: "Brave", AppBundleID: "com.brave.Browser"},
+		// The exported window ID 10 now belongs to another app
+		{WindowID: 10, WindowTitle: "notes", AppName: "Notes", AppBundleID: "com.apple.Notes"},
+		{WindowID: 22, WindowTitle: "nvim", AppName: "Alacritty", AppBundleID: "io.alacritty"},
+	}
+	currentMarks := []queries.Mark{{WindowID: 3, Mark: "web"}}
+
+	writeDocument := func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		require.NoError(t, os.WriteFile("marks.json", []byte(document), 0o600))
+	}
+
+	t.Run("merges marks and reports conflicts - `import marks.json`", func(t *testing.T) {
+		args := []string{"import", "marks.json"}
+		writeDocument(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().GetMarks().Return(currentMarks, nil).Times(1)
+		strg.EXPECT().
+			AddMark(22, "term", storage.WindowMetadata{
+				AppBundleID: "io.alacritty",
+				AppName:     "Alacritty",
+				WindowTitle: "nvim",
+			}).
+			Return(nil).
+			Times(1)
+		// Marked by another command since the marks were read
+		strg.EXPECT().
+			AddMark(2, "mail", gomock.Any()).
+			Return(fmt.Errorf("%w: 'mail'", storage.ErrMarkExists)).
+			Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("current marks", currentMarks),
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("reports a replace without changes - `import marks.json --replace --dry-run -o json`",
+		func(t *testing.T) {
+			args := []string{"import", "marks.json", "--replace", "--dry-run", "-o", "json"}
+			writeDocument(t)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			mocks.ExpectTransaction(strg).Times(1)
+			strg.EXPECT().GetMarks().Return(currentMarks, nil).Times(1)
+
+			conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, args...)
+			require.NoError(t, err)
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(args...),
+				Stdout:  out,
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+
+	t.Run("replaces marks with YAML from stdin - `import - --replace`", func(t *testing.T) {
+		args := []string{"import", "-", "--replace"}
+		yamlDocument := `version: 1
+marks:
+  - mark: mail
+    window_id: 2
+    app_bundle_id: com.apple.mail
+  - mark: mail
+    window_id: 3
+    app_bundle_id: com.brave.Browser
+`
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().GetMarks().Return(currentMarks, nil).Times(1)
+		strg.EXPECT().DeleteAllMarks().Return(int64(1), nil).Times(1)
+		strg.EXPECT().AddMark(2, "mail", gomock.Any()).Return(nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecuteWithStdin(rootCmd, yamlDocument, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("stdin", yamlDocument),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("keeps the current marks when nothing is imported - `import - --replace`",
+		func(t *testing.T) {
+			args := []string{"import", "-", "--replace"}
+			// Window 4 is gone and window 10 belongs to another app
+			staleDocument := `{"version": 1, "marks": [
+  {"mark": "zoom", "window_id": 4, "app_bundle_id": "us.zoom.xos"},
+  {"mark": "term", "window_id": 10, "app_bundle_id": "io.alacritty", "window_title": "vim"}
+]}`
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			mocks.ExpectTransaction(strg).Times(1)
+			strg.EXPECT().GetMarks().Return(currentMarks, nil).Times(1)
+			strg.EXPECT().DeleteAllMarks().Times(0)
+			strg.EXPECT().AddMark(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecuteWithStdin(rootCmd, staleDocument, args...)
+			require.NoError(t, err)
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(args...),
+				Stdout:  out,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("stdin", staleDocument),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+
+	t.Run("fails with an unsupported document version", func(t *testing.T) {
+		args := []string{"import", "-"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecuteWithStdin(rootCmd, `{"version": 2, "marks": []}`, args...)
+		require.Error(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
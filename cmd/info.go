@@ -9,42 +9,60 @@ import (
 
 	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
 	"github.com/cristianoliveira/aerospace-marks/internal/constants"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/spf13/cobra"
 )
 
+// infoReport is the configuration shown by the info command.
+type infoReport struct {
+	Socket   infoSocket       `json:"socket" yaml:"socket"`
+	Database infoDatabase     `json:"database" yaml:"database"`
+	Logging  logger.LogConfig `json:"logging" yaml:"logging"`
+}
+
+type infoSocket struct {
+	Path       string `json:"path" yaml:"path"`
+	Version    string `json:"version" yaml:"version"`
+	Compatible bool   `json:"compatible" yaml:"compatible"`
+	Status     string `json:"status" yaml:"status"`
+}
+
+type infoDatabase struct {
+	Name string `json:"name" yaml:"name"`
+	Path string `json:"path" yaml:"path"`
+	// MigrationVersion is the version of the last migration applied
+	MigrationVersion int64  `json:"migration_version" yaml:"migration_version"`
+	Status           string `json:"status" yaml:"status"`
+}
+
 // InfoCmd represents the config command.
 func InfoCmd(
 	storageClient storage.MarkStorage,
 	aerospaceClient aerospace.AerosSpaceMarkWindows,
 ) *cobra.Command {
-	return &cobra.Command{
+	infoCmd := &cobra.Command{
 		Use:   "info",
 		Short: "Displays aerospace-marks config information",
 		Long: `Displays the config information of aerospace-marks.
 
 This command allows you to view the current configurations for the aerospace-marks CLI.
 It also displays help information about environment variables available.
+
+Use --output json or --output yaml for the configuration without the help.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			logConfig := logger.GetDefaultLogger().GetConfig()
-			dbConfig := storageClient.Client().GetStorageConfig()
-			client := aerospaceClient.Client().Connection()
-			socketPath, err := client.GetSocketPath()
+			outputFlag, _ := cmd.Flags().GetString("output")
+			outputFormat, err := format.ParseDocumentFormat(outputFlag)
 			if err != nil {
-				return fmt.Errorf("failed to get socket path: %w", err)
+				return err
 			}
 
-			var validationInfo string
-			if err = client.CheckServerVersion(); err != nil {
-				validationInfo = "Incompatible. Reason: " + err.Error()
-			} else {
-				validationInfo = "Compatible."
-			}
-			serverVersion, err := client.GetServerVersion()
-			if err != nil {
-				return fmt.Errorf("failed to get server version: %w", err)
+			report := newInfoReport(storageClient, aerospaceClient)
+
+			if outputFormat != format.OutputFormatText {
+				return format.WriteDocument(os.Stdout, outputFormat, report)
 			}
 
 			fmt.Fprintf(os.Stdout, `Aerospace Marks CLI - Configuration
@@ -57,6 +75,8 @@ Status: %s
 [Database]
 Name: %s
 Path: %s
+Migration: %d
+Status: %s
 
 [Logging]
 Path: %s
@@ -67,27 +87,98 @@ Configure with ENV variables:
 %s - Path to database directory.
 %s - Log level [debug|info|warn|error] (default: disabled)
 %s - Path to the logs file.
+%s - Remove marks of closed windows when listing [true|false] (default: false)
+%s - Path to the daemon socket, commands delegate to the daemon when set.
+%s - Workspace for hidden scratchpad windows (default: scratchpad)
 `,
-				socketPath,
-				serverVersion,
-				validationInfo,
+				report.Socket.Path,
+				report.Socket.Version,
+				report.Socket.Status,
 
 				// Database configuration
-				dbConfig.DBName,
-				dbConfig.DBPath,
+				report.Database.Name,
+				report.Database.Path,
+				report.Database.MigrationVersion,
+				report.Database.Status,
 
 				// logging configuration
-				logConfig.Path,
-				logConfig.Level,
+				report.Logging.Path,
+				report.Logging.Level,
 
 				// Environment variables
 				constants.EnvAeroSpaceSock,
 				constants.EnvAeroSpaceMarksDBPath,
 				constants.EnvAeroSpaceMarksLogsLevel,
 				constants.EnvAeroSpaceMarksLogsPath,
+				constants.EnvAeroSpaceMarksAutoPrune,
+				constants.EnvAeroSpaceMarksSocket,
+				constants.EnvAeroSpaceMarksScratchpadWorkspace,
 			)
 
 			return nil
 		},
 	}
+
+	enableDocumentOutputFlag(infoCmd)
+
+	return infoCmd
+}
+
+// newInfoReport collects the configuration of aerospace-marks. AeroSpace or
+// a database that can't be reached is reported in its status rather than
+// failing.
+func newInfoReport(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *infoReport {
+	dbClient := storageClient.Client()
+	dbConfig := dbClient.GetStorageConfig()
+	database := infoDatabase{
+		Name:   dbConfig.DBName,
+		Path:   dbConfig.DBPath,
+		Status: "Available.",
+	}
+	migrationVersion, err := dbClient.GetVersion()
+	if err != nil {
+		database.Status = "Unavailable. Reason: " + err.Error()
+	}
+	database.MigrationVersion = migrationVersion
+
+	return &infoReport{
+		Socket:   newInfoSocket(aerospaceClient),
+		Database: database,
+		Logging:  logger.GetDefaultLogger().GetConfig(),
+	}
+}
+
+func newInfoSocket(aerospaceClient aerospace.AerosSpaceMarkWindows) infoSocket {
+	socket := infoSocket{Path: "unknown", Version: "unknown"}
+	aerospaceWM, err := aerospaceClient.Client()
+	if err != nil {
+		socket.Status = "Unavailable. Reason: " + err.Error()
+		return socket
+	}
+
+	client := aerospaceWM.Connection()
+	socketPath, err := client.GetSocketPath()
+	if err != nil {
+		socket.Status = "Unavailable. Reason: failed to get socket path: " + err.Error()
+		return socket
+	}
+
+	socket.Path = socketPath
+	socket.Compatible = true
+	socket.Status = "Compatible."
+	if err = client.CheckServerVersion(); err != nil {
+		socket.Compatible = false
+		socket.Status = "Incompatible. Reason: " + err.Error()
+	}
+	socket.Version, err = client.GetServerVersion()
+	if err != nil {
+		socket.Version = "unknown"
+		socket.Compatible = false
+		socket.Status = "Unavailable. Reason: " + err.Error()
+	}
+
+	return socket
 }
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
 	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
@@ -36,6 +38,12 @@ func TestInfoCmd(t *testing.T) {
 			}).
 			Times(1)
 
+		dbClient.
+			EXPECT().
+			GetVersion().
+			Return(int64(6), nil).
+			Times(1)
+
 		gomock.InOrder(
 			aerospaceConnection.
 				EXPECT().
@@ -94,6 +102,12 @@ func TestInfoCmd(t *testing.T) {
 			}).
 			Times(1)
 
+		dbClient.
+			EXPECT().
+			GetVersion().
+			Return(int64(6), nil).
+			Times(1)
+
 		gomock.InOrder(
 			aerospaceConnection.
 				EXPECT().
@@ -130,7 +144,7 @@ func TestInfoCmd(t *testing.T) {
 		snaps.MatchSnapshot(tt, snapshot)
 	})
 
-	t.Run("Failure - to retrieve socket path", func(tt *testing.T) {
+	t.Run("Reports a socket path that can't be retrieved", func(tt *testing.T) {
 		ctrl := gomock.NewController(tt)
 		defer ctrl.Finish()
 
@@ -152,28 +166,131 @@ func TestInfoCmd(t *testing.T) {
 			}).
 			Times(1)
 
-		gomock.InOrder(
-			aerospaceConnection.
-				EXPECT().
-				GetSocketPath().
-				Return("", errors.New("missing socket path")).
-				Times(1),
-		)
+		dbClient.
+			EXPECT().
+			GetVersion().
+			Return(int64(6), nil).
+			Times(1)
+
+		aerospaceConnection.
+			EXPECT().
+			GetSocketPath().
+			Return("", errors.New("missing socket path")).
+			Times(1)
 
 		cmd := cmd.InfoCmd(
 			storageClient,
 			aerospaceClient,
 		)
 		out, err := testutils.CmdExecute(cmd)
-		if err == nil {
+		if err != nil {
 			tt.Fatal(err)
 		}
 
 		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
 			Command: fmt.Sprintf("aerospace-marks %s", cmd.Use),
 			Stdout:  out,
-			Stderr:  err.Error(),
 		})
 		snaps.MatchSnapshot(tt, snapshot)
 	})
+
+	t.Run("Reports AeroSpace and a database that can't be reached", func(tt *testing.T) {
+		ctrl := gomock.NewController(tt)
+		defer ctrl.Finish()
+
+		dbClient, storageClient := mocks.MockStorageDBClient(ctrl)
+		storageClient.EXPECT().Client().Return(dbClient).Times(1)
+		dbClient.EXPECT().
+			GetStorageConfig().
+			Return(storage.StorageConfig{DBPath: "/tmp/database/", DBName: "foo.db"}).
+			Times(1)
+		dbClient.EXPECT().
+			GetVersion().
+			Return(int64(0), errors.New("unable to open database file")).
+			Times(1)
+
+		aerospaceClient := &testutils.MockUnavailableAerospaceMarkWindows{
+			Err: errkind.New(errkind.IPCUnavailable, "dial unix /tmp/foo.sock: no such file"),
+		}
+
+		cmd := cmd.InfoCmd(storageClient, aerospaceClient)
+		out, err := testutils.CmdExecute(cmd, "-o", "json")
+		if err != nil {
+			tt.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString("info", "-o", "json"),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(tt, snapshot)
+	})
+}
+
+func TestInfoCmdOutput(t *testing.T) {
+	mockInfo := func(
+		tt *testing.T,
+		serverVersionErr error,
+	) (storage.MarkStorage, aerospace.AerosSpaceMarkWindows) {
+		ctrl := gomock.NewController(tt)
+		tt.Cleanup(ctrl.Finish)
+
+		aerospaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		dbClient, storageClient := mocks.MockStorageDBClient(ctrl)
+
+		storageClient.EXPECT().Client().Return(dbClient).Times(1)
+		dbClient.EXPECT().
+			GetStorageConfig().
+			Return(storage.StorageConfig{DBPath: "/tmp/database/", DBName: "foo.db"}).
+			Times(1)
+		dbClient.EXPECT().GetVersion().Return(int64(6), nil).Times(1)
+
+		aerospaceConnection.EXPECT().GetSocketPath().Return("/tmp/foo.sock", nil).Times(1)
+		aerospaceConnection.EXPECT().CheckServerVersion().Return(nil).Times(1)
+		aerospaceConnection.EXPECT().
+			GetServerVersion().
+			Return("aerospace-ipc v0.1.0", serverVersionErr).
+			Times(1)
+
+		return storageClient, aerospaceClient
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		serverVersionErr error
+	}{
+		{"json", []string{"--output", "json"}, nil},
+		{"yaml", []string{"-o", "yaml"}, nil},
+		{"reports an unavailable server version", []string{}, errors.New("connection reset")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tt *testing.T) {
+			storageClient, aerospaceClient := mockInfo(tt, tc.serverVersionErr)
+
+			infoCmd := cmd.InfoCmd(storageClient, aerospaceClient)
+			out, err := testutils.CmdExecute(infoCmd, tc.args...)
+			if err != nil {
+				tt.Fatal(err)
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(append([]string{"info"}, tc.args...)...),
+				Stdout:  out,
+			})
+			snaps.MatchSnapshot(tt, snapshot)
+		})
+	}
+
+	t.Run("fails with an unsupported output format", func(tt *testing.T) {
+		infoCmd := cmd.InfoCmd(nil, nil)
+		_, err := testutils.CmdExecute(infoCmd, "--output", "csv")
+		if err == nil {
+			tt.Fatal("expected an error")
+		}
+		if errkind.Of(err) != errkind.InvalidInput {
+			tt.Fatalf("expected an invalid input error, got %v", err)
+		}
+	})
 }
@@ -4,38 +4,17 @@ Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
 package cmd
 
 import (
-	"errors"
 	"fmt"
-	"os"
-
-	"slices"
+	"strings"
 
 	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
 	"github.com/cristianoliveira/aerospace-marks/internal/format"
 	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/spf13/cobra"
-
-	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
 )
 
-func popWindow(windowsList []windows.Window, windowID int) (*windows.Window, error) {
-	for i, window := range windowsList {
-		if windowID == 0 {
-			return nil, errors.New("window ID not found")
-		}
-		if windowID == window.WindowID {
-			// Remove the window from the list
-			// The assignment is intentional to show intent, even though the slice is passed by value
-			//nolint:staticcheck,ineffassign,wastedassign // intentional: shows removal intent
-			windowsList = slices.Delete(windowsList, i, i+1)
-			return &window, nil
-		}
-	}
-
-	return nil, errors.New("window ID not found")
-}
-
 // ListCmd represents the list command.
 //
 //nolint:gocognit // ListCmd has high complexity due to multiple formatting operations
@@ -50,10 +29,33 @@ func ListCmd(
 		Long: `List all marked windows
 
 This command lists all marked windows with their respective marks.
-Display format can be controlled with --output flag (text, json, csv).
+Display format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
 
 Default format (text):
-<mark>|<window-id>|<app-name>|<window-title>|<workspace>|<app-bundle-id>
+<mark>|<window-id>|<app-name>|<window-title>|<workspace>|<app-bundle-id>|<tags>
+
+Tags are comma separated, see 'aerospace-marks tag'.
+
+Each mark is a row, so a window with several marks shows up several times.
+Use --by-window to list each window once with all of its marks:
+<marks>|<window-id>|<app-name>|<window-title>|<workspace>|<app-bundle-id>|<tags>
+
+Marks can be filtered with --workspace, --focused-workspace, --app,
+--bundle-id and --mark (a glob like 'web:*' or a regex like '/^web:/'),
+and sorted with --sort (mark, app, workspace or window-id).
+
+Select the columns with --fields, e.g. --fields mark,workspace. JSON, NDJSON
+and YAML objects only have the selected keys. With --by-window the mark
+field is named marks.
+
+On a terminal, window titles are truncated to fit its width (or COLUMNS)
+and, with --color auto, marks and workspaces are colored. Add a header row
+with --header.
+
+Example:
+
+  aerospace-marks list --focused-workspace --sort mark
+  aerospace-marks list --fields mark,workspace
 	`,
 		Run: func(cmd *cobra.Command, args []string) {
 			// Get and validate output format early
@@ -68,58 +70,55 @@ Default format (text):
 				outputFormat = string(format.OutputFormatText)
 			}
 
-			// Validate format before any processing
-			formatter, err := format.NewListOutputFormatter(os.Stdout, outputFormat)
+			byWindow, err := cmd.Flags().GetBool("by-window")
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
 
-			// Get marks from storage
-			marks, err := storageClient.GetMarks()
+			// Validate format before any processing
+			formatter, err := newListOutputFormatter(cmd, outputFormat)
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
 
-			// Handle empty marks based on format
-			if len(marks) == 0 {
-				if formatErr := formatter.FormatEmpty("No marks found"); formatErr != nil {
-					stdout.ErrorAndExit(fmt.Errorf("failed to format empty output: %w", formatErr))
-					return
-				}
+			if err = formatter.ValidateFields(byWindow); err != nil {
+				stdout.ErrorAndExit(err)
 				return
 			}
 
-			// Get windows from Aerospace
-			windowsList, err := aerospaceClient.Client().Windows().GetAllWindows()
+			filter, sortKey, err := listFilterFromFlags(cmd, aerospaceClient)
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
 
-			// Collect marked windows
-			markedWindows := make([]format.MarkedWindow, 0)
-			for _, mark := range marks {
-				window, popErr := popWindow(windowsList, mark.WindowID)
-				if popErr != nil {
-					// Silently skip windows that no longer exist
-					continue
+			markedWindows, err := listMarkedWindows(storageClient, aerospaceClient)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			// Handle empty marks based on format
+			if markedWindows == nil {
+				if formatErr := formatEmptyList(formatter, byWindow, "No marks found"); formatErr != nil {
+					stdout.ErrorAndExit(fmt.Errorf("failed to format empty output: %w", formatErr))
+					return
 				}
+				return
+			}
 
-				markedWindows = append(markedWindows, format.MarkedWindow{
-					Mark:        mark.Mark,
-					WindowID:    window.WindowID,
-					AppName:     window.AppName,
-					WindowTitle: window.WindowTitle,
-					Workspace:   window.Workspace,
-					AppBundleID: window.AppBundleID,
-				})
+			markedWindows = filter.apply(markedWindows)
+			if sortErr := sortMarkedWindows(markedWindows, sortKey); sortErr != nil {
+				stdout.ErrorAndExit(sortErr)
+				return
 			}
 
 			// Handle empty marked windows based on format
 			if len(markedWindows) == 0 {
-				if formatErr := formatter.FormatEmpty("No marked window found"); formatErr != nil {
+				formatErr := formatEmptyList(formatter, byWindow, "No marked window found")
+				if formatErr != nil {
 					stdout.ErrorAndExit(fmt.Errorf("failed to format empty output: %w", formatErr))
 					return
 				}
@@ -127,6 +126,13 @@ Default format (text):
 			}
 
 			// Format and output
+			if byWindow {
+				formatErr := formatter.FormatByWindow(format.GroupByWindow(markedWindows))
+				if formatErr != nil {
+					stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				}
+				return
+			}
 			if formatErr := formatter.Format(markedWindows); formatErr != nil {
 				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
 				return
@@ -134,5 +140,76 @@ Default format (text):
 		},
 	}
 
+	listCmd.Flags().Bool("by-window", false, "List each window once with all of its marks")
+	listCmd.Flags().String("workspace", "", "Only list marks of windows on this workspace")
+	listCmd.Flags().Bool("focused-workspace", false, "Only list marks on the focused workspace")
+	listCmd.Flags().String("app", "", "Only list marks of windows of this app (case insensitive)")
+	listCmd.Flags().String("bundle-id", "", "Only list marks of windows of this app bundle ID")
+	listCmd.Flags().String(
+		"mark",
+		"",
+		"Only list marks matching a glob ('web:*') or a regex ('/^web:/')",
+	)
+	listCmd.Flags().String(
+		"sort",
+		"",
+		"Sort by "+strings.Join(listSortKeys(), ", ")+" (default: order of creation)",
+	)
+	listCmd.MarkFlagsMutuallyExclusive("workspace", "focused-workspace")
+	enableFieldsFlag(listCmd, format.MarkedWindowFields())
+	enableTableFlags(listCmd)
+
 	return listCmd
 }
+
+// listMarkedWindows returns the marked windows that are still alive, one
+// per mark, from the daemon when commands delegate to it. Returns nil when
+// there is no mark at all.
+func listMarkedWindows(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) ([]format.MarkedWindow, error) {
+	var delegatedWindows []format.MarkedWindow
+	delegated, err := callDaemon(daemon.MethodList, nil, &delegatedWindows)
+	if delegated {
+		if err != nil {
+			return nil, err
+		}
+		// The daemon only lists alive windows, marks of other windows may be
+		// left, so an empty list isn't "no marks"
+		if delegatedWindows == nil {
+			delegatedWindows = []format.MarkedWindow{}
+		}
+		return delegatedWindows, nil
+	}
+
+	marks, err := storageClient.GetMarks()
+	if err != nil || len(marks) == 0 {
+		return nil, err
+	}
+
+	client, err := aerospaceClient.Client()
+	if err != nil {
+		return nil, err
+	}
+	windowsList, err := client.Windows().GetAllWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	if aerospace.IsAutoPruneEnabled() {
+		aerospace.AutoPrune(storageClient, marks, windowsList)
+	}
+
+	return aerospace.MarkedWindows(storageClient, marks, windowsList)
+}
+
+// formatEmptyList writes the empty output of list, with the by-window
+// columns when byWindow is set.
+func formatEmptyList(formatter *format.ListOutputFormatter, byWindow bool, message string) error {
+	if byWindow {
+		return formatter.FormatByWindowEmpty(message)
+	}
+
+	return formatter.FormatEmpty(message)
+}
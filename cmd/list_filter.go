@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/spf13/cobra"
+)
+
+const (
+	listSortMark      = "mark"
+	listSortApp       = "app"
+	listSortWorkspace = "workspace"
+	listSortWindowID  = "window-id"
+)
+
+// listFilter keeps the marked windows matching every field that is set.
+type listFilter struct {
+	workspace   string
+	app         string
+	appBundleID string
+	mark        func(mark string) bool
+}
+
+// listFilterFromFlags builds the filter and sort key from the flags of list.
+func listFilterFromFlags(
+	cmd *cobra.Command,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) (listFilter, string, error) {
+	filter := listFilter{}
+	filter.workspace, _ = cmd.Flags().GetString("workspace")
+	filter.app, _ = cmd.Flags().GetString("app")
+	filter.appBundleID, _ = cmd.Flags().GetString("bundle-id")
+
+	// Sorting nothing validates the key before any processing
+	sortKey, _ := cmd.Flags().GetString("sort")
+	if err := sortMarkedWindows(nil, sortKey); err != nil {
+		return filter, "", err
+	}
+
+	markPattern, _ := cmd.Flags().GetString("mark")
+	if markPattern != "" {
+		match, err := markMatcher(markPattern)
+		if err != nil {
+			return filter, "", err
+		}
+		filter.mark = match
+	}
+
+	focusedWorkspace, _ := cmd.Flags().GetBool("focused-workspace")
+	if focusedWorkspace {
+		client, err := aerospaceClient.Client()
+		if err != nil {
+			return filter, "", err
+		}
+		workspace, err := client.Workspaces().GetFocusedWorkspace()
+		if err != nil {
+			return filter, "", err
+		}
+		filter.workspace = workspace.Workspace
+	}
+
+	return filter, sortKey, nil
+}
+
+// apply returns the marked windows matching the filter.
+func (f listFilter) apply(windows []format.MarkedWindow) []format.MarkedWindow {
+	filtered := make([]format.MarkedWindow, 0, len(windows))
+	for _, w := range windows {
+		if f.workspace != "" && w.Workspace != f.workspace {
+			continue
+		}
+		// App names are shown capitalized, don't make users match the case
+		if f.app != "" && !strings.EqualFold(w.AppName, f.app) {
+			continue
+		}
+		if f.appBundleID != "" && w.AppBundleID != f.appBundleID {
+			continue
+		}
+		if f.mark != nil && !f.mark(w.Mark) {
+			continue
+		}
+		filtered = append(filtered, w)
+	}
+
+	return filtered
+}
+
+// sortMarkedWindows sorts the marked windows in place by the given key,
+// then by mark. An empty key keeps the storage order.
+func sortMarkedWindows(windows []format.MarkedWindow, key string) error {
+	var less func(a, b format.MarkedWindow) bool
+	switch key {
+	case "":
+		return nil
+	case listSortMark:
+		// Ties are sorted by mark below
+		less = func(_, _ format.MarkedWindow) bool { return false }
+	case listSortApp:
+		less = func(a, b format.MarkedWindow) bool {
+			return strings.ToLower(a.AppName) < strings.ToLower(b.AppName)
+		}
+	case listSortWorkspace:
+		less = func(a, b format.MarkedWindow) bool { return a.Workspace < b.Workspace }
+	case listSortWindowID:
+		less = func(a, b format.MarkedWindow) bool { return a.WindowID < b.WindowID }
+	default:
+		return errkind.Errorf(
+			errkind.InvalidInput,
+			"invalid sort '%s', expected one of: %s",
+			key,
+			strings.Join(listSortKeys(), ", "),
+		)
+	}
+
+	sort.SliceStable(windows, func(i, j int) bool {
+		if less(windows[i], windows[j]) {
+			return true
+		}
+		if less(windows[j], windows[i]) {
+			return false
+		}
+		return windows[i].Mark < windows[j].Mark
+	})
+
+	return nil
+}
+
+// listSortKeys returns the keys accepted by --sort.
+func listSortKeys() []string {
+	return []string{listSortMark, listSortApp, listSortWorkspace, listSortWindowID}
+}
@@ -3,6 +3,8 @@ package cmd_test
 import (
 	"encoding/csv"
 	"encoding/json"
+	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -28,6 +30,10 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return([]queries.WindowTag{
+			{WindowID: 1, Tag: "meeting"},
+			{WindowID: 1, Tag: "work"},
+		}, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -99,8 +105,8 @@ func TestListCommand(t *testing.T) {
 		lines := strings.Split(result, "\n")
 		assert.Len(t, lines, 2)
 		assert.Equal(t, []string{
-			"mark1 | 1 | app1 | title1 | _ | _",
-			"mark2 | 2 | app2 | title2 | _ | _",
+			"mark1 | 1 | app1 | title1 | _ | _ | meeting,work",
+			"mark2 | 2 | app2 | title2 | _ | _ | _",
 		}, lines)
 	})
 
@@ -109,6 +115,7 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -213,6 +220,7 @@ func TestListCommand(t *testing.T) {
 			t.Fatal(err)
 		}
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(marks, nil).
@@ -265,6 +273,7 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -330,6 +339,7 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -382,8 +392,8 @@ func TestListCommand(t *testing.T) {
 		result := strings.TrimSpace(out)
 		lines := strings.Split(result, "\n")
 		assert.Len(t, lines, 2)
-		assert.Equal(t, "mark,window_id,app_name,window_title,workspace,app_bundle_id", lines[0])
-		assert.Equal(t, "mark1,1,app1,title1,workspace1,bundle1", lines[1])
+		assert.Equal(t, "mark,window_id,app_name,window_title,workspace,app_bundle_id,tags", lines[0])
+		assert.Equal(t, "mark1,1,app1,title1,workspace1,bundle1,", lines[1])
 	})
 
 	t.Run("defaults to text format", func(t *testing.T) {
@@ -391,6 +401,7 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -489,7 +500,7 @@ func TestListCommand(t *testing.T) {
 		}
 
 		result := strings.TrimSpace(out)
-		assert.Equal(t, "mark,window_id,app_name,window_title,workspace,app_bundle_id", result)
+		assert.Equal(t, "mark,window_id,app_name,window_title,workspace,app_bundle_id,tags", result)
 	})
 
 	t.Run("invalid output format returns error", func(t *testing.T) {
@@ -519,6 +530,7 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -582,6 +594,7 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -648,6 +661,7 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -715,7 +729,7 @@ func TestListCommand(t *testing.T) {
 		for _, line := range lines {
 			assert.Contains(t, line, "|")
 			fields := strings.Split(line, "|")
-			assert.Len(t, fields, 6, "Each line should have 6 fields")
+			assert.Len(t, fields, 7, "Each line should have 7 fields")
 		}
 	})
 
@@ -724,6 +738,7 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -801,6 +816,7 @@ func TestListCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(
@@ -867,6 +883,7 @@ func TestListCommand(t *testing.T) {
 			"window_title",
 			"workspace",
 			"app_bundle_id",
+			"tags",
 		}
 		assert.Equal(t, expectedHeader, records[0])
 		// Verify all rows have same number of columns as header
@@ -917,6 +934,7 @@ func TestListCommand(t *testing.T) {
 			t.Fatal(err)
 		}
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(marks, nil).
@@ -975,6 +993,7 @@ func TestListCommand(t *testing.T) {
 			t.Fatal(err)
 		}
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(marks, nil).
@@ -1033,6 +1052,7 @@ func TestListCommand(t *testing.T) {
 			t.Fatal(err)
 		}
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
 		strg.EXPECT().
 			GetMarks().
 			Return(marks, nil).
@@ -1080,3 +1100,573 @@ func TestListCommand(t *testing.T) {
 		snaps.MatchSnapshot(t, snapshot)
 	})
 }
+
+func TestListCommandAutoPrune(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{WindowID: 1, Mark: "live"},
+		{WindowID: 10, Mark: "dead", AppName: "Zoom", AppBundleID: "us.zoom.xos"},
+	}
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+	}
+
+	t.Run("removes orphaned marks when auto prune is enabled", func(t *testing.T) {
+		t.Setenv("AEROSPACE_MARKS_AUTO_PRUNE", "true")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		// Once to prune the tags, once to list them
+		strg.EXPECT().GetTags().Return(nil, nil).Times(2)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+		strg.EXPECT().DeleteByWindow(10).Return(int64(1), nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+		args := []string{"list"}
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", marks),
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("keeps orphaned marks when auto prune is disabled", func(t *testing.T) {
+		t.Setenv("AEROSPACE_MARKS_AUTO_PRUNE", "false")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+		strg.EXPECT().DeleteByWindow(gomock.Any()).Times(0)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecute(rootCmd, "list")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestListCommandMultipleMarks(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	// Window 1 has 3 marks and window 2 has 4, interleaved with window 3
+	marks := []queries.Mark{
+		{WindowID: 1, Mark: "term"},
+		{WindowID: 2, Mark: "web"},
+		{WindowID: 1, Mark: "t"},
+		{WindowID: 3, Mark: "notes"},
+		{WindowID: 2, Mark: "w"},
+		{WindowID: 1, Mark: "shell"},
+		{WindowID: 2, Mark: "docs"},
+		{WindowID: 2, Mark: "jira"},
+		{WindowID: 9, Mark: "gone"},
+	}
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "zsh", AppName: "Alacritty", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "GitHub", AppName: "Brave Browser", Workspace: "2"},
+		{WindowID: 3, WindowTitle: "todo", AppName: "Notes", Workspace: "3"},
+	}
+	tags := []queries.WindowTag{
+		{WindowID: 2, Tag: "work"},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"one row per mark", []string{"list"}},
+		{"one row per mark as JSON", []string{"list", "-o", "json"}},
+		{"one row per window", []string{"list", "--by-window"}},
+		{"one row per window as JSON", []string{"list", "--by-window", "-o", "json"}},
+		{"one row per window as CSV", []string{"list", "--by-window", "-o", "csv"}},
+		{"one row per mark as NDJSON", []string{"list", "-o", "ndjson"}},
+		{"one row per window as YAML", []string{"list", "--by-window", "-o", "yaml"}},
+		{"one row per mark as TSV", []string{"list", "-o", "tsv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" - `"+strings.Join(tt.args, " ")+"`", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+			strg.EXPECT().GetTags().Return(tags, nil).Times(1)
+
+			mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tt.args...),
+				Stdout:  out,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("marks", marks),
+					testutils.Context("windows", windows),
+					testutils.Context("tags", tags),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	t.Run("empty by window CSV has the by-window header", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarks().Return([]queries.Mark{}, nil).Times(1)
+
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, "list", "--by-window", "-o", "csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(
+			t,
+			"marks,window_id,app_name,window_title,workspace,app_bundle_id,tags",
+			strings.TrimSpace(out),
+		)
+	})
+}
+
+func TestListCommandFilters(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{WindowID: 3, Mark: "web:docs"},
+		{WindowID: 1, Mark: "term"},
+		{WindowID: 2, Mark: "web:ci"},
+		{WindowID: 4, Mark: "notes"},
+		{WindowID: 3, Mark: "b"},
+	}
+	windows := []aerospace.Window{
+		{
+			WindowID:    1,
+			WindowTitle: "zsh",
+			AppName:     "Alacritty",
+			AppBundleID: "org.alacritty",
+			Workspace:   "2",
+		},
+		{
+			WindowID:    2,
+			WindowTitle: "CI",
+			AppName:     "Brave Browser",
+			AppBundleID: "com.brave.Browser",
+			Workspace:   "1",
+		},
+		{
+			WindowID:    3,
+			WindowTitle: "Docs",
+			AppName:     "Brave Browser",
+			AppBundleID: "com.brave.Browser",
+			Workspace:   "2",
+		},
+		{
+			WindowID:    4,
+			WindowTitle: "todo",
+			AppName:     "Notes",
+			AppBundleID: "com.apple.Notes",
+			Workspace:   "3",
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		focusedWorkspace string
+	}{
+		{"by workspace", []string{"list", "--workspace", "2"}, ""},
+		{"by focused workspace", []string{"list", "--focused-workspace", "--sort", "mark"}, "2"},
+		{"by app ignoring case", []string{"list", "--app", "brave browser"}, ""},
+		{"by bundle id", []string{"list", "--bundle-id", "com.apple.Notes"}, ""},
+		{"by mark glob", []string{"list", "--mark", "web:*"}, ""},
+		{"by mark regex", []string{"list", "--mark", "/^(term|notes)$/"}, ""},
+		{"sorted by mark", []string{"list", "--sort", "mark"}, ""},
+		{"sorted by app", []string{"list", "--sort", "app"}, ""},
+		{"sorted by workspace", []string{"list", "--sort", "workspace"}, ""},
+		{"sorted by window id", []string{"list", "--sort", "window-id", "-o", "csv"}, ""},
+		{"combined with by window", []string{"list", "--app", "Brave Browser", "--by-window"}, ""},
+		{"nothing matches", []string{"list", "--mark", "zzz"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" - `"+strings.Join(tt.args, " ")+"`", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+			strg.EXPECT().GetTags().Return(nil, nil).Times(1)
+
+			mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+			if tt.focusedWorkspace != "" {
+				mocks.ExpectGetFocusedWorkspace(mockAeroSpaceConnection, tt.focusedWorkspace).Times(1)
+			}
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tt.args...),
+				Stdout:  out,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("marks", marks),
+					testutils.Context("windows", windows),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	errorTests := []struct {
+		name string
+		args []string
+	}{
+		{"invalid sort", []string{"list", "--sort", "title"}},
+		{"invalid regex", []string{"list", "--mark", "/(/"}},
+		{
+			"workspace and focused workspace",
+			[]string{"list", "--workspace", "1", "--focused-workspace"},
+		},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name+" - `"+strings.Join(tt.args, " ")+"`", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			//nolint:reassign // Test utility needs to modify package variable
+			stdout.ShouldExit = false
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tt.args...),
+				Stdout:  out,
+				Stderr:  err.Error(),
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+}
+
+func TestListCommandFields(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{WindowID: 1, Mark: "term"},
+		{WindowID: 2, Mark: "web"},
+		{WindowID: 2, Mark: "w"},
+	}
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "zsh", AppName: "Alacritty", Workspace: "2"},
+		{WindowID: 2, WindowTitle: "GitHub", AppName: "Brave Browser", Workspace: "1"},
+	}
+	tags := []queries.WindowTag{
+		{WindowID: 2, Tag: "work"},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"text", []string{"list", "--fields", "mark,workspace"}},
+		{"json keeps the field order", []string{"list", "--fields", "workspace,mark", "-o", "json"}},
+		{"csv", []string{"list", "--fields", "mark,window_id", "-o", "csv"}},
+		{"yaml with tags", []string{"list", "--fields", "mark,tags", "-o", "yaml"}},
+		{"by window", []string{"list", "--by-window", "--fields", "marks,app_name", "-o", "json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" - `"+strings.Join(tt.args, " ")+"`", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+			strg.EXPECT().GetTags().Return(tags, nil).Times(1)
+
+			mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tt.args...),
+				Stdout:  out,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("marks", marks),
+					testutils.Context("windows", windows),
+					testutils.Context("tags", tags),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	errorTests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown field", []string{"list", "--fields", "mark,title"}},
+		{"mark field by window", []string{"list", "--by-window", "--fields", "mark"}},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name+" - `"+strings.Join(tt.args, " ")+"`", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			//nolint:reassign // Test utility needs to modify package variable
+			stdout.ShouldExit = false
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tt.args...),
+				Stdout:  out,
+				Stderr:  err.Error(),
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+}
+
+func TestListCommandTableStyle(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{WindowID: 1, Mark: "term"},
+		{WindowID: 2, Mark: "日本"},
+	}
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "zsh", AppName: "Alacritty", Workspace: "2"},
+		{WindowID: 2, WindowTitle: "ドキュメント", AppName: "Brave Browser", Workspace: "1"},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"aligned by display width", []string{"list"}},
+		{"with header", []string{"list", "--header", "--fields", "mark,window_title,workspace"}},
+		{"always colored", []string{"list", "--color", "always", "--fields", "mark,workspace"}},
+		{"auto isn't colored when piped", []string{"list", "--color", "auto", "--fields", "mark"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" - `"+strings.Join(tt.args, " ")+"`", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+			strg.EXPECT().GetTags().Return(nil, nil).Times(1)
+
+			mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tt.args...),
+				Stdout:  out,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("marks", marks),
+					testutils.Context("windows", windows),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	t.Run("invalid color", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecute(rootCmd, "list", "--color", "sometimes")
+		require.Error(t, err)
+		assert.Contains(
+			t,
+			err.Error(),
+			"invalid color 'sometimes', expected one of: auto, always, never",
+		)
+	})
+}
+
+func TestListCommandTemplate(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{WindowID: 1, Mark: "term"},
+		{WindowID: 2, Mark: "web"},
+		{WindowID: 2, Mark: "w"},
+	}
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "zsh", AppName: "Alacritty", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "GitHub - \"Brave\"", AppName: "Brave Browser", Workspace: "2"},
+	}
+
+	templateFile := t.TempDir() + "/sketchybar.tmpl"
+	err := os.WriteFile(templateFile, []byte("{{.Mark | pad 5}}{{.AppName}}\n"), 0o600)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			"renders each mark",
+			[]string{"list", "-o", "template", "--template", "{{.Mark}} {{.AppName}}"},
+		},
+		{
+			"with helpers",
+			[]string{
+				"list", "-o", "template",
+				"--template", `{"mark":{{json .Mark}},"title":{{json (.WindowTitle | truncate 10)}}}`,
+			},
+		},
+		{
+			"renders each window",
+			[]string{
+				"list", "--by-window", "-o", "template",
+				"--template", `{{.WindowID}}: {{join "," .Marks}}`,
+			},
+		},
+		{
+			"from a template file",
+			[]string{"list", "-o", "template", "--template-file", templateFile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+			strg.EXPECT().GetTags().Return(nil, nil).Times(1)
+
+			mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The temporary path changes on every run
+			args := slices.Clone(tt.args)
+			if i := slices.Index(args, templateFile); i >= 0 {
+				args[i] = "sketchybar.tmpl"
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(args...),
+				Stdout:  out,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("marks", marks),
+					testutils.Context("windows", windows),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	errorTests := []struct {
+		name string
+		args []string
+	}{
+		{"missing template", []string{"list", "-o", "template"}},
+		{"invalid template", []string{"list", "-o", "template", "--template", "{{.Mark"}},
+		{"missing template file", []string{"list", "-o", "template", "--template-file", "nope.tmpl"}},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+			//nolint:reassign // Test utility needs to modify package variable
+			stdout.ShouldExit = false
+
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			out, err := testutils.CmdExecute(rootCmd, tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(tt.args...),
+				Stdout:  out,
+				Stderr:  err.Error(),
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+}
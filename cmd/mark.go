@@ -11,12 +11,17 @@ import (
 
 	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
 	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
 	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
 )
 
-//nolint:gocognit,funlen // MarkCmd has high complexity and length due to multiple mark operations
+//nolint:funlen // MarkCmd handles the window flags and the daemon
 func MarkCmd(
 	storageClient storage.MarkStorage,
 	aerospaceClient aerospace.AerosSpaceMarkWindows,
@@ -42,6 +47,7 @@ Example:
 
 aerospace-marks mark first # Will set the mark first on the current window [first]
 aerospace-marks mark --add sec # Will add the mark sec to the current window [first sec]
+aerospace-marks mark term --launch 'open -a Alacritty' # 'focus --launch term' starts Alacritty when the window is gone
 `,
 		Args: cobra.MatchAll(
 			cobra.ExactArgs(1),
@@ -55,79 +61,70 @@ aerospace-marks mark --add sec # Will add the mark sec to the current window [fi
 			replace, _ := cmd.Flags().GetBool("replace")
 			winArgID, _ := cmd.Flags().GetString("window-id")
 			silent, _ := cmd.Flags().GetBool("silent")
-
-			// Get the window ID from the command line argument
-			var windowID int
-			if winArgID == "" {
-				window, err := aerospaceClient.Client().Windows().GetFocusedWindow()
-				if err != nil {
-					stdout.ErrorAndExit(err)
+			launchCommand, _ := cmd.Flags().GetString("launch")
+			toggle, _ := cmd.Flags().GetBool("toggle")
+
+			intWindowID := 0
+			if winArgID != "" {
+				var err error
+				intWindowID, err = strconv.Atoi(strings.TrimSpace(winArgID))
+				if err != nil || intWindowID <= 0 {
+					stdout.ErrorAndExit(
+						errkind.Errorf(errkind.InvalidInput, "invalid window ID '%s'", winArgID),
+					)
 					return
 				}
-				windowID = window.WindowID
-			} else {
-				intWindowID, err := strconv.Atoi(strings.TrimSpace(winArgID))
-				if err != nil {
-					stdout.ErrorAndExitf("invalid window ID '%s'", winArgID)
-					return
-				}
-				window, err := aerospaceClient.GetWindowByID(intWindowID)
-				if err != nil {
-					stdout.ErrorAndExit(err)
-					return
-				}
-				windowID = window.WindowID
 			}
 
-			// Manage marks using MarkClient
-			if add && !replace {
-				err := storageClient.AddMark(windowID, identifier)
-				if err != nil {
-					stdout.ErrorAndExit(err)
-					return
-				}
-				if silent {
+			method := markMethod(add, replace, toggle)
+
+			// The launch command is only saved without the daemon
+			if strings.TrimSpace(launchCommand) == "" {
+				var event format.OutputEvent
+				params := daemon.MarkParams{Mark: identifier, WindowID: intWindowID}
+				delegated, err := callDaemon(method, params, &event)
+				if delegated {
+					if err != nil {
+						stdout.ErrorAndExit(err)
+						return
+					}
+					if !silent {
+						fmt.Fprintln(os.Stdout, event.Message)
+					}
 					return
 				}
-
-				fmt.Fprintf(os.Stdout, "Added mark: %s\n", identifier)
-				return
 			}
 
-			if toggle, err := cmd.Flags().GetBool("toggle"); toggle {
+			// Get the window from the command line argument
+			var window *windows.Window
+			if intWindowID == 0 {
+				client, err := aerospaceClient.Client()
 				if err != nil {
 					stdout.ErrorAndExit(err)
 					return
 				}
-
-				toggleErr := storageClient.ToggleMark(windowID, identifier)
-				if toggleErr != nil {
-					stdout.ErrorAndExit(toggleErr)
+				focusedWindow, err := client.Windows().GetFocusedWindow()
+				if err != nil {
+					stdout.ErrorAndExit(err)
 					return
 				}
-
-				if silent {
+				window = focusedWindow
+			} else {
+				windowByID, err := aerospaceClient.GetWindowByID(intWindowID)
+				if err != nil {
+					stdout.ErrorAndExit(err)
 					return
 				}
-
-				fmt.Fprintf(os.Stdout, "Toggling mark: %s\n", identifier)
-				return
+				window = windowByID
 			}
 
-			hasBeenDeleted, err := storageClient.ReplaceAllMarks(windowID, identifier)
+			message, err := markWindow(storageClient, method, identifier, window, launchCommand)
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
-
-			if silent {
-				return
-			}
-
-			if hasBeenDeleted > 0 {
-				fmt.Fprintf(os.Stdout, "Replaced all marks with '%s'\n", identifier)
-			} else {
-				fmt.Fprintf(os.Stdout, "Marked window with '%s'\n", identifier)
+			if !silent {
+				fmt.Fprintln(os.Stdout, message)
 			}
 		},
 	}
@@ -138,6 +135,95 @@ aerospace-marks mark --add sec # Will add the mark sec to the current window [fi
 	newMarkCmd.Flags().Bool("toggle", false, "Toggle the mark on the window")
 	newMarkCmd.Flags().String("window-id", "", "Window ID to mark (default: focused window)")
 	newMarkCmd.Flags().BoolP("silent", "s", false, "Suppress output")
+	newMarkCmd.Flags().String("launch", "", "Command that starts the application, used by 'focus --launch'")
 
 	return newMarkCmd
 }
+
+// markMethod returns the daemon method marking a window like the flags of mark.
+func markMethod(add, replace, toggle bool) string {
+	switch {
+	case add && !replace:
+		return daemon.MethodAdd
+	case toggle:
+		return daemon.MethodToggle
+	default:
+		return daemon.MethodReplace
+	}
+}
+
+// markWindow marks the window with the daemon method, see markMethod, and
+// returns the message of mark. A launch command is saved in the same
+// transaction, once the window is marked, and not when toggling removes the
+// mark.
+func markWindow(
+	storageClient storage.MarkStorage,
+	method string,
+	mark string,
+	window *windows.Window,
+	launchCommand string,
+) (string, error) {
+	if strings.TrimSpace(launchCommand) == "" {
+		return applyMark(storageClient, method, mark, window)
+	}
+
+	var message string
+	err := storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		var err error
+		message, err = applyMark(tx, method, mark, window)
+		if err != nil {
+			return err
+		}
+
+		if method == daemon.MethodToggle {
+			_, err = tx.GetWindowIDByMark(mark)
+			if errkind.Of(err) == errkind.MarkNotFound {
+				// The mark was removed, there is nothing to launch
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.SetMarkLauncher(mark, launchCommand, window.AppBundleID)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return message, nil
+}
+
+// applyMark marks the window with the daemon method and returns the message
+// of mark.
+func applyMark(
+	storageClient storage.MarkStorage,
+	method string,
+	mark string,
+	window *windows.Window,
+) (string, error) {
+	metadata := aerospace.NewWindowMetadata(*window)
+
+	switch method {
+	case daemon.MethodAdd:
+		if err := storageClient.AddMark(window.WindowID, mark, metadata); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Added mark: %s", mark), nil
+	case daemon.MethodToggle:
+		if err := storageClient.ToggleMark(window.WindowID, mark, metadata); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Toggling mark: %s", mark), nil
+	default:
+		deleted, err := storageClient.ReplaceAllMarks(window.WindowID, mark, metadata)
+		if err != nil {
+			return "", err
+		}
+		if deleted > 0 {
+			return fmt.Sprintf("Replaced all marks with '%s'", mark), nil
+		}
+		return fmt.Sprintf("Marked window with '%s'", mark), nil
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"testing"
 
 	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
 	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
@@ -26,7 +31,10 @@ func TestMarkCommand(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			ReplaceAllMarks(1, "mark1").
+			ReplaceAllMarks(1, "mark1", storage.WindowMetadata{
+				AppName:     "app1",
+				WindowTitle: "title1",
+			}).
 			Return(int64(1), nil).
 			Times(1)
 
@@ -84,7 +92,10 @@ func TestMarkCommand(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			ReplaceAllMarks(2, "mark1").
+			ReplaceAllMarks(2, "mark1", storage.WindowMetadata{
+				AppName:     "app2",
+				WindowTitle: "title2",
+			}).
 			Return(int64(1), nil).
 			Times(1)
 
@@ -147,7 +158,10 @@ func TestMarkCommand(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			AddMark(1, "mark2").
+			AddMark(1, "mark2", storage.WindowMetadata{
+				AppName:     "app1",
+				WindowTitle: "title1",
+			}).
 			Return(nil).
 			Times(1)
 
@@ -234,7 +248,10 @@ func TestMarkCommand(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			ToggleMark(2, "foobar").
+			ToggleMark(2, "foobar", storage.WindowMetadata{
+				AppName:     "app2",
+				WindowTitle: "title2",
+			}).
 			Return(nil).
 			Times(1)
 
@@ -292,7 +309,10 @@ func TestMarkCommand(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			ToggleMark(2, "foobar").
+			ToggleMark(2, "foobar", storage.WindowMetadata{
+				AppName:     "app2",
+				WindowTitle: "title2",
+			}).
 			Return(nil).
 			Times(1)
 
@@ -378,3 +398,108 @@ func TestMarkCommand(t *testing.T) {
 		snaps.MatchSnapshot(t, snapshot)
 	})
 }
+
+func TestMarkCommandLaunch(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("records the launch command - `mark term --launch 'open -a Alacritty'`", func(t *testing.T) {
+		args := []string{"mark", "term", "--launch", "open -a Alacritty"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		window := aerospace.Window{
+			WindowID:    7,
+			WindowTitle: "zsh",
+			AppName:     "Alacritty",
+			AppBundleID: "io.alacritty",
+			Workspace:   "1",
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		// The launch command is saved with the mark, once it is set
+		mocks.ExpectTransaction(strg).Times(1)
+		gomock.InOrder(
+			strg.EXPECT().
+				ReplaceAllMarks(7, "term", storage.WindowMetadata{
+					AppBundleID: "io.alacritty",
+					AppName:     "Alacritty",
+					WindowTitle: "zsh",
+					Workspace:   "1",
+				}).
+				Return(int64(0), nil).
+				Times(1),
+			strg.EXPECT().
+				SetMarkLauncher("term", "open -a Alacritty", "io.alacritty").
+				Return(nil).
+				Times(1),
+		)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, window).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", []aerospace.Window{window}),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+	t.Run("doesn't record the launch command when the mark fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		window := aerospace.Window{WindowID: 7, AppName: "Alacritty", AppBundleID: "io.alacritty"}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().
+			AddMark(7, "term", gomock.Any()).
+			Return(errkind.Wrap(errkind.InvalidInput, storage.ErrMarkExists)).
+			Times(1)
+		strg.EXPECT().SetMarkLauncher(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, window).Times(1)
+
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecute(rootCmd, "mark", "term", "--add", "--launch", "alacritty")
+		require.Error(t, err)
+	})
+
+	t.Run("doesn't record the launch command when toggling removes the mark", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		window := aerospace.Window{WindowID: 7, AppName: "Alacritty", AppBundleID: "io.alacritty"}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().ToggleMark(7, "term", gomock.Any()).Return(nil).Times(1)
+		strg.EXPECT().
+			GetWindowIDByMark("term").
+			Return(0, errkind.New(errkind.MarkNotFound, "no window found for mark term")).
+			Times(1)
+		strg.EXPECT().SetMarkLauncher(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, window).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		args := []string{"mark", "term", "--toggle", "--launch", "alacritty"}
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+		assert.Equal(t, "Toggling mark: term\n", out)
+	})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+)
+
+// markPatternMatcher returns a function matching marks against a pattern
+// (see markMatcher) or, when the pattern is neither a regex nor a glob, a
+// prefix.
+func markPatternMatcher(pattern string) (func(mark string) bool, error) {
+	return patternMatcher(pattern, func(mark string) bool {
+		return strings.HasPrefix(mark, pattern)
+	})
+}
+
+// markMatcher returns a function matching marks against a regex when the
+// pattern is wrapped in slashes ('/^web:/'), a glob when it has any of
+// '*?[' (see globRegexp), or else the exact mark.
+func markMatcher(pattern string) (func(mark string) bool, error) {
+	return patternMatcher(pattern, func(mark string) bool {
+		return mark == pattern
+	})
+}
+
+// patternMatcher returns a regex or glob matcher for pattern, or plain when
+// the pattern is neither.
+func patternMatcher(
+	pattern string,
+	plain func(mark string) bool,
+) (func(mark string) bool, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, errkind.Errorf(errkind.InvalidInput, "invalid regex '%s': %w", pattern, err)
+		}
+
+		return re.MatchString, nil
+	}
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		return plain, nil
+	}
+
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, errkind.Errorf(errkind.InvalidInput, "invalid glob '%s': %w", pattern, err)
+	}
+
+	return re.MatchString, nil
+}
+
+// globRegexp compiles a glob into a regex matching whole marks. Unlike
+// path.Match, '*' and '?' match any character, '/' included, since marks
+// aren't paths: 'web/*' matches 'web/docs/ci'. '[...]' is a class ('[!...]'
+// or '[^...]' negated) and '\' escapes the next character.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("(?s)^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '\\':
+			i++
+			if i == len(pattern) {
+				return nil, path.ErrBadPattern
+			}
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, path.ErrBadPattern
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+
+	return regexp.Compile(expr.String())
+}
@@ -0,0 +1,178 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// MoveToMarkCmd represents the move-to-mark command.
+//
+//nolint:funlen // MoveToMarkCmd has multiple flags to handle
+func MoveToMarkCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	moveCmd := &cobra.Command{
+		Use:   "move-to-mark <identifier> [flags]",
+		Short: "Move the focused window to the workspace of a marked window",
+		Long: `Move the focused window to the workspace of a marked window
+
+Similar to i3's 'move container to mark <identifier>'. It is the inverse of
+summon: instead of bringing the marked window here, the focused window (or
+--window-id) is sent next to the marked window.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateArgIsNotEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+			mark := args[0]
+			logger.LogDebug("MoveToMarkCmd called", "mark", mark)
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			shouldFocus, err := cmd.Flags().GetBool("focus")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			winArgID, err := cmd.Flags().GetString("window-id")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			intWindowID := 0
+			if winArgID != "" {
+				intWindowID, err = strconv.Atoi(strings.TrimSpace(winArgID))
+				if err != nil || intWindowID <= 0 {
+					stdout.ErrorAndExit(
+						errkind.Errorf(errkind.InvalidInput, "invalid window ID '%s'", winArgID),
+					)
+					return
+				}
+			}
+
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			// Get the workspace of the marked window
+			markedWindow, err := aerospace.NewMarkResolver(storageClient, aerospaceClient).ResolveMark(mark)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			targetWorkspace := markedWindow.Workspace
+
+			client, err := aerospaceClient.Client()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			// Get the window to move
+			var window *windows.Window
+			if intWindowID == 0 {
+				window, err = client.Windows().GetFocusedWindow()
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+			} else {
+				window, err = aerospaceClient.GetWindowByID(intWindowID)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+			}
+			windowID := window.WindowID
+
+			if windowID == markedWindow.WindowID {
+				stdout.ErrorAndExit(
+					errkind.New(errkind.InvalidInput, "cannot move a window to its own mark"),
+				)
+				return
+			}
+
+			err = moveWindowToWorkspace(aerospaceClient, windowID, targetWorkspace)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			if shouldFocus {
+				focusErr := client.Focus().SetFocusByWindowID(windowID)
+				if focusErr != nil {
+					stdout.ErrorAndExit(focusErr)
+					return
+				}
+			}
+
+			logger.LogDebug(
+				"Window moved to mark",
+				"windowID", windowID,
+				"mark", mark,
+				"workspace", targetWorkspace,
+			)
+
+			action := "move"
+			message := fmt.Sprintf("Window %d moved to workspace %s", windowID, targetWorkspace)
+			if shouldFocus {
+				action = "move_and_focus"
+				message += " and focused"
+			}
+
+			event := format.OutputEvent{
+				Command:         "move-to-mark",
+				Action:          action,
+				WindowID:        windowID,
+				AppName:         window.AppName,
+				Workspace:       window.Workspace,
+				TargetWorkspace: targetWorkspace,
+				Result:          "success",
+				Message:         message,
+			}
+
+			if formatErr := formatter.Format(event); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+		},
+	}
+
+	moveCmd.Flags().BoolP("focus", "f", false, "Focus the window after moving")
+	moveCmd.Flags().String("window-id", "", "Window ID to move (default: focused window)")
+
+	return moveCmd
+}
@@ -0,0 +1,184 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+func TestMoveToMarkCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "title1", AppName: "app1", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "title2", AppName: "app2", Workspace: "2"},
+		{WindowID: 3, WindowTitle: "title3", AppName: "app3", Workspace: "3"},
+	}
+
+	t.Run("validate missing identifier", func(t *testing.T) {
+		args := []string{"move-to-mark"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("moves the focused window to the marked window workspace - `move-to-mark a`", func(t *testing.T) {
+		args := []string{"move-to-mark", "a"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("a").
+			Return(&queries.Mark{WindowID: 2, Mark: "a"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"2", "--window-id", "1"},
+		).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("moves and focuses a given window - `move-to-mark a --window-id 3 --focus -o json`", func(t *testing.T) {
+		args := []string{"move-to-mark", "a", "--window-id", "3", "--focus", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("a").
+			Return(&queries.Mark{WindowID: 2, Mark: "a"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		// Once to resolve the mark, once to find the window by ID
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(2)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"2", "--window-id", "3"},
+		).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "3"}).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails to move the marked window to itself", func(t *testing.T) {
+		args := []string{"move-to-mark", "a"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("a").
+			Return(&queries.Mark{WindowID: 1, Mark: "a"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails with a window ID that isn't positive - `move-to-mark a --window-id 0`", func(t *testing.T) {
+		args := []string{"move-to-mark", "a", "--window-id", "0"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetWindowByMark(gomock.Any()).Times(0)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "move-node-to-workspace", gomock.Any()).Times(0)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/spf13/cobra"
+)
+
+// newListOutputFormatter creates the list formatter for the --output flag,
+// with the template of --template or --template-file and the fields of
+// --fields. Extra options override the ones from flags.
+func newListOutputFormatter(
+	cmd *cobra.Command,
+	outputFormat string,
+	extraOpts ...format.FormatterOption,
+) (*format.ListOutputFormatter, error) {
+	opts, err := formatterOptionsFromFlags(cmd, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return format.NewListOutputFormatter(os.Stdout, outputFormat, append(opts, extraOpts...)...)
+}
+
+// newOutputEventFormatter creates the event formatter for the --output flag,
+// with the template of --template or --template-file.
+func newOutputEventFormatter(
+	cmd *cobra.Command,
+	outputFormat string,
+) (*format.OutputEventFormatter, error) {
+	opts, err := formatterOptionsFromFlags(cmd, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return format.NewOutputEventFormatter(os.Stdout, outputFormat, opts...)
+}
+
+// formatterOptionsFromFlags reads the output template from --template or
+// the file given with --template-file, the fields of commands with --fields
+// and the text table style of commands with --header and --color.
+func formatterOptionsFromFlags(
+	cmd *cobra.Command,
+	outputFormat string,
+) ([]format.FormatterOption, error) {
+	// Window titles only get truncated on terminals, pipes get whole rows
+	opts := []format.FormatterOption{format.WithMaxWidth(stdout.TerminalWidth())}
+	if cmd.Flags().Lookup("header") != nil {
+		header, _ := cmd.Flags().GetBool("header")
+		opts = append(opts, format.WithHeader(header))
+	}
+	if cmd.Flags().Lookup("color") != nil {
+		colorMode, _ := cmd.Flags().GetString("color")
+		color, err := stdout.ColorEnabled(colorMode)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, format.WithColor(color))
+	}
+	if cmd.Flags().Lookup("fields") != nil {
+		fields, _ := cmd.Flags().GetStringSlice("fields")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		opts = append(opts, format.WithFields(fields))
+	}
+
+	tmpl, _ := cmd.Flags().GetString("template")
+
+	templateFile, _ := cmd.Flags().GetString("template-file")
+	if templateFile != "" {
+		content, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, errkind.Errorf(
+				errkind.InvalidInput,
+				"failed to read template file: %w",
+				err,
+			)
+		}
+		tmpl = string(content)
+	}
+
+	if tmpl == "" {
+		if strings.EqualFold(strings.TrimSpace(outputFormat), string(format.OutputFormatTemplate)) {
+			return nil, fmt.Errorf(
+				"%w, set it with --template or --template-file",
+				format.ErrMissingTemplate,
+			)
+		}
+		return opts, nil
+	}
+
+	return append(opts, format.WithTemplate(tmpl)), nil
+}
+
+// enableFieldsFlag adds the --fields flag selecting the fields of the
+// output, see format.WithFields.
+func enableFieldsFlag(command *cobra.Command, fields []string) {
+	command.Flags().StringSlice(
+		"fields",
+		nil,
+		"Comma separated fields to output, in order: "+strings.Join(fields, ", "),
+	)
+}
+
+// enableTableFlags adds the flags styling the text tables: --header and
+// --color.
+func enableTableFlags(command *cobra.Command) {
+	command.Flags().Bool("header", false, "Print a header row with the column names in text format")
+	command.Flags().String(
+		"color",
+		"auto",
+		"Color marks and workspaces in text format: auto, always or never",
+	)
+}
+
+// enableDocumentOutputFlag adds the --output flag of commands printing a
+// report instead of windows, see format.ParseDocumentFormat.
+func enableDocumentOutputFlag(command *cobra.Command) {
+	command.Flags().StringP("output", "o", "text", "Output format: text, json or yaml")
+}
@@ -0,0 +1,163 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// PruneCmd represents the prune command.
+//
+//nolint:funlen // PruneCmd has multiple output and dry-run branches
+func PruneCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	pruneCmd := &cobra.Command{
+		Use:   "prune [flags]",
+		Short: "Remove marks of windows that no longer exist",
+		Long: `Remove marks of windows that no longer exist
+
+Compares the stored marks against the windows currently managed by AeroSpace
+and removes the marks whose window is gone. Marks that can still be re-bound
+to a live window (same app and title) are kept. The tags of windows that are
+gone, or whose ID now belongs to another app, are removed too.
+
+Use --dry-run to only list the orphaned marks.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+
+Set ` + "`AEROSPACE_MARKS_AUTO_PRUNE=true`" + ` to prune automatically whenever a command
+fetches all windows (list, focus, summon, get, ...).
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			dryRun, err := cmd.Flags().GetBool("dry-run")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			formatter, err := newListOutputFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			marks, err := storageClient.GetMarks()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			client, err := aerospaceClient.Client()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			windowsList, err := client.Windows().GetAllWindows()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			orphans := aerospace.FindOrphanMarks(marks, windowsList)
+
+			summary := fmt.Sprintf("Would remove %d orphaned marks", len(orphans))
+			if !dryRun {
+				deleted, pruneErr := pruneOrphans(storageClient, marks, orphans, windowsList)
+				if pruneErr != nil {
+					stdout.ErrorAndExit(pruneErr)
+					return
+				}
+				summary = fmt.Sprintf("Removed %d orphaned marks", deleted)
+			}
+			logger.LogInfo("Pruned orphan marks", "dryRun", dryRun, "orphans", len(orphans))
+
+			if len(orphans) == 0 {
+				if formatErr := formatter.FormatEmpty("No orphaned marks found"); formatErr != nil {
+					stdout.ErrorAndExit(fmt.Errorf("failed to format empty output: %w", formatErr))
+					return
+				}
+				return
+			}
+
+			prunedWindows := make([]format.MarkedWindow, 0, len(orphans))
+			for _, orphan := range orphans {
+				prunedWindows = append(prunedWindows, format.MarkedWindow{
+					Mark:        orphan.Mark,
+					WindowID:    orphan.WindowID,
+					AppName:     orphan.AppName,
+					WindowTitle: orphan.WindowTitle,
+					Workspace:   orphan.Workspace,
+					AppBundleID: orphan.AppBundleID,
+				})
+			}
+
+			if formatErr := formatter.Format(prunedWindows); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+
+			if outputFormat == string(format.OutputFormatText) {
+				fmt.Fprintln(os.Stdout, summary)
+			}
+		},
+	}
+
+	pruneCmd.Flags().Bool("dry-run", false, "Only list the orphaned marks without removing them")
+
+	return pruneCmd
+}
+
+// pruneOrphans deletes the orphan marks, then the tags of the windows that
+// are gone, see aerospace.PruneOrphanTags. Returns the number of deleted
+// marks.
+func pruneOrphans(
+	storageClient storage.MarkStorage,
+	marks []queries.Mark,
+	orphans []queries.Mark,
+	windowsList []windows.Window,
+) (int64, error) {
+	var deleted int64
+	if len(orphans) > 0 {
+		var err error
+		deleted, err = aerospace.PruneOrphanMarks(storageClient, marks, orphans)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	deletedTags, err := aerospace.PruneOrphanTags(storageClient, windowsList)
+	if err != nil {
+		return 0, err
+	}
+	logger.GetDefaultLogger().LogInfo("Pruned orphan tags", "deleted", deletedTags)
+
+	return deleted, nil
+}
@@ -0,0 +1,178 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+func TestPruneCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{WindowID: 1, Mark: "live"},
+		{WindowID: 10, Mark: "dead1", AppName: "Zoom", AppBundleID: "us.zoom.xos"},
+		{WindowID: 10, Mark: "dead2", AppName: "Zoom", AppBundleID: "us.zoom.xos"},
+		{WindowID: 11, Mark: "gone", AppName: "Notes", WindowTitle: "todo"},
+		{
+			WindowID:    11,
+			Mark:        "rebindable",
+			AppName:     "Alacritty",
+			AppBundleID: "io.alacritty",
+			WindowTitle: "nvim",
+		},
+	}
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		{WindowID: 2, WindowTitle: "nvim", AppName: "Alacritty", AppBundleID: "io.alacritty"},
+	}
+
+	t.Run("lists orphaned marks without removing them - `prune --dry-run`", func(t *testing.T) {
+		args := []string{"prune", "--dry-run"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", marks),
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("removes orphaned marks - `prune -o json`", func(t *testing.T) {
+		args := []string{"prune", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+		mocks.ExpectTransaction(strg).Times(1)
+		// Window 10 only has orphan marks, window 11 still has a mark that can be re-bound
+		strg.EXPECT().DeleteByWindow(10).Return(int64(2), nil).Times(1)
+		strg.EXPECT().DeleteByMark("gone").Return(int64(1), nil).Times(1)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", marks),
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("removes orphaned marks - `prune`", func(t *testing.T) {
+		args := []string{"prune"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Window 11 is gone but its tags are kept for the mark that can be
+		// re-bound, window 2 is now an app that wasn't tagged
+		tags := []queries.WindowTag{
+			{WindowID: 1, Tag: "work"},
+			{WindowID: 2, Tag: "meeting", AppBundleID: "us.zoom.xos"},
+			{WindowID: 10, Tag: "meeting", AppBundleID: "us.zoom.xos"},
+			{WindowID: 11, Tag: "editor", AppBundleID: "io.alacritty"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		gomock.InOrder(
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1),
+			strg.EXPECT().GetMarks().Return([]queries.Mark{marks[0], marks[4]}, nil).Times(1),
+		)
+		mocks.ExpectTransaction(strg).Times(2)
+		strg.EXPECT().DeleteByWindow(10).Return(int64(2), nil).Times(1)
+		strg.EXPECT().DeleteByMark("gone").Return(int64(1), nil).Times(1)
+		strg.EXPECT().GetTags().Return(tags, nil).Times(1)
+		strg.EXPECT().DeleteWindowTag(2, "meeting").Return(int64(1), nil).Times(1)
+		strg.EXPECT().DeleteWindowTag(10, "meeting").Return(int64(1), nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", marks),
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("nothing to prune", func(t *testing.T) {
+		args := []string{"prune"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		liveMarks := []queries.Mark{{WindowID: 1, Mark: "live"}}
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarks().Return(liveMarks, nil).Times(1)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", liveMarks),
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
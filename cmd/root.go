@@ -7,7 +7,9 @@ import (
 	"os"
 
 	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
 	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/spf13/cobra"
 )
@@ -24,20 +26,48 @@ func NewRootCmd(
 This CLI is heavily inspired by the marks feature of i3 and sway window managers.
 		`,
 		Version: VERSION,
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			// Errors are printed as JSON when the command outputs JSON
+			outputFormat := ""
+			if cmd.Flags().Lookup("output") != nil {
+				outputFormat, _ = cmd.Flags().GetString("output")
+			}
+			stdout.SetErrorOutput(cmd.Name(), outputFormat)
+		},
 	}
 
 	// Required new Mark Cmd because of leaking context
 	newRootCmd.AddCommand(InfoCmd(storage, aerospaceClient))
+	newRootCmd.AddCommand(DoctorCmd(storage, aerospaceClient))
 
 	// Manage marks
 	newRootCmd.AddCommand(MarkCmd(storage, aerospaceClient))
-	newRootCmd.AddCommand(UnmarkCmd(storage))
+	newRootCmd.AddCommand(enableOutputFlag(UnmarkCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(PruneCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(DaemonCmd(storage, aerospaceClient))
+	newRootCmd.AddCommand(ServeCmd(storage, aerospaceClient))
+	newRootCmd.AddCommand(ExportCmd(storage))
+	newRootCmd.AddCommand(ImportCmd(storage, aerospaceClient))
+	newRootCmd.AddCommand(BatchCmd(storage, aerospaceClient))
+	newRootCmd.AddCommand(UndoCmd(storage))
+	newRootCmd.AddCommand(RedoCmd(storage))
+	newRootCmd.AddCommand(HistoryCmd(storage))
 
 	// Manage windows with marks
 	newRootCmd.AddCommand(enableOutputFlag(FocusCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(FocusNextCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(FocusPrevCmd(storage, aerospaceClient)))
 	newRootCmd.AddCommand(enableOutputFlag(ListCmd(storage, aerospaceClient)))
 	newRootCmd.AddCommand(enableOutputFlag(SummonCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(DismissCmd(storage, aerospaceClient)))
 	newRootCmd.AddCommand(enableOutputFlag(GetCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(SwapCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(MoveToMarkCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(ScratchpadCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(TagCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(UntagCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(SummonGroupCmd(storage, aerospaceClient)))
+	newRootCmd.AddCommand(enableOutputFlag(FocusGroupCmd(storage, aerospaceClient)))
 
 	return newRootCmd
 }
@@ -50,8 +80,15 @@ func init() {
 
 func enableOutputFlag(command *cobra.Command) *cobra.Command {
 	// Add output flag
-	command.Flags().StringP("output", "o", "text", "Output format: text, json, or csv")
+	command.Flags().StringP("output", "o", "text", "Output format: "+format.OutputFormatNames())
 	command.Flag("output").DefValue = string(format.OutputFormatText)
+	command.Flags().String(
+		"template",
+		"",
+		"Go template for --output template, e.g. '{{.WindowID}} {{.AppName | truncate 20}}'",
+	)
+	command.Flags().String("template-file", "", "File with the Go template for --output template")
+	command.MarkFlagsMutuallyExclusive("template", "template-file")
 	return command
 }
 
@@ -59,7 +96,11 @@ func Run(storage storage.MarkStorage, aerospaceClient aerospace.AerosSpaceMarkWi
 	rootCmd := NewRootCmd(storage, aerospaceClient)
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		// Errors cobra returns itself are about flags and arguments
+		if errkind.Of(err) == errkind.Unknown {
+			os.Exit(errkind.ExitInvalidInput)
+		}
+		os.Exit(errkind.ExitCode(err))
 	}
 }
 
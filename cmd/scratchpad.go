@@ -0,0 +1,184 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/constants"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// defaultStashWorkspace is where hidden scratchpad windows go when their
+// origin workspace is unknown.
+const defaultStashWorkspace = "scratchpad"
+
+// ScratchpadCmd represents the scratchpad command.
+//
+//nolint:funlen // ScratchpadCmd handles both show and hide
+func ScratchpadCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	scratchpadCmd := &cobra.Command{
+		Use:   "scratchpad <identifier> [flags]",
+		Short: "Toggle a marked window between its workspace and the current one",
+		Long: `Toggle a marked window between its workspace and the current one
+
+Emulates i3's scratchpad with marks:
+ - if the marked window is elsewhere, it is summoned to the focused workspace
+   and focused. The workspace it came from is recorded.
+ - if it is already on the focused workspace, it is sent back to the workspace
+   it came from, or to the stash workspace if unknown.
+
+The stash workspace can be set with --stash or ` + "`" + constants.EnvAeroSpaceMarksScratchpadWorkspace + "`" + `
+(default: ` + defaultStashWorkspace + `).
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+
+Example:
+
+  cmd-ctrl-t = 'exec-and-forget aerospace-marks scratchpad terminal'
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateArgIsNotEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+			mark := args[0]
+			logger.LogDebug("ScratchpadCmd called", "mark", mark)
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			stashWorkspace, err := cmd.Flags().GetString("stash")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			stashWorkspace = strings.TrimSpace(stashWorkspace)
+			if stashWorkspace == "" {
+				stashWorkspace = defaultStashWorkspace
+			}
+
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			window, err := aerospace.NewMarkResolver(storageClient, aerospaceClient).ResolveMark(mark)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			windowID := window.WindowID
+
+			client, err := aerospaceClient.Client()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			focusedWorkspace, err := client.Workspaces().GetFocusedWorkspace()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			event := format.OutputEvent{
+				Command:   "scratchpad",
+				WindowID:  windowID,
+				AppName:   window.AppName,
+				Workspace: window.Workspace,
+				Result:    "success",
+			}
+
+			if window.Workspace != focusedWorkspace.Workspace {
+				// Show: remember where it was so hiding sends it back there
+				err = storageClient.SetMarkOrigin(mark, window.Workspace)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+
+				err = moveWindowToWorkspace(aerospaceClient, windowID, focusedWorkspace.Workspace)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+
+				err = client.Focus().SetFocusByWindowID(windowID)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+
+				event.Action = "show"
+				event.TargetWorkspace = focusedWorkspace.Workspace
+				event.Message = fmt.Sprintf(
+					"Window %d shown on workspace %s",
+					windowID,
+					focusedWorkspace.Workspace,
+				)
+			} else {
+				// Hide: send it back to where it came from
+				targetWorkspace, originErr := storageClient.GetMarkOrigin(mark)
+				if originErr != nil {
+					stdout.ErrorAndExit(originErr)
+					return
+				}
+				if targetWorkspace == "" || targetWorkspace == focusedWorkspace.Workspace {
+					targetWorkspace = stashWorkspace
+				}
+
+				err = moveWindowToWorkspace(aerospaceClient, windowID, targetWorkspace)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+
+				event.Action = "hide"
+				event.TargetWorkspace = targetWorkspace
+				event.Message = fmt.Sprintf("Window %d hidden to workspace %s", windowID, targetWorkspace)
+			}
+
+			logger.LogDebug(
+				"Scratchpad toggled",
+				"windowID", windowID,
+				"action", event.Action,
+				"targetWorkspace", event.TargetWorkspace,
+			)
+
+			if formatErr := formatter.Format(event); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+		},
+	}
+
+	scratchpadCmd.Flags().String(
+		"stash",
+		os.Getenv(constants.EnvAeroSpaceMarksScratchpadWorkspace),
+		"Workspace for hidden windows when their origin is unknown (default: "+defaultStashWorkspace+")",
+	)
+
+	return scratchpadCmd
+}
@@ -0,0 +1,189 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+func TestScratchpadCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("shows a window from another workspace - `scratchpad term`", func(t *testing.T) {
+		args := []string{"scratchpad", "term"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		windows := []aerospace.Window{
+			{WindowID: 7, WindowTitle: "zsh", AppName: "Alacritty", Workspace: "3"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("term").
+			Return(&queries.Mark{WindowID: 7, Mark: "term"}, nil).
+			Times(1)
+		strg.EXPECT().SetMarkOrigin("term", "3").Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(mockAeroSpaceConnection, "1").Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"1", "--window-id", "7"},
+		).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "7"}).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("hides a window back to its origin - `scratchpad term -o json`", func(t *testing.T) {
+		args := []string{"scratchpad", "term", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		windows := []aerospace.Window{
+			{WindowID: 7, WindowTitle: "zsh", AppName: "Alacritty", Workspace: "1"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("term").
+			Return(&queries.Mark{WindowID: 7, Mark: "term"}, nil).
+			Times(1)
+		strg.EXPECT().GetMarkOrigin("term").Return("3", nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(mockAeroSpaceConnection, "1").Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"3", "--window-id", "7"},
+		).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("hides a window without origin to the stash - `scratchpad term`", func(t *testing.T) {
+		args := []string{"scratchpad", "term"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		windows := []aerospace.Window{
+			{WindowID: 7, WindowTitle: "zsh", AppName: "Alacritty", Workspace: "1"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("term").
+			Return(&queries.Mark{WindowID: 7, Mark: "term"}, nil).
+			Times(1)
+		strg.EXPECT().GetMarkOrigin("term").Return("", nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(mockAeroSpaceConnection, "1").Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"scratchpad", "--window-id", "7"},
+		).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("hides a window to a custom stash - `scratchpad term --stash S`", func(t *testing.T) {
+		args := []string{"scratchpad", "term", "--stash", "S"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		windows := []aerospace.Window{
+			{WindowID: 7, WindowTitle: "zsh", AppName: "Alacritty", Workspace: "1"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("term").
+			Return(&queries.Mark{WindowID: 7, Mark: "term"}, nil).
+			Times(1)
+		// Origin is the current workspace, hiding there would be a no-op
+		strg.EXPECT().GetMarkOrigin("term").Return("1", nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(mockAeroSpaceConnection, "1").Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"S", "--window-id", "7"},
+		).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// ServeCmd represents the serve command.
+func ServeCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve [flags]",
+		Short: "Expose the mark operations as JSON-RPC over a Unix socket",
+		Long: `Expose the mark operations as JSON-RPC over a Unix socket
+
+Serves newline delimited JSON-RPC 2.0 requests, so scripts (e.g. sketchybar,
+Hammerspoon) don't need to parse the text output of the commands.
+
+Methods:
+  add, toggle, replace  {"mark": "<identifier>", "window_id": <id>}  (default: focused window)
+  delete, get           {"mark": "<identifier>"}
+  list
+  focus                 {"mark": "<identifier>"}
+  summon                {"mark": "<identifier>", "focus": true|false}
+  sync, ping
+
+Results use the same fields as ` + "`--output json`" + `.
+
+Example:
+
+  echo '{"jsonrpc":"2.0","id":1,"method":"list"}' | nc -U "$AEROSPACE_MARKS_SOCKET"
+
+Unlike daemon, serve doesn't sync marks in background.
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+
+			socketPath, err := cmd.Flags().GetString("socket")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			listener, err := daemon.Listen(socketPath)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			server := daemon.NewServer()
+			daemon.New(storageClient, aerospaceClient).Register(server)
+
+			logger.LogInfo("Serving marks API", "socket", socketPath)
+			fmt.Fprintf(os.Stdout, "Listening on %s\n", socketPath)
+
+			if serveErr := server.Serve(ctx, listener); serveErr != nil {
+				stdout.ErrorAndExit(serveErr)
+				return
+			}
+		},
+	}
+
+	serveCmd.Flags().String("socket", daemon.SocketPath(), "Path of the Unix socket to listen on")
+
+	return serveCmd
+}
@@ -5,22 +5,20 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
 	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
 	"github.com/cristianoliveira/aerospace-marks/internal/format"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/spf13/cobra"
-
-	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
 )
 
 // SummonCmd represents the summon command.
 //
-//nolint:gocognit,funlen // SummonCmd has high complexity and length due to workspace operations and output formatting
+//nolint:funlen // SummonCmd handles summoning, returning and the daemon
 func SummonCmd(
 	storageClient storage.MarkStorage,
 	aerospaceClient aerospace.AerosSpaceMarkWindows,
@@ -31,7 +29,9 @@ func SummonCmd(
 		Long: `Summon a marked window to current workspace.
 
 Similar to 'aerospace summon-workspace' but for marked windows to current workspace.
-Output format can be controlled with --output flag (text, json, csv).
+The workspace the window came from is recorded, use --return (or dismiss)
+to send it back.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
 `,
 		Args: cobra.MatchAll(
 			cobra.ExactArgs(1),
@@ -60,87 +60,59 @@ Output format can be controlled with --output flag (text, json, csv).
 				return
 			}
 
-			// Get window ID by mark
-			windowID, err := storageClient.GetWindowIDByMark(mark)
+			shouldReturn, err := cmd.Flags().GetBool("return")
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
-			if windowID == 0 {
-				stdout.ErrorAndExitf("no window found for mark '%s'", mark)
-				return
-			}
 
-			workspace, err := aerospaceClient.Client().Workspaces().GetFocusedWorkspace()
+			// Format output using OutputEvent
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
 
-			// Move window to current workspace
-			err = aerospaceClient.Client().Workspaces().MoveWindowToWorkspaceWithOpts(
-				workspaces.MoveWindowToWorkspaceArgs{
-					WorkspaceName: workspace.Workspace,
-				},
-				workspaces.MoveWindowToWorkspaceOpts{
-					WindowID: &windowID,
-				},
-			)
-			if err != nil {
-				stdout.ErrorAndExit(err)
+			if shouldReturn {
+				event, returnErr := returnMarkedWindow(storageClient, aerospaceClient, mark)
+				if returnErr != nil {
+					stdout.ErrorAndExit(returnErr)
+					return
+				}
+				event.Command = "summon"
+				event.Action = "return"
+
+				if formatErr := formatter.Format(*event); formatErr != nil {
+					stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				}
 				return
 			}
 
-			if shouldFocus {
-				focusErr := aerospaceClient.Client().
-					Focus().
-					SetFocusByWindowID(windowID)
-				if focusErr != nil {
-					stdout.ErrorAndExit(focusErr)
+			var event format.OutputEvent
+			params := daemon.SummonParams{Mark: mark, Focus: shouldFocus}
+			delegated, err := callDaemon(daemon.MethodSummon, params, &event)
+			if delegated {
+				if err != nil {
+					stdout.ErrorAndExit(err)
 					return
 				}
+				if formatErr := formatter.Format(event); formatErr != nil {
+					stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				}
+				return
 			}
 
-			logger.LogDebug(
-				"Window summoned",
-				"windowID",
-				windowID,
-				"workspace",
-				workspace.Workspace,
-			)
-
-			// Format output using OutputEvent
-			formatter, err := format.NewOutputEventFormatter(os.Stdout, outputFormat)
+			// Get the live window by mark
+			window, err := aerospace.NewMarkResolver(storageClient, aerospaceClient).ResolveMark(mark)
 			if err != nil {
 				stdout.ErrorAndExit(err)
 				return
 			}
 
-			action := "summon"
-			if shouldFocus {
-				action = "summon_and_focus"
-			}
-			message := fmt.Sprintf(
-				"Window %d summoned to workspace %s",
-				windowID,
-				workspace.Workspace,
-			)
-			if shouldFocus {
-				message = fmt.Sprintf(
-					"Window %d summoned to workspace %s and focused",
-					windowID,
-					workspace.Workspace,
-				)
-			}
-
-			event := format.OutputEvent{
-				Command:         "summon",
-				Action:          action,
-				WindowID:        windowID,
-				Workspace:       workspace.Workspace,
-				TargetWorkspace: workspace.Workspace,
-				Result:          "success",
-				Message:         message,
+			event, err = aerospace.SummonWindow(storageClient, aerospaceClient, mark, window, shouldFocus)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
 			}
 
 			if formatErr := formatter.Format(event); formatErr != nil {
@@ -151,6 +123,8 @@ Output format can be controlled with --output flag (text, json, csv).
 	}
 
 	summonCmd.Flags().BoolP("focus", "f", false, "Focus the window after summoning")
+	summonCmd.Flags().Bool("return", false, "Send the window back to the workspace it was summoned from")
+	summonCmd.MarkFlagsMutuallyExclusive("focus", "return")
 
 	return summonCmd
 }
@@ -6,11 +6,13 @@ import (
 	"github.com/cristianoliveira/aerospace-marks/cmd"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
 	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
 	"github.com/gkampitakis/go-snaps/snaps"
 	"go.uber.org/mock/gomock"
 
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
 	aerospacecli "github.com/cristianoliveira/aerospace-ipc/pkg/client"
 )
 
@@ -81,11 +83,14 @@ func TestSummonCmd(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			GetWindowIDByMark("mark1").
-			Return(1, nil).
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
 			Times(1)
 
 		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
 		// Mock list-workspaces command (used by GetFocusedWorkspace)
 		workspaceJSON := `[{"workspace":"workspace1","is-visible":true,"is-focused":true}]`
 		mockAeroSpaceConnection.EXPECT().
@@ -134,11 +139,14 @@ func TestSummonCmd(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			GetWindowIDByMark("mark1").
-			Return(1, nil).
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
 			Times(1)
 
 		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
 		workspaceJSON := `[{"workspace":"workspace1","is-visible":true,"is-focused":true}]`
 		mockAeroSpaceConnection.EXPECT().
 			SendCommand("list-workspaces", gomock.Any()).
@@ -185,11 +193,14 @@ func TestSummonCmd(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			GetWindowIDByMark("mark1").
-			Return(1, nil).
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
 			Times(1)
 
 		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
 		workspaceJSON := `[{"workspace":"workspace1","is-visible":true,"is-focused":true}]`
 		mockAeroSpaceConnection.EXPECT().
 			SendCommand("list-workspaces", gomock.Any()).
@@ -236,11 +247,14 @@ func TestSummonCmd(t *testing.T) {
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
 		strg.EXPECT().
-			GetWindowIDByMark("mark1").
-			Return(1, nil).
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
 			Times(1)
 
 		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1"},
+		}).Times(1)
 		workspaceJSON := `[{"workspace":"workspace1","is-visible":true,"is-focused":true}]`
 		mockAeroSpaceConnection.EXPECT().
 			SendCommand("list-workspaces", gomock.Any()).
@@ -260,6 +274,8 @@ func TestSummonCmd(t *testing.T) {
 					StdErr:        "",
 					ExitCode:      0,
 				}, nil).AnyTimes()
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, aerospace.Window{WindowID: 2, AppName: "app2"}).Times(1)
+		strg.EXPECT().PushFocusHistory(2).Return(nil).Times(1)
 		mockAeroSpaceConnection.EXPECT().
 			SendCommand("focus", gomock.Any()).
 			Return(
@@ -288,3 +304,132 @@ func TestSummonCmd(t *testing.T) {
 		snaps.MatchSnapshot(t, snapshot)
 	})
 }
+
+func TestSummonCmdOrigin(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("records the workspace the window came from - `summon mark1 -o json`", func(t *testing.T) {
+		args := []string{"summon", "mark1", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		windows := []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1", Workspace: "3"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+		strg.EXPECT().SetMarkOrigin("mark1", "3").Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(mockAeroSpaceConnection, "1").Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"1", "--window-id", "1"},
+		).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("doesn't record the origin when the window can't be moved", func(t *testing.T) {
+		args := []string{"summon", "mark1"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		windows := []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1", Workspace: "3"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+		strg.EXPECT().SetMarkOrigin(gomock.Any(), gomock.Any()).Times(0)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(mockAeroSpaceConnection, "1").Times(1)
+		mockAeroSpaceConnection.EXPECT().
+			SendCommand("move-node-to-workspace", []string{"1", "--window-id", "1"}).
+			Return(&aerospacecli.Response{ExitCode: 1, StdErr: "window 1 is gone"}, nil).
+			Times(1)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("sends the window back - `summon mark1 --return -o json`", func(t *testing.T) {
+		args := []string{"summon", "mark1", "--return", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		windows := []aerospace.Window{
+			{WindowID: 1, WindowTitle: "title1", AppName: "app1", Workspace: "1"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarkOrigin("mark1").Return("3", nil).Times(1)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+		strg.EXPECT().DeleteMarkOrigin("mark1").Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"3", "--window-id", "1"},
+		).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
@@ -0,0 +1,292 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
+)
+
+// SwapCmd represents the swap command.
+//
+//nolint:funlen,gocognit // SwapCmd resolves two windows and optionally swaps their marks
+func SwapCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	swapCmd := &cobra.Command{
+		Use:   "swap <identifier> [flags]",
+		Short: "Swap the focused window with a marked window",
+		Long: `Swap the focused window with a marked window
+
+Similar to sway's 'swap container with mark <identifier>'. The focused window
+(or the window marked with --with) and the marked window exchange workspaces.
+
+NOTE: AeroSpace can only move windows between workspaces by ID, so the
+position inside the workspace tree isn't swapped, and windows on the same
+workspace can only swap their marks.
+
+By default marks stay with their windows, use --swap-marks to also exchange
+the marks of both windows.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+
+Example:
+
+aerospace-marks swap a           # swap the focused window with the window marked 'a'
+aerospace-marks swap a --with b  # swap the windows marked 'a' and 'b'
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateArgIsNotEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := logger.GetDefaultLogger()
+			mark := args[0]
+			logger.LogDebug("SwapCmd called", "mark", mark)
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			withMark, err := cmd.Flags().GetString("with")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			swapMarks, err := cmd.Flags().GetBool("swap-marks")
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			resolver := aerospace.NewMarkResolver(storageClient, aerospaceClient)
+
+			markedWindow, err := resolver.ResolveMark(mark)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			client, err := aerospaceClient.Client()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			var otherWindow *windows.Window
+			if withMark == "" {
+				otherWindow, err = client.Windows().GetFocusedWindow()
+			} else {
+				otherWindow, err = resolver.ResolveMark(withMark)
+			}
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			if markedWindow.WindowID == otherWindow.WindowID {
+				stdout.ErrorAndExit(
+					errkind.New(errkind.InvalidInput, "cannot swap a window with itself"),
+				)
+				return
+			}
+
+			sourceWorkspace := markedWindow.Workspace
+			targetWorkspace := otherWindow.Workspace
+			sameWorkspace := sourceWorkspace == targetWorkspace
+
+			if sameWorkspace && !swapMarks {
+				stdout.ErrorAndExit(errkind.Errorf(
+					errkind.InvalidInput,
+					"windows %d and %d are both on workspace %s, nothing to swap",
+					markedWindow.WindowID,
+					otherWindow.WindowID,
+					sourceWorkspace,
+				))
+				return
+			}
+
+			if !sameWorkspace {
+				err = swapWorkspaces(aerospaceClient, markedWindow, otherWindow)
+				if err != nil {
+					stdout.ErrorAndExit(err)
+					return
+				}
+			}
+
+			logger.LogDebug(
+				"Windows swapped",
+				"windowID", markedWindow.WindowID,
+				"otherWindowID", otherWindow.WindowID,
+				"workspace", sourceWorkspace,
+				"targetWorkspace", targetWorkspace,
+			)
+
+			// Windows have exchanged workspaces, keep the stored metadata accurate
+			markedWindow.Workspace, otherWindow.Workspace = targetWorkspace, sourceWorkspace
+
+			if swapMarks {
+				if swapErr := swapWindowMarks(storageClient, markedWindow, otherWindow); swapErr != nil {
+					stdout.ErrorAndExit(swapErr)
+					return
+				}
+			}
+
+			message := fmt.Sprintf(
+				"Window %d swapped with window %d",
+				markedWindow.WindowID,
+				otherWindow.WindowID,
+			)
+			if sameWorkspace {
+				message = fmt.Sprintf(
+					"Marks of window %d swapped with window %d (same workspace, no window moved)",
+					markedWindow.WindowID,
+					otherWindow.WindowID,
+				)
+			} else if swapMarks {
+				message += " (marks swapped)"
+			}
+
+			event := format.OutputEvent{
+				Command:         "swap",
+				Action:          "swap",
+				WindowID:        markedWindow.WindowID,
+				AppName:         markedWindow.AppName,
+				Workspace:       sourceWorkspace,
+				TargetWorkspace: targetWorkspace,
+				Result:          "success",
+				Message:         message,
+			}
+
+			if formatErr := formatter.Format(event); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+		},
+	}
+
+	swapCmd.Flags().String("with", "", "Swap with the window of this mark instead of the focused window")
+	swapCmd.Flags().Bool("swap-marks", false, "Also exchange the marks of both windows")
+
+	return swapCmd
+}
+
+// swapWorkspaces moves each window to the workspace of the other. When the
+// second move fails, the first window is moved back so nothing is left half
+// swapped.
+func swapWorkspaces(
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	window *windows.Window,
+	otherWindow *windows.Window,
+) error {
+	err := moveWindowToWorkspace(aerospaceClient, window.WindowID, otherWindow.Workspace)
+	if err != nil {
+		return err
+	}
+
+	err = moveWindowToWorkspace(aerospaceClient, otherWindow.WindowID, window.Workspace)
+	if err != nil {
+		undoErr := moveWindowToWorkspace(aerospaceClient, window.WindowID, window.Workspace)
+		if undoErr != nil {
+			logger.GetDefaultLogger().LogError(
+				"failed to move the window back",
+				"windowID", window.WindowID,
+				"err", undoErr,
+			)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func moveWindowToWorkspace(
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	windowID int,
+	workspace string,
+) error {
+	client, err := aerospaceClient.Client()
+	if err != nil {
+		return err
+	}
+
+	return client.Workspaces().MoveWindowToWorkspaceWithOpts(
+		workspaces.MoveWindowToWorkspaceArgs{
+			WorkspaceName: workspace,
+		},
+		workspaces.MoveWindowToWorkspaceOpts{
+			WindowID: &windowID,
+		},
+	)
+}
+
+// swapWindowMarks moves every mark of a window to the other one and vice
+// versa, in a single transaction so the swap is one change of the marks.
+func swapWindowMarks(
+	storageClient storage.MarkStorage,
+	window *windows.Window,
+	otherWindow *windows.Window,
+) error {
+	return storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		marks, err := tx.GetMarksByWindowID(window.WindowID)
+		if err != nil {
+			return err
+		}
+
+		otherMarks, err := tx.GetMarksByWindowID(otherWindow.WindowID)
+		if err != nil {
+			return err
+		}
+
+		for _, mark := range marks {
+			err = tx.UpdateMarkWindow(
+				mark.Mark,
+				otherWindow.WindowID,
+				aerospace.NewWindowMetadata(*otherWindow),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, mark := range otherMarks {
+			err = tx.UpdateMarkWindow(
+				mark.Mark,
+				window.WindowID,
+				aerospace.NewWindowMetadata(*window),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
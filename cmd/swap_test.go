@@ -0,0 +1,332 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+func TestSwapCmd(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "title1", AppName: "app1", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "title2", AppName: "app2", Workspace: "2"},
+		{WindowID: 3, WindowTitle: "title3", AppName: "app3", Workspace: "2"},
+	}
+
+	t.Run("validate missing identifier", func(t *testing.T) {
+		args := []string{"swap"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("swaps the focused window with a marked window - `swap a`", func(t *testing.T) {
+		args := []string{"swap", "a"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("a").
+			Return(&queries.Mark{WindowID: 2, Mark: "a"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		gomock.InOrder(
+			mocks.ExpectCommand(
+				mockAeroSpaceConnection,
+				"move-node-to-workspace",
+				[]string{"1", "--window-id", "2"},
+			).Times(1),
+			mocks.ExpectCommand(
+				mockAeroSpaceConnection,
+				"move-node-to-workspace",
+				[]string{"2", "--window-id", "1"},
+			).Times(1),
+		)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("swaps two marked windows and their marks - `swap a --with b --swap-marks -o json`", func(t *testing.T) {
+		args := []string{"swap", "a", "--with", "b", "--swap-marks", "-o", "json"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("a").
+			Return(&queries.Mark{WindowID: 1, Mark: "a"}, nil).
+			Times(1)
+		strg.EXPECT().
+			GetWindowByMark("b").
+			Return(&queries.Mark{WindowID: 2, Mark: "b"}, nil).
+			Times(1)
+		// The marks are swapped in a single transaction
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().
+			GetMarksByWindowID(1).
+			Return([]queries.Mark{{WindowID: 1, Mark: "a"}}, nil).
+			Times(1)
+		strg.EXPECT().
+			GetMarksByWindowID(2).
+			Return([]queries.Mark{{WindowID: 2, Mark: "b"}, {WindowID: 2, Mark: "c"}}, nil).
+			Times(1)
+		// Metadata is stored with the workspaces after the swap
+		strg.EXPECT().
+			UpdateMarkWindow("a", 2, storage.WindowMetadata{AppName: "app2", WindowTitle: "title2", Workspace: "1"}).
+			Return(nil).
+			Times(1)
+		strg.EXPECT().
+			UpdateMarkWindow("b", 1, storage.WindowMetadata{AppName: "app1", WindowTitle: "title1", Workspace: "2"}).
+			Return(nil).
+			Times(1)
+		strg.EXPECT().
+			UpdateMarkWindow("c", 1, storage.WindowMetadata{AppName: "app1", WindowTitle: "title1", Workspace: "2"}).
+			Return(nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(2)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"2", "--window-id", "1"},
+		).Times(1)
+		mocks.ExpectCommand(
+			mockAeroSpaceConnection,
+			"move-node-to-workspace",
+			[]string{"1", "--window-id", "2"},
+		).Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails to swap windows of the same workspace - `swap a --with b`", func(t *testing.T) {
+		args := []string{"swap", "a", "--with", "b"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("a").
+			Return(&queries.Mark{WindowID: 2, Mark: "a"}, nil).
+			Times(1)
+		strg.EXPECT().
+			GetWindowByMark("b").
+			Return(&queries.Mark{WindowID: 3, Mark: "b"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(2)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "move-node-to-workspace", gomock.Any()).Times(0)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("swaps only the marks of windows of the same workspace - `swap a --with b --swap-marks`", func(t *testing.T) {
+		args := []string{"swap", "a", "--with", "b", "--swap-marks"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("a").
+			Return(&queries.Mark{WindowID: 2, Mark: "a"}, nil).
+			Times(1)
+		strg.EXPECT().
+			GetWindowByMark("b").
+			Return(&queries.Mark{WindowID: 3, Mark: "b"}, nil).
+			Times(1)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().
+			GetMarksByWindowID(2).
+			Return([]queries.Mark{{WindowID: 2, Mark: "a"}}, nil).
+			Times(1)
+		strg.EXPECT().
+			GetMarksByWindowID(3).
+			Return([]queries.Mark{{WindowID: 3, Mark: "b"}}, nil).
+			Times(1)
+		strg.EXPECT().
+			UpdateMarkWindow("a", 3, storage.WindowMetadata{AppName: "app3", WindowTitle: "title3", Workspace: "2"}).
+			Return(nil).
+			Times(1)
+		strg.EXPECT().
+			UpdateMarkWindow("b", 2, storage.WindowMetadata{AppName: "app2", WindowTitle: "title2", Workspace: "2"}).
+			Return(nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(2)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "move-node-to-workspace", gomock.Any()).Times(0)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("windows", windows),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("moves the window back when the second move fails - `swap a`", func(t *testing.T) {
+		args := []string{"swap", "a"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("a").
+			Return(&queries.Mark{WindowID: 2, Mark: "a"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		gomock.InOrder(
+			mocks.ExpectCommand(
+				mockAeroSpaceConnection,
+				"move-node-to-workspace",
+				[]string{"1", "--window-id", "2"},
+			).Times(1),
+			mockAeroSpaceConnection.EXPECT().
+				SendCommand("move-node-to-workspace", []string{"2", "--window-id", "1"}).
+				Return(&client.Response{ExitCode: 1, StdErr: "window 1 is gone"}, nil).
+				Times(1),
+			mocks.ExpectCommand(
+				mockAeroSpaceConnection,
+				"move-node-to-workspace",
+				[]string{"2", "--window-id", "2"},
+			).Times(1),
+		)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("fails to swap a window with itself", func(t *testing.T) {
+		args := []string{"swap", "a"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("a").
+			Return(&queries.Mark{WindowID: 1, Mark: "a"}, nil).
+			Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windows).Times(1)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, windows[0]).Times(1)
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+}
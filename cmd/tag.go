@@ -0,0 +1,230 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/cli"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// TagCmd represents the tag command.
+func TagCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	tagCmd := &cobra.Command{
+		Use:   "tag <tag> [flags]",
+		Short: "Add a window to a group of windows sharing a tag",
+		Long: `Add a window to a group of windows sharing a tag
+
+Unlike marks, a tag can be set on many windows and a window can have many
+tags. Tagged windows can be acted on together with summon-group and
+focus-group.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+
+Example:
+
+  aerospace-marks tag meeting # Tags the focused window
+  aerospace-marks tag meeting --window-id 42
+  aerospace-marks summon-group meeting
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateArgIsNotEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			tag := args[0]
+			logger.GetDefaultLogger().LogDebug("TagCmd called", "tag", tag)
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			winArgID, _ := cmd.Flags().GetString("window-id")
+			window, err := flagOrFocusedWindow(aerospaceClient, winArgID)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			err = storageClient.AddTag(window.WindowID, tag, window.AppBundleID)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			event := format.OutputEvent{
+				Command:   "tag",
+				Action:    "tag",
+				WindowID:  window.WindowID,
+				AppName:   window.AppName,
+				Workspace: window.Workspace,
+				Result:    "success",
+				Message:   fmt.Sprintf("Window %d tagged with '%s'", window.WindowID, tag),
+			}
+
+			if formatErr := formatter.Format(event); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+		},
+	}
+
+	tagCmd.Flags().String("window-id", "", "Window ID to tag (default: focused window)")
+
+	return tagCmd
+}
+
+// UntagCmd represents the untag command.
+//
+//nolint:funlen // UntagCmd handles both a single window and --all
+func UntagCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
+	untagCmd := &cobra.Command{
+		Use:   "untag <tag> [flags]",
+		Short: "Remove a window from a group of windows sharing a tag",
+		Long: `Remove a window from a group of windows sharing a tag
+
+Removes the tag from the focused window, the window given with --window-id
+or, with --all, from every window.
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+`,
+		Args: cobra.MatchAll(
+			cobra.ExactArgs(1),
+			cli.ValidateArgIsNotEmpty,
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			tag := args[0]
+			logger.GetDefaultLogger().LogDebug("UntagCmd called", "tag", tag)
+
+			// Get and validate output format early
+			outputFormat, err := cmd.Flags().GetString("output")
+			if err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to get output flag: %w", err))
+				return
+			}
+
+			// Default to text if not specified
+			if outputFormat == "" {
+				outputFormat = string(format.OutputFormatText)
+			}
+
+			formatter, err := newOutputEventFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			all, _ := cmd.Flags().GetBool("all")
+			event := format.OutputEvent{
+				Command: "untag",
+				Action:  "untag",
+				Result:  "success",
+			}
+
+			if all {
+				deleted, deleteErr := storageClient.DeleteTag(tag)
+				if deleteErr != nil {
+					stdout.ErrorAndExit(deleteErr)
+					return
+				}
+				if deleted == 0 {
+					stdout.ErrorAndExit(
+						errkind.Errorf(errkind.TagNotFound, "no window tagged with '%s'", tag),
+					)
+					return
+				}
+				event.Message = fmt.Sprintf("Removed tag '%s' from %d windows", tag, deleted)
+			} else {
+				winArgID, _ := cmd.Flags().GetString("window-id")
+				window, windowErr := flagOrFocusedWindow(aerospaceClient, winArgID)
+				if windowErr != nil {
+					stdout.ErrorAndExit(windowErr)
+					return
+				}
+
+				deleted, deleteErr := storageClient.DeleteWindowTag(window.WindowID, tag)
+				if deleteErr != nil {
+					stdout.ErrorAndExit(deleteErr)
+					return
+				}
+				if deleted == 0 {
+					stdout.ErrorAndExit(errkind.Errorf(
+						errkind.TagNotFound,
+						"window %d is not tagged with '%s'",
+						window.WindowID,
+						tag,
+					))
+					return
+				}
+
+				event.WindowID = window.WindowID
+				event.AppName = window.AppName
+				event.Workspace = window.Workspace
+				event.Message = fmt.Sprintf("Removed tag '%s' from window %d", tag, window.WindowID)
+			}
+
+			if formatErr := formatter.Format(event); formatErr != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", formatErr))
+				return
+			}
+		},
+	}
+
+	untagCmd.Flags().String("window-id", "", "Window ID to untag (default: focused window)")
+	untagCmd.Flags().Bool("all", false, "Remove the tag from every window")
+	untagCmd.MarkFlagsMutuallyExclusive("window-id", "all")
+
+	return untagCmd
+}
+
+// flagOrFocusedWindow returns the window with the ID given in a --window-id
+// flag or the focused window when the flag is empty.
+func flagOrFocusedWindow(
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+	winArgID string,
+) (*windows.Window, error) {
+	if winArgID == "" {
+		client, err := aerospaceClient.Client()
+		if err != nil {
+			return nil, err
+		}
+		return client.Windows().GetFocusedWindow()
+	}
+
+	windowID, err := strconv.Atoi(strings.TrimSpace(winArgID))
+	if err != nil || windowID <= 0 {
+		return nil, errkind.Errorf(errkind.InvalidInput, "invalid window ID '%s'", winArgID)
+	}
+
+	return aerospaceClient.GetWindowByID(windowID)
+}
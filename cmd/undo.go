@@ -0,0 +1,87 @@
+/*
+Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// UndoCmd reverts the last changes of the marks.
+func UndoCmd(storageClient storage.MarkStorage) *cobra.Command {
+	return &cobra.Command{
+		Use:   "undo [n]",
+		Short: "Revert the last n changes of the marks",
+		Long: `Revert the last n changes of the marks
+
+Sets the marks changed by the last n changes, 1 by default, back to their
+previous state, the most recent change first. Only the marks are restored, no
+window is moved or focused. See history for the changes that can be undone.
+
+Example:
+
+  aerospace-marks undo
+  aerospace-marks undo 3
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			n := 1
+			if len(args) > 0 {
+				var err error
+				n, err = strconv.Atoi(args[0])
+				if err != nil || n <= 0 {
+					stdout.ErrorAndExit(errkind.Errorf(
+						errkind.InvalidInput,
+						"invalid number of changes '%s', expected 1 or more",
+						args[0],
+					))
+					return
+				}
+			}
+
+			reverted, err := storageClient.Undo(n)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			for _, change := range reverted {
+				fmt.Fprintln(os.Stdout, "Undid "+changeText(change))
+			}
+		},
+	}
+}
+
+// RedoCmd applies again the last change reverted by undo.
+func RedoCmd(storageClient storage.MarkStorage) *cobra.Command {
+	return &cobra.Command{
+		Use:   "redo",
+		Short: "Apply again the last change reverted by undo",
+		Long: `Apply again the last change reverted by undo
+
+Changes reverted by undo can be applied again until the marks are changed
+otherwise, like in an editor.
+
+Example:
+
+  aerospace-marks undo && aerospace-marks redo
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			applied, err := storageClient.Redo()
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+
+			fmt.Fprintln(os.Stdout, "Redid "+changeText(*applied))
+		},
+	}
+}
@@ -4,53 +4,270 @@ Copyright © 2025 Cristian Oliveira license@cristianoliveira.dev
 package cmd
 
 import (
+	"bufio"
 	"fmt"
-	"os"
+	"strings"
 
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
-
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
 	"github.com/spf13/cobra"
 )
 
 // UnmarkCmd represents the unmark command.
-func UnmarkCmd(storageClient storage.MarkStorage) *cobra.Command {
+func UnmarkCmd(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *cobra.Command {
 	unmarkCmd := &cobra.Command{
-		Use:   "unmark",
-		Short: "Unmark one or more windows by identifier",
-		Long: `Unmark one or more windows by identifier.
+		Use:   "unmark [<identifier|glob|/regex/>...] [flags]",
+		Short: "Remove marks by identifier, pattern or window",
+		Long: `Remove marks by identifier, pattern or window
 
-unmark [<identifier>]
+Each argument is a mark, a glob like 'web:*' or a regex like '/^web:/'. Either
+every matched mark is removed or, when an argument matches no mark, none.
 
-unmark cmd will remove identifier from the list of current marks on a window. If identifier is omitted, all marks are removed.
-	`,
+Use --window-id or --focused to remove the marks of a window, and --all to
+remove every mark. --all asks for confirmation when stdin is a terminal, --yes
+skips it.
 
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) == 0 {
-				rowsEffected, err := storageClient.DeleteAllMarks()
-				if err != nil {
-					return err
-				}
+Output format can be controlled with --output flag (text, json, ndjson, yaml, csv, tsv, template).
+
+Example:
 
-				fmt.Fprintf(os.Stdout, "Removed %d marks\n", rowsEffected)
-				return nil
+  aerospace-marks unmark term mail
+  aerospace-marks unmark 'web:*'
+  aerospace-marks unmark --focused
+  aerospace-marks unmark --all --yes
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputFormat, _ := cmd.Flags().GetString("output")
+			formatter, err := newListOutputFormatter(cmd, outputFormat)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
 			}
 
-			var count int
-			for _, identifier := range args {
-				rowsAffected, err := storageClient.DeleteByMark(identifier)
-				if err != nil {
-					return err
-				}
-				if rowsAffected == 0 {
-					return fmt.Errorf("mark '%s' not found", identifier)
+			removed, err := runUnmark(cmd, args, storageClient, aerospaceClient)
+			if err != nil {
+				stdout.ErrorAndExit(err)
+				return
+			}
+			if len(removed) == 0 {
+				if err = formatter.FormatEmpty("No marks to remove"); err != nil {
+					stdout.ErrorAndExit(fmt.Errorf("failed to format empty output: %w", err))
 				}
-				count += int(rowsAffected)
+				return
 			}
 
-			fmt.Fprintf(os.Stdout, "Removed %d marks\n", count)
-			return nil
+			removedWindows := make([]format.MarkedWindow, 0, len(removed))
+			for _, mark := range removed {
+				removedWindows = append(removedWindows, format.MarkedWindow{
+					Mark:        mark.Mark,
+					WindowID:    mark.WindowID,
+					AppName:     mark.AppName,
+					WindowTitle: mark.WindowTitle,
+					Workspace:   mark.Workspace,
+					AppBundleID: mark.AppBundleID,
+				})
+			}
+			if err = formatter.Format(removedWindows); err != nil {
+				stdout.ErrorAndExit(fmt.Errorf("failed to format output: %w", err))
+				return
+			}
 		},
 	}
 
+	unmarkCmd.Flags().Bool("all", false, "Remove every mark")
+	unmarkCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation with --all")
+	unmarkCmd.Flags().Int("window-id", 0, "Remove the marks of the window with this ID")
+	unmarkCmd.Flags().Bool("focused", false, "Remove the marks of the focused window")
+	unmarkCmd.MarkFlagsMutuallyExclusive("all", "window-id", "focused")
+
 	return unmarkCmd
 }
+
+// runUnmark removes the marks selected by the arguments or flags and
+// returns them.
+func runUnmark(
+	cmd *cobra.Command,
+	args []string,
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) ([]queries.Mark, error) {
+	all, _ := cmd.Flags().GetBool("all")
+	windowID, _ := cmd.Flags().GetInt("window-id")
+	focused, _ := cmd.Flags().GetBool("focused")
+
+	byWindow := cmd.Flags().Changed("window-id") || focused
+	switch {
+	case len(args) > 0 && (all || byWindow):
+		return nil, errkind.New(
+			errkind.InvalidInput,
+			"marks can't be given with --all, --window-id or --focused",
+		)
+	case all:
+		yes, _ := cmd.Flags().GetBool("yes")
+		return unmarkAll(cmd, storageClient, yes)
+	case focused:
+		client, err := aerospaceClient.Client()
+		if err != nil {
+			return nil, err
+		}
+		window, err := client.Windows().GetFocusedWindow()
+		if err != nil {
+			return nil, err
+		}
+		return unmarkWindow(storageClient, window.WindowID)
+	case byWindow:
+		if windowID <= 0 {
+			return nil, errkind.Errorf(errkind.InvalidInput, "invalid window ID %d", windowID)
+		}
+		return unmarkWindow(storageClient, windowID)
+	case len(args) > 0:
+		return unmarkPatterns(storageClient, args)
+	default:
+		return nil, errkind.New(
+			errkind.InvalidInput,
+			"no marks given, use --all to remove every mark",
+		)
+	}
+}
+
+// unmarkPatterns removes the marks matching the patterns, see markMatcher.
+// Either every mark is removed or, when a pattern matches none, none.
+func unmarkPatterns(storageClient storage.MarkStorage, patterns []string) ([]queries.Mark, error) {
+	matchers := make([]func(mark string) bool, 0, len(patterns))
+	for _, pattern := range patterns {
+		matcher, err := markMatcher(pattern)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	var removed []queries.Mark
+	err := storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		marks, err := tx.GetMarks()
+		if err != nil {
+			return err
+		}
+
+		matched := make(map[string]bool, len(marks))
+		for i, matcher := range matchers {
+			found := false
+			for _, mark := range marks {
+				if !matcher(mark.Mark) {
+					continue
+				}
+				found = true
+				if !matched[mark.Mark] {
+					matched[mark.Mark] = true
+					removed = append(removed, mark)
+				}
+			}
+			if !found {
+				return errkind.Errorf(errkind.MarkNotFound, "mark '%s' not found", patterns[i])
+			}
+		}
+
+		for _, mark := range removed {
+			if _, err = tx.DeleteByMark(mark.Mark); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// unmarkWindow removes the marks of a window.
+func unmarkWindow(storageClient storage.MarkStorage, windowID int) ([]queries.Mark, error) {
+	var removed []queries.Mark
+	err := storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		marks, err := tx.GetMarksByWindowID(windowID)
+		if err != nil {
+			return err
+		}
+		if len(marks) == 0 {
+			return errkind.Errorf(errkind.MarkNotFound, "window %d has no marks", windowID)
+		}
+
+		if _, err = tx.DeleteByWindow(windowID); err != nil {
+			return err
+		}
+		removed = marks
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// unmarkAll removes every mark, once confirmed when stdin is a terminal
+// unless yes is set.
+func unmarkAll(
+	cmd *cobra.Command,
+	storageClient storage.MarkStorage,
+	yes bool,
+) ([]queries.Mark, error) {
+	// Asked before the transaction, which would lock the database meanwhile
+	if !yes && stdout.IsInteractive(cmd.InOrStdin()) {
+		marks, err := storageClient.GetMarks()
+		if err != nil {
+			return nil, err
+		}
+		if len(marks) == 0 {
+			return nil, nil
+		}
+
+		if !confirm(cmd, fmt.Sprintf("Remove all %d marks? [y/N] ", len(marks))) {
+			return nil, errkind.New(errkind.InvalidInput, "aborted, no marks were removed")
+		}
+	}
+
+	var removed []queries.Mark
+	err := storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		marks, err := tx.GetMarks()
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.DeleteAllMarks(); err != nil {
+			return err
+		}
+		removed = marks
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// confirm asks prompt on stderr and reports whether the answer read from
+// stdin is yes.
+func confirm(cmd *cobra.Command, prompt string) bool {
+	fmt.Fprint(cmd.ErrOrStderr(), prompt)
+
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
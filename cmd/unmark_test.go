@@ -1,17 +1,33 @@
 package cmd_test
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
 	"github.com/cristianoliveira/aerospace-marks/cmd"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
 	"github.com/cristianoliveira/aerospace-marks/internal/testutils"
 	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
 )
 
 func TestUnmarkCommand(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	marks := []queries.Mark{
+		{WindowID: 1, Mark: "mark1", AppName: "Alacritty", Workspace: "1"},
+		{WindowID: 2, Mark: "web:docs", AppName: "Brave", WindowTitle: "docs"},
+		{WindowID: 2, Mark: "web:mail", AppName: "Brave", WindowTitle: "mail"},
+		{WindowID: 3, Mark: "mail", AppName: "Mail"},
+	}
+
 	t.Run("unmarks a mark from a window - `marks unmark mark1`", func(t *testing.T) {
 		// t.Skip("Skipping")
 		command := "unmark"
@@ -21,6 +37,8 @@ func TestUnmarkCommand(t *testing.T) {
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
 		strg.EXPECT().
 			DeleteByMark("mark1").
 			Return(int64(1), nil).
@@ -41,18 +59,132 @@ func TestUnmarkCommand(t *testing.T) {
 		snaps.MatchSnapshot(t, snapshot)
 	})
 
-	t.Run("unmarks all marks from a window - `marks unmark`", func(t *testing.T) {
-		// t.Skip("Skipping")
-		command := "unmark"
-		args := []string{command}
+	t.Run("unmarks marks matching patterns - `unmark 'web:*' mail -o json`", func(t *testing.T) {
+		args := []string{"unmark", "web:*", "mail", "-o", "json"}
 
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+		for _, mark := range []string{"web:docs", "web:mail", "mail"} {
+			strg.EXPECT().DeleteByMark(mark).Return(int64(1), nil).Times(1)
+		}
+
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", marks),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("matches slashes with a glob star - `unmark 'proj/*' -o json`", func(t *testing.T) {
+		args := []string{"unmark", "proj/*", "-o", "json"}
+
+		nested := []queries.Mark{
+			{WindowID: 1, Mark: "proj/api", AppName: "Alacritty"},
+			{WindowID: 2, Mark: "proj/web/docs", AppName: "Brave"},
+			{WindowID: 3, Mark: "notes/proj", AppName: "Notes"},
+		}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().GetMarks().Return(nested, nil).Times(1)
+		for _, mark := range []string{"proj/api", "proj/web/docs"} {
+			strg.EXPECT().DeleteByMark(mark).Return(int64(1), nil).Times(1)
+		}
+
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+			Contexts: []testutils.SnapshotContext{
+				testutils.Context("marks", nested),
+			},
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("unmarks the focused window - `marks unmark --focused`", func(t *testing.T) {
+		args := []string{"unmark", "--focused"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().GetMarksByWindowID(2).Return(marks[1:3], nil).Times(1)
+		strg.EXPECT().DeleteByWindow(2).Return(int64(2), nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(conn, aerospace.Window{WindowID: 2, AppName: "Brave"}).
+			Times(1)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("unmarks a window by ID - `marks unmark --window-id 3 -o ndjson`", func(t *testing.T) {
+		args := []string{"unmark", "--window-id", "3", "-o", "ndjson"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().GetMarksByWindowID(3).Return(marks[3:], nil).Times(1)
+		strg.EXPECT().DeleteByWindow(3).Return(int64(1), nil).Times(1)
+
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+		out, err := testutils.CmdExecute(rootCmd, args...)
+		require.NoError(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stdout:  out,
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
+	t.Run("unmarks all marks without confirmation - `unmark --all --yes`", func(t *testing.T) {
+		args := []string{"unmark", "--all", "--yes"}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
 		strg.EXPECT().
 			DeleteAllMarks().
-			Return(int64(2), nil).
+			Return(int64(len(marks)), nil).
 			Times(1)
 
 		aerospaceClient := &testutils.MockEmptyAerspaceMarkWindows{}
@@ -70,6 +202,90 @@ func TestUnmarkCommand(t *testing.T) {
 		snaps.MatchSnapshot(t, snapshot)
 	})
 
+	for _, tc := range []struct {
+		answer  string
+		confirm bool
+	}{
+		{answer: "y\n", confirm: true},
+		{answer: "\n", confirm: false},
+	} {
+		t.Run(fmt.Sprintf("asks to confirm --all - answering %q", tc.answer), func(t *testing.T) {
+			args := []string{"unmark", "--all"}
+
+			//nolint:reassign // Test utility needs to modify package variable
+			stdout.ForceInteractive = true
+			t.Cleanup(func() {
+				//nolint:reassign // Test utility needs to restore package variable
+				stdout.ForceInteractive = false
+			})
+			//nolint:reassign // Test utility needs to modify package variable
+			stdout.ShouldExit = false
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, strg := mocks.MockStorageDBClient(ctrl)
+			if tc.confirm {
+				mocks.ExpectTransaction(strg).Times(1)
+				strg.EXPECT().GetMarks().Return(marks, nil).Times(2)
+				strg.EXPECT().DeleteAllMarks().Return(int64(len(marks)), nil).Times(1)
+			} else {
+				strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+			}
+
+			aerospaceClient := &testutils.MockEmptyAerspaceMarkWindows{}
+
+			var prompt bytes.Buffer
+			rootCmd := cmd.NewRootCmd(strg, aerospaceClient)
+			rootCmd.SetErr(&prompt)
+			out, err := testutils.CmdExecuteWithStdin(rootCmd, tc.answer, args...)
+			stderr := ""
+			if tc.confirm {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				stderr = err.Error()
+			}
+
+			snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+				Command: testutils.CommandString(args...),
+				Stdout:  out,
+				Stderr:  stderr,
+				Contexts: []testutils.SnapshotContext{
+					testutils.Context("prompt", prompt.String()),
+					testutils.Context("answer", tc.answer),
+				},
+			})
+			snaps.MatchSnapshot(t, snapshot)
+		})
+	}
+
+	t.Run("fails without marks or --all - `marks unmark`", func(t *testing.T) {
+		// t.Skip("Skipping")
+		command := "unmark"
+		args := []string{command}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+
+		aerospaceClient := &testutils.MockEmptyAerspaceMarkWindows{}
+
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
+		cmd := cmd.NewRootCmd(strg, aerospaceClient)
+		_, err := testutils.CmdExecute(cmd, args...)
+		require.Error(t, err)
+
+		snapshot := testutils.RenderSnapshotSpec(testutils.SnapshotSpec{
+			Command: testutils.CommandString(args...),
+			Stderr:  err.Error(),
+		})
+		snaps.MatchSnapshot(t, snapshot)
+	})
+
 	t.Run("unmarks --help", func(t *testing.T) {
 		// t.Skip("Skipping")
 		command := "unmark"
@@ -97,19 +313,20 @@ func TestUnmarkCommand(t *testing.T) {
 	t.Run("fails when mark not found", func(t *testing.T) {
 		// t.Skip("Skipping")
 		command := "unmark"
-		args := []string{command, "unkown"}
+		args := []string{command, "mark1", "unkown"}
 
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		_, strg := mocks.MockStorageDBClient(ctrl)
-		strg.EXPECT().
-			DeleteByMark("unkown").
-			Return(int64(0), nil).
-			Times(1)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
 
 		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
 
+		//nolint:reassign // Test utility needs to modify package variable
+		stdout.ShouldExit = false
+
 		cmd := cmd.NewRootCmd(strg, aerospaceClient)
 		out, err := testutils.CmdExecute(cmd, args...)
 		if err == nil {
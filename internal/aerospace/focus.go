@@ -0,0 +1,113 @@
+package aerospace
+
+import (
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+)
+
+// ErrNoPreviousFocus is returned when there is no window to focus back to.
+var ErrNoPreviousFocus = errkind.New(errkind.InvalidInput, "no previous window to focus")
+
+// FocusSwitcher moves focus between windows, recording the window that had
+// focus before each switch so it is possible to go back and forth like i3's
+// 'workspace back_and_forth'.
+type FocusSwitcher struct {
+	storage   storage.MarkStorage
+	aerospace AerosSpaceMarkWindows
+}
+
+// NewFocusSwitcher creates a FocusSwitcher.
+func NewFocusSwitcher(
+	storageClient storage.MarkStorage,
+	aerospaceClient AerosSpaceMarkWindows,
+) *FocusSwitcher {
+	return &FocusSwitcher{
+		storage:   storageClient,
+		aerospace: aerospaceClient,
+	}
+}
+
+// Focus moves focus to the given window.
+//
+// When the window already has focus and toggle is set, focus goes back to the
+// previous window instead. Returns the ID of the window that got focus.
+func (s *FocusSwitcher) Focus(windowID int, toggle bool) (int, error) {
+	currentID := s.focusedWindowID()
+
+	if currentID == windowID {
+		if toggle {
+			previousID, err := s.storage.GetPreviousFocus(currentID)
+			if err != nil {
+				return 0, err
+			}
+			if previousID != 0 {
+				return s.switchTo(currentID, previousID)
+			}
+		}
+
+		// Nothing to switch from, focusing again is harmless
+		return windowID, s.setFocus(windowID)
+	}
+
+	return s.switchTo(currentID, windowID)
+}
+
+// Back moves focus to the window that had focus before the last switch.
+//
+// Returns ErrNoPreviousFocus if there is none.
+func (s *FocusSwitcher) Back() (int, error) {
+	currentID := s.focusedWindowID()
+
+	previousID, err := s.storage.GetPreviousFocus(currentID)
+	if err != nil {
+		return 0, err
+	}
+	if previousID == 0 {
+		return 0, ErrNoPreviousFocus
+	}
+
+	return s.switchTo(currentID, previousID)
+}
+
+// switchTo records currentID in the history and focuses windowID.
+func (s *FocusSwitcher) switchTo(currentID int, windowID int) (int, error) {
+	if currentID != 0 {
+		if err := s.storage.PushFocusHistory(currentID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := s.setFocus(windowID); err != nil {
+		return 0, err
+	}
+
+	return windowID, nil
+}
+
+// setFocus focuses the window with windowID.
+func (s *FocusSwitcher) setFocus(windowID int) error {
+	client, err := s.aerospace.Client()
+	if err != nil {
+		return err
+	}
+
+	return client.Focus().SetFocusByWindowID(windowID)
+}
+
+// focusedWindowID returns the ID of the focused window or 0 if no window has
+// focus (e.g. an empty workspace).
+func (s *FocusSwitcher) focusedWindowID() int {
+	client, err := s.aerospace.Client()
+	if err != nil {
+		logger.GetDefaultLogger().LogDebug("AeroSpace unavailable", "err", err)
+		return 0
+	}
+	window, err := client.Windows().GetFocusedWindow()
+	if err != nil {
+		logger.GetDefaultLogger().LogDebug("No focused window", "err", err)
+		return 0
+	}
+
+	return window.WindowID
+}
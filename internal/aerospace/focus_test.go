@@ -0,0 +1,49 @@
+package aerospace_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+func TestFocusSwitcherBack(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	focused := windows.Window{WindowID: 5, AppName: "Alacritty", Workspace: "1"}
+
+	t.Run("focuses the previous window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetPreviousFocus(5).Return(3, nil).Times(1)
+		strg.EXPECT().PushFocusHistory(5).Return(nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, focused).Times(1)
+		mocks.ExpectCommand(mockAeroSpaceConnection, "focus", []string{"--window-id", "3"}).Times(1)
+
+		windowID, err := aerospace.NewFocusSwitcher(strg, aerospaceClient).Back()
+		require.NoError(t, err)
+		assert.Equal(t, 3, windowID)
+	})
+
+	t.Run("fails as invalid input without a previous window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetPreviousFocus(5).Return(0, nil).Times(1)
+
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(mockAeroSpaceConnection, focused).Times(1)
+
+		_, err := aerospace.NewFocusSwitcher(strg, aerospaceClient).Back()
+		require.ErrorIs(t, err, aerospace.ErrNoPreviousFocus)
+		assert.Equal(t, errkind.ExitInvalidInput, errkind.ExitCode(err))
+	})
+}
@@ -0,0 +1,61 @@
+package aerospace
+
+import (
+	"sync"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+
+	aerospacecli "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// LazyAeroSpaceWindows connects to AeroSpace the first time it is used, so
+// commands that don't need AeroSpace, or that delegate to the daemon, never
+// connect.
+type LazyAeroSpaceWindows struct {
+	mu      sync.Mutex
+	windows *DefaultAeroSpaceWindows
+}
+
+// NewLazyAeroSpaceClient creates a LazyAeroSpaceWindows, see NewAeroSpaceClient.
+func NewLazyAeroSpaceClient() *LazyAeroSpaceWindows {
+	return &LazyAeroSpaceWindows{}
+}
+
+// Connect connects to AeroSpace unless already connected. A failed
+// connection is tried again on the next call.
+func (l *LazyAeroSpaceWindows) Connect() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.windows != nil {
+		return nil
+	}
+
+	client, err := NewAeroSpaceClient()
+	if err != nil {
+		return errkind.Wrap(errkind.IPCUnavailable, err)
+	}
+	l.windows = client
+
+	return nil
+}
+
+// Client returns the AeroSpaceWM client, connecting first.
+//
+// Returns an IPCUnavailable error if AeroSpace can't be reached.
+func (l *LazyAeroSpaceWindows) Client() (*aerospacecli.AeroSpaceWM, error) {
+	if err := l.Connect(); err != nil {
+		return nil, err
+	}
+
+	return l.windows.Client()
+}
+
+func (l *LazyAeroSpaceWindows) GetWindowByID(windowID int) (*windows.Window, error) {
+	if err := l.Connect(); err != nil {
+		return nil, err
+	}
+
+	return l.windows.GetWindowByID(windowID)
+}
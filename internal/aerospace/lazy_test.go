@@ -0,0 +1,38 @@
+package aerospace_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	aerospacecli_mock "github.com/cristianoliveira/aerospace-marks/internal/mocks/aerospacecli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	aerospacecli "github.com/cristianoliveira/aerospace-ipc/pkg/client"
+)
+
+func TestLazyAeroSpaceWindows(t *testing.T) {
+	t.Run("returns an IPCUnavailable error when AeroSpace can't be reached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		connector := aerospacecli_mock.NewMockAeroSpaceConnector(ctrl)
+		// A failed connection is tried again on the next call
+		connector.EXPECT().
+			Connect().
+			Return(nil, errors.New("dial unix /tmp/aerospace.sock: connect: no such file")).
+			Times(2)
+		aerospacecli.SetDefaultConnector(connector)
+
+		client := aerospace.NewLazyAeroSpaceClient()
+
+		wm, err := client.Client()
+		require.Error(t, err)
+		assert.Nil(t, wm)
+		assert.Equal(t, errkind.IPCUnavailable, errkind.Of(err))
+
+		_, err = client.GetWindowByID(1)
+		assert.Equal(t, errkind.IPCUnavailable, errkind.Of(err))
+	})
+}
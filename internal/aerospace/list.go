@@ -0,0 +1,53 @@
+package aerospace
+
+import (
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// MarkedWindows returns the marks of the live windows in windowsList with
+// their tags, in the order of marks. Marks of windows that no longer exist
+// are skipped.
+func MarkedWindows(
+	storageClient storage.MarkStorage,
+	marks []queries.Mark,
+	windowsList []windows.Window,
+) ([]format.MarkedWindow, error) {
+	tagsByWindow, err := TagsByWindow(storageClient, windowsList)
+	if err != nil {
+		return nil, err
+	}
+
+	windowsByID := make(map[int]*windows.Window, len(windowsList))
+	for i := range windowsList {
+		windowsByID[windowsList[i].WindowID] = &windowsList[i]
+	}
+
+	markedWindows := make([]format.MarkedWindow, 0, len(marks))
+	for _, mark := range marks {
+		window, ok := windowsByID[mark.WindowID]
+		if !ok {
+			continue
+		}
+		markedWindow := NewMarkedWindow(mark.Mark, window)
+		markedWindow.Tags = tagsByWindow[window.WindowID]
+		markedWindows = append(markedWindows, markedWindow)
+	}
+
+	return markedWindows, nil
+}
+
+// NewMarkedWindow returns the mark of window, without its tags.
+func NewMarkedWindow(mark string, window *windows.Window) format.MarkedWindow {
+	return format.MarkedWindow{
+		Mark:        mark,
+		WindowID:    window.WindowID,
+		AppName:     window.AppName,
+		WindowTitle: window.WindowTitle,
+		Workspace:   window.Workspace,
+		AppBundleID: window.AppBundleID,
+	}
+}
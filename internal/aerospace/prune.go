@@ -0,0 +1,148 @@
+package aerospace
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/constants"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// FindOrphanMarks returns the marks whose window no longer exists.
+//
+// A mark is only considered orphan when its window ID is gone AND no live
+// window matches its stored metadata, so marks that can still be re-bound
+// are kept.
+func FindOrphanMarks(marks []queries.Mark, windowsList []windows.Window) []queries.Mark {
+	liveWindows := make(map[int]bool, len(windowsList))
+	for _, window := range windowsList {
+		liveWindows[window.WindowID] = true
+	}
+
+	orphans := make([]queries.Mark, 0)
+	for _, mark := range marks {
+		if liveWindows[mark.WindowID] {
+			continue
+		}
+		if FindWindowByMetadata(mark, windowsList) != nil {
+			continue
+		}
+		orphans = append(orphans, mark)
+	}
+
+	return orphans
+}
+
+// PruneOrphanMarks deletes the given orphan marks from the storage.
+//
+// Orphans are deleted per window with DeleteByWindow. If a window still has
+// marks that aren't orphan, only its orphan marks are deleted. Either every
+// orphan is deleted or, on error, none.
+// Returns the number of deleted marks.
+func PruneOrphanMarks(
+	storageClient storage.MarkStorage,
+	allMarks []queries.Mark,
+	orphans []queries.Mark,
+) (int64, error) {
+	orphanMarks := make(map[string]bool, len(orphans))
+	windowIDs := make([]int, 0)
+	orphansByWindow := make(map[int][]queries.Mark)
+	for _, orphan := range orphans {
+		orphanMarks[orphan.Mark] = true
+		if _, ok := orphansByWindow[orphan.WindowID]; !ok {
+			windowIDs = append(windowIDs, orphan.WindowID)
+		}
+		orphansByWindow[orphan.WindowID] = append(orphansByWindow[orphan.WindowID], orphan)
+	}
+
+	hasLiveMarks := make(map[int]bool)
+	for _, mark := range allMarks {
+		if !orphanMarks[mark.Mark] {
+			hasLiveMarks[mark.WindowID] = true
+		}
+	}
+
+	var deleted int64
+	err := storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		for _, windowID := range windowIDs {
+			if !hasLiveMarks[windowID] {
+				rowsAffected, err := tx.DeleteByWindow(windowID)
+				if err != nil {
+					return err
+				}
+				deleted += rowsAffected
+				continue
+			}
+
+			for _, orphan := range orphansByWindow[windowID] {
+				rowsAffected, err := tx.DeleteByMark(orphan.Mark)
+				if err != nil {
+					return err
+				}
+				deleted += rowsAffected
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// AutoPrune removes the orphan marks among marks, see FindOrphanMarks, and
+// the orphan tags, see PruneOrphanTags, for commands that already fetched
+// all windows. Commands must not fail because of a failed cleanup, errors
+// are only logged.
+func AutoPrune(
+	storageClient storage.MarkStorage,
+	marks []queries.Mark,
+	windowsList []windows.Window,
+) {
+	log := logger.GetDefaultLogger()
+
+	orphans := FindOrphanMarks(marks, windowsList)
+	if len(orphans) > 0 {
+		deleted, err := PruneOrphanMarks(storageClient, marks, orphans)
+		if err != nil {
+			log.LogError("failed to auto prune marks", "err", err)
+			return
+		}
+		log.LogInfo("Auto pruned orphan marks", "deleted", deleted)
+	}
+
+	deletedTags, err := PruneOrphanTags(storageClient, windowsList)
+	if err != nil {
+		log.LogError("failed to auto prune tags", "err", err)
+	} else if deletedTags > 0 {
+		log.LogInfo("Auto pruned orphan tags", "deleted", deletedTags)
+	}
+}
+
+// IsAutoPruneEnabled reports whether orphan marks should be removed
+// automatically by commands that already fetch all windows.
+func IsAutoPruneEnabled() bool {
+	value := strings.TrimSpace(os.Getenv(constants.EnvAeroSpaceMarksAutoPrune))
+	if value == "" {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.GetDefaultLogger().LogError(
+			"invalid auto prune value",
+			"env", constants.EnvAeroSpaceMarksAutoPrune,
+			"value", value,
+		)
+		return false
+	}
+
+	return enabled
+}
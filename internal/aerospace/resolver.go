@@ -0,0 +1,152 @@
+package aerospace
+
+import (
+	"fmt"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// ErrWindowGone is returned when a mark points to a window that no longer
+// exists and no live window matches its stored metadata.
+var ErrWindowGone = errkind.New(errkind.WindowGone, "window no longer exists")
+
+// MarkResolver finds the live window of a mark.
+//
+// Window IDs change whenever AeroSpace or an application restarts. When the
+// stored window ID is gone, the resolver looks for a live window with the same
+// app bundle ID and title and re-binds the mark to it.
+type MarkResolver struct {
+	storage   storage.MarkStorage
+	aerospace AerosSpaceMarkWindows
+}
+
+// NewMarkResolver creates a MarkResolver.
+func NewMarkResolver(
+	storageClient storage.MarkStorage,
+	aerospaceClient AerosSpaceMarkWindows,
+) *MarkResolver {
+	return &MarkResolver{
+		storage:   storageClient,
+		aerospace: aerospaceClient,
+	}
+}
+
+// ResolveMark returns the live window for the given mark.
+//
+// Returns an error wrapping ErrWindowGone if the window can't be found.
+func (r *MarkResolver) ResolveMark(mark string) (*windows.Window, error) {
+	markedWindow, err := r.storage.GetWindowByMark(mark)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := r.aerospace.Client()
+	if err != nil {
+		return nil, err
+	}
+	windowsList, err := client.Windows().GetAllWindows()
+	if err != nil {
+		return nil, err
+	}
+	r.autoPrune(windowsList)
+
+	return r.Resolve(*markedWindow, windowsList)
+}
+
+// autoPrune removes the orphan marks when auto prune is enabled, the
+// resolver has the list of all windows already.
+func (r *MarkResolver) autoPrune(windowsList []windows.Window) {
+	if !IsAutoPruneEnabled() {
+		return
+	}
+
+	marks, err := r.storage.GetMarks()
+	if err != nil {
+		logger.GetDefaultLogger().LogError("failed to auto prune marks", "err", err)
+		return
+	}
+	AutoPrune(r.storage, marks, windowsList)
+}
+
+// Resolve matches a stored mark against a list of live windows.
+//
+// If the mark had to be re-bound to a different window, the storage is updated.
+func (r *MarkResolver) Resolve(
+	mark queries.Mark,
+	windowsList []windows.Window,
+) (*windows.Window, error) {
+	log := logger.GetDefaultLogger()
+
+	for i := range windowsList {
+		if mark.WindowID != 0 && windowsList[i].WindowID == mark.WindowID {
+			return &windowsList[i], nil
+		}
+	}
+
+	window := FindWindowByMetadata(mark, windowsList)
+	if window == nil {
+		return nil, fmt.Errorf("%w: mark '%s' (window ID %d)", ErrWindowGone, mark.Mark, mark.WindowID)
+	}
+
+	log.LogInfo(
+		"Re-binding mark to a new window",
+		"mark", mark.Mark,
+		"oldWindowID", mark.WindowID,
+		"newWindowID", window.WindowID,
+	)
+	if err := RebindMark(r.storage, mark, *window); err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+// RebindMark binds a mark to the window that replaced its window, e.g.
+// after the app restarted, and moves the tags of the old window to it. It
+// isn't a change of the marks to undo, see RefreshMarkWindow.
+func RebindMark(storageClient storage.MarkStorage, mark queries.Mark, window windows.Window) error {
+	return storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		err := tx.RefreshMarkWindow(mark.Mark, window.WindowID, NewWindowMetadata(window))
+		if err != nil || mark.WindowID == 0 {
+			return err
+		}
+
+		return tx.MoveWindowTags(mark.WindowID, window.WindowID)
+	})
+}
+
+// FindWindowByMetadata looks for a live window matching the metadata stored
+// with a mark.
+//
+// Windows must share the app bundle ID and the title, a window of the same
+// app with another title may be another document and isn't picked.
+// Returns nil when no window matches.
+func FindWindowByMetadata(mark queries.Mark, windowsList []windows.Window) *windows.Window {
+	if mark.AppBundleID == "" || mark.WindowTitle == "" {
+		return nil
+	}
+
+	for i := range windowsList {
+		if windowsList[i].AppBundleID == mark.AppBundleID &&
+			windowsList[i].WindowTitle == mark.WindowTitle {
+			return &windowsList[i]
+		}
+	}
+
+	return nil
+}
+
+// NewWindowMetadata extracts the metadata stored alongside a mark from a window.
+func NewWindowMetadata(window windows.Window) storage.WindowMetadata {
+	return storage.WindowMetadata{
+		AppBundleID: window.AppBundleID,
+		AppName:     window.AppName,
+		WindowTitle: window.WindowTitle,
+		Workspace:   window.Workspace,
+	}
+}
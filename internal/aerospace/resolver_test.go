@@ -0,0 +1,128 @@
+package aerospace_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+func TestFindWindowByMetadata(t *testing.T) {
+	windowsList := []windows.Window{
+		{WindowID: 1, AppBundleID: "io.alacritty", WindowTitle: "zsh"},
+		{WindowID: 2, AppBundleID: "io.alacritty", WindowTitle: "nvim"},
+		{WindowID: 3, AppBundleID: "com.apple.mail", WindowTitle: "inbox"},
+	}
+
+	tests := []struct {
+		name     string
+		mark     queries.Mark
+		windowID int
+	}{
+		{
+			name:     "matches the app and the title",
+			mark:     queries.Mark{AppBundleID: "io.alacritty", WindowTitle: "nvim"},
+			windowID: 2,
+		},
+		{
+			name: "doesn't match another title of the app",
+			mark: queries.Mark{AppBundleID: "io.alacritty", WindowTitle: "htop"},
+		},
+		{
+			name: "doesn't match the only window of the app with another title",
+			mark: queries.Mark{AppBundleID: "com.apple.mail", WindowTitle: "drafts"},
+		},
+		{
+			name: "doesn't match the title of another app",
+			mark: queries.Mark{AppBundleID: "com.brave.Browser", WindowTitle: "nvim"},
+		},
+		{
+			name: "doesn't match without an app",
+			mark: queries.Mark{WindowTitle: "nvim"},
+		},
+		{
+			name: "doesn't match without a title",
+			mark: queries.Mark{AppBundleID: "com.apple.mail"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			window := aerospace.FindWindowByMetadata(tc.mark, windowsList)
+			if tc.windowID == 0 {
+				assert.Nil(t, window)
+				return
+			}
+			require.NotNil(t, window)
+			assert.Equal(t, tc.windowID, window.WindowID)
+		})
+	}
+}
+
+func TestMarkResolverResolve(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	windowsList := []windows.Window{
+		{WindowID: 1, AppBundleID: "io.alacritty", WindowTitle: "zsh", Workspace: "1"},
+		{WindowID: 2, AppBundleID: "io.alacritty", WindowTitle: "nvim", Workspace: "2"},
+	}
+
+	t.Run("returns the live window of the mark", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		_, strg := mocks.MockStorageDBClient(ctrl)
+
+		resolver := aerospace.NewMarkResolver(strg, nil)
+		window, err := resolver.Resolve(queries.Mark{Mark: "term", WindowID: 1}, windowsList)
+		require.NoError(t, err)
+		assert.Equal(t, 1, window.WindowID)
+	})
+
+	t.Run("re-binds a stale mark to the window with its app and title", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		mocks.ExpectTransaction(strg).Times(1)
+		strg.EXPECT().
+			RefreshMarkWindow("editor", 2, storage.WindowMetadata{
+				AppBundleID: "io.alacritty",
+				WindowTitle: "nvim",
+				Workspace:   "2",
+			}).
+			Return(nil).
+			Times(1)
+		strg.EXPECT().MoveWindowTags(10, 2).Return(nil).Times(1)
+
+		stale := queries.Mark{
+			Mark:        "editor",
+			WindowID:    10,
+			AppBundleID: "io.alacritty",
+			WindowTitle: "nvim",
+		}
+		window, err := aerospace.NewMarkResolver(strg, nil).Resolve(stale, windowsList)
+		require.NoError(t, err)
+		assert.Equal(t, 2, window.WindowID)
+	})
+
+	t.Run("fails when no window has the app and title of the mark", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		_, strg := mocks.MockStorageDBClient(ctrl)
+
+		stale := queries.Mark{
+			Mark:        "monitor",
+			WindowID:    10,
+			AppBundleID: "io.alacritty",
+			WindowTitle: "htop",
+		}
+		_, err := aerospace.NewMarkResolver(strg, nil).Resolve(stale, windowsList)
+		require.ErrorIs(t, err, aerospace.ErrWindowGone)
+		assert.Equal(t, errkind.WindowGone, errkind.Of(err))
+	})
+}
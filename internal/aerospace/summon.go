@@ -0,0 +1,87 @@
+package aerospace
+
+import (
+	"fmt"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/workspaces"
+)
+
+// SummonWindow moves the window of a mark to the focused workspace, and
+// focuses it when shouldFocus is set.
+//
+// The workspace the window came from is recorded, unless it is already on
+// the focused workspace, so it can be sent back with dismiss. Returns the
+// event of the summon command.
+func SummonWindow(
+	storageClient storage.MarkStorage,
+	aerospaceClient AerosSpaceMarkWindows,
+	mark string,
+	window *windows.Window,
+	shouldFocus bool,
+) (format.OutputEvent, error) {
+	windowID := window.WindowID
+	sourceWorkspace := window.Workspace
+
+	client, err := aerospaceClient.Client()
+	if err != nil {
+		return format.OutputEvent{}, err
+	}
+	workspace, err := client.Workspaces().GetFocusedWorkspace()
+	if err != nil {
+		return format.OutputEvent{}, err
+	}
+
+	err = client.Workspaces().MoveWindowToWorkspaceWithOpts(
+		workspaces.MoveWindowToWorkspaceArgs{
+			WorkspaceName: workspace.Workspace,
+		},
+		workspaces.MoveWindowToWorkspaceOpts{
+			WindowID: &windowID,
+		},
+	)
+	if err != nil {
+		return format.OutputEvent{}, err
+	}
+
+	// Remember where the window came from, unless it was already here. Only
+	// once moved, a failed move must not leave an origin it never left
+	if sourceWorkspace != "" && sourceWorkspace != workspace.Workspace {
+		if err = storageClient.SetMarkOrigin(mark, sourceWorkspace); err != nil {
+			return format.OutputEvent{}, err
+		}
+	}
+
+	action := "summon"
+	message := fmt.Sprintf("Window %d summoned to workspace %s", windowID, workspace.Workspace)
+	if shouldFocus {
+		// Remember the window that had focus so `focus --back` returns to it
+		_, err = NewFocusSwitcher(storageClient, aerospaceClient).Focus(windowID, false)
+		if err != nil {
+			return format.OutputEvent{}, err
+		}
+		action = "summon_and_focus"
+		message += " and focused"
+	}
+
+	logger.GetDefaultLogger().LogDebug(
+		"Window summoned",
+		"windowID", windowID,
+		"workspace", workspace.Workspace,
+	)
+
+	return format.OutputEvent{
+		Command:         "summon",
+		Action:          action,
+		WindowID:        windowID,
+		AppName:         window.AppName,
+		Workspace:       sourceWorkspace,
+		TargetWorkspace: workspace.Workspace,
+		Result:          "success",
+		Message:         message,
+	}, nil
+}
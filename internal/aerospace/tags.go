@@ -0,0 +1,151 @@
+package aerospace
+
+import (
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// TagsByWindow returns the tags of the live windows in windowsList, keyed
+// by window ID, see IsTagOf.
+//
+// Tags of a window keep the storage order (by tag name).
+func TagsByWindow(
+	storageClient storage.MarkStorage,
+	windowsList []windows.Window,
+) (map[int][]string, error) {
+	tags, err := storageClient.GetTags()
+	if err != nil {
+		return nil, err
+	}
+
+	windowsByID := make(map[int]windows.Window, len(windowsList))
+	for _, window := range windowsList {
+		windowsByID[window.WindowID] = window
+	}
+
+	tagsByWindow := make(map[int][]string)
+	for _, tag := range tags {
+		window, ok := windowsByID[tag.WindowID]
+		if !ok || !IsTagOf(tag, window) {
+			continue
+		}
+		tagsByWindow[tag.WindowID] = append(tagsByWindow[tag.WindowID], tag.Tag)
+	}
+
+	return tagsByWindow, nil
+}
+
+// IsTagOf reports whether tag is set on window: the window has the tagged
+// ID and, when it was recorded, the tagged app. A window reusing the ID of
+// a closed tagged window doesn't join its groups.
+func IsTagOf(tag queries.WindowTag, window windows.Window) bool {
+	if tag.WindowID != window.WindowID {
+		return false
+	}
+
+	return tag.AppBundleID == "" || tag.AppBundleID == window.AppBundleID
+}
+
+// FindTaggedWindows returns the live windows with the given tag, in the
+// order they were tagged. Tagged windows that no longer exist are skipped.
+func FindTaggedWindows(
+	storageClient storage.MarkStorage,
+	aerospaceClient AerosSpaceMarkWindows,
+	tag string,
+) ([]windows.Window, error) {
+	windowTags, err := storageClient.GetWindowTagsByTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(windowTags) == 0 {
+		return []windows.Window{}, nil
+	}
+
+	client, err := aerospaceClient.Client()
+	if err != nil {
+		return nil, err
+	}
+	windowsList, err := client.Windows().GetAllWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	windowsByID := make(map[int]windows.Window, len(windowsList))
+	for _, window := range windowsList {
+		windowsByID[window.WindowID] = window
+	}
+
+	tagged := make([]windows.Window, 0, len(windowTags))
+	for _, windowTag := range windowTags {
+		if window, ok := windowsByID[windowTag.WindowID]; ok && IsTagOf(windowTag, window) {
+			tagged = append(tagged, window)
+		}
+	}
+
+	return tagged, nil
+}
+
+// PruneOrphanTags deletes the tags of the windows that no longer exist, or
+// whose ID now belongs to another app, see IsTagOf.
+//
+// Tags of a gone window that still has marks are kept: the marks may be
+// re-bound to a new window, which then gets the tags, see RebindMark.
+// Returns the number of deleted tags.
+func PruneOrphanTags(
+	storageClient storage.MarkStorage,
+	windowsList []windows.Window,
+) (int64, error) {
+	tags, err := storageClient.GetTags()
+	if err != nil || len(tags) == 0 {
+		return 0, err
+	}
+
+	marks, err := storageClient.GetMarks()
+	if err != nil {
+		return 0, err
+	}
+	markedWindows := make(map[int]bool, len(marks))
+	for _, mark := range marks {
+		markedWindows[mark.WindowID] = true
+	}
+
+	windowsByID := make(map[int]windows.Window, len(windowsList))
+	for _, window := range windowsList {
+		windowsByID[window.WindowID] = window
+	}
+
+	orphans := make([]queries.WindowTag, 0)
+	for _, tag := range tags {
+		window, isLive := windowsByID[tag.WindowID]
+		if isLive && IsTagOf(tag, window) {
+			continue
+		}
+		if !isLive && markedWindows[tag.WindowID] {
+			continue
+		}
+		orphans = append(orphans, tag)
+	}
+	if len(orphans) == 0 {
+		return 0, nil
+	}
+
+	var deleted int64
+	err = storageClient.WithTransaction(func(tx storage.MarkStorage) error {
+		for _, orphan := range orphans {
+			rowsAffected, deleteErr := tx.DeleteWindowTag(orphan.WindowID, orphan.Tag)
+			if deleteErr != nil {
+				return deleteErr
+			}
+			deleted += rowsAffected
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
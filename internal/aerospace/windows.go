@@ -1,8 +1,7 @@
 package aerospace
 
 import (
-	"fmt"
-
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 
 	aerospacecli "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
@@ -19,8 +18,13 @@ type AerosSpaceMarkWindows interface {
 	// Client returns the AeroSpaceWM client
 	//
 	// Returns the AeroSpaceWM client
-	// or panics if the client is not initialized
-	Client() *aerospacecli.AeroSpaceWM
+	// or an IPCUnavailable error if AeroSpace can't be reached
+	Client() (*aerospacecli.AeroSpaceWM, error)
+
+	// Connect connects to AeroSpace unless already connected
+	//
+	// Returns an IPCUnavailable error if AeroSpace can't be reached
+	Connect() error
 }
 
 type DefaultAeroSpaceWindows struct {
@@ -38,14 +42,19 @@ func NewAeroSpaceClient() (*DefaultAeroSpaceWindows, error) {
 	}, nil
 }
 
-func (d *DefaultAeroSpaceWindows) Client() *aerospacecli.AeroSpaceWM {
+func (d *DefaultAeroSpaceWindows) Client() (*aerospacecli.AeroSpaceWM, error) {
 	if d.client == nil {
 		logger := logger.GetDefaultLogger()
 		logger.LogError("ASSERT: AeroSpaceWM client is not initialized", nil)
 		panic("AeroSpaceWM client is not initialized")
 	}
 
-	return d.client
+	return d.client, nil
+}
+
+// Connect does nothing, the client is connected when created.
+func (d *DefaultAeroSpaceWindows) Connect() error {
+	return nil
 }
 
 func (d *DefaultAeroSpaceWindows) GetWindowByID(windowID int) (*windows.Window, error) {
@@ -62,5 +71,5 @@ func (d *DefaultAeroSpaceWindows) GetWindowByID(windowID int) (*windows.Window,
 		}
 	}
 
-	return nil, fmt.Errorf("window with ID %d not found", windowID)
+	return nil, errkind.Errorf(errkind.WindowGone, "window with ID %d not found", windowID)
 }
@@ -17,4 +17,19 @@ const (
 
 	// EnvAeroSpaceSock is the environment variable for the AeroSpace IPC socket path.
 	EnvAeroSpaceSock string = "AEROSPACESOCK"
+
+	// EnvAeroSpaceMarksAutoPrune enables removing marks of closed windows
+	// in commands that already fetch all windows (e.g. list)
+	// default: `false`
+	EnvAeroSpaceMarksAutoPrune string = "AEROSPACE_MARKS_AUTO_PRUNE"
+
+	// EnvAeroSpaceMarksSocket is the environment variable for the daemon socket path.
+	// When set, commands delegate to the running daemon (e.g. focus)
+	// default: `/tmp/aerospace-marks.sock` (daemon only)
+	EnvAeroSpaceMarksSocket string = "AEROSPACE_MARKS_SOCKET"
+
+	// EnvAeroSpaceMarksScratchpadWorkspace is the environment variable for the workspace
+	// where scratchpad windows are hidden when their origin workspace is unknown
+	// default: `scratchpad`
+	EnvAeroSpaceMarksScratchpadWorkspace string = "AEROSPACE_MARKS_SCRATCHPAD_WORKSPACE"
 )
@@ -0,0 +1,232 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// Mark operations exposed by the daemon.
+//
+// Results reuse format.OutputEvent and format.MarkedWindow, the same
+// structures printed by the commands with `--output json`.
+const (
+	MethodAdd     = "add"
+	MethodToggle  = "toggle"
+	MethodReplace = "replace"
+	MethodDelete  = "delete"
+	MethodList    = "list"
+	MethodGet     = "get"
+	MethodSummon  = "summon"
+)
+
+// MarkParams are the params of add, toggle, replace, delete and get.
+type MarkParams struct {
+	Mark string `json:"mark"`
+	// WindowID is the window to mark (default: focused window)
+	WindowID int `json:"window_id,omitempty"`
+}
+
+// SummonParams are the params of the summon method.
+type SummonParams struct {
+	Mark  string `json:"mark"`
+	Focus bool   `json:"focus,omitempty"`
+}
+
+// registerAPI adds the mark operations to the server.
+func (d *Daemon) registerAPI(server *Server) {
+	server.Register(MethodAdd, d.markHandler(d.add))
+	server.Register(MethodToggle, d.markHandler(d.toggle))
+	server.Register(MethodReplace, d.markHandler(d.replace))
+	server.Register(MethodDelete, d.markHandler(d.delete))
+	server.Register(MethodGet, d.markHandler(d.get))
+
+	server.Register(MethodList, func(_ json.RawMessage) (any, error) {
+		return d.List()
+	})
+
+	server.Register(MethodSummon, func(params json.RawMessage) (any, error) {
+		var summonParams SummonParams
+		if err := DecodeParams(params, &summonParams); err != nil {
+			return nil, err
+		}
+		if summonParams.Mark == "" {
+			return nil, NewRPCError(CodeInvalidParams, "mark is required")
+		}
+		return d.Summon(summonParams.Mark, summonParams.Focus)
+	})
+}
+
+// markHandler decodes MarkParams and calls fn holding the daemon lock.
+func (d *Daemon) markHandler(fn func(params MarkParams) (any, error)) HandlerFunc {
+	return func(params json.RawMessage) (any, error) {
+		var markParams MarkParams
+		if err := DecodeParams(params, &markParams); err != nil {
+			return nil, err
+		}
+		if markParams.Mark == "" {
+			return nil, NewRPCError(CodeInvalidParams, "mark is required")
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return fn(markParams)
+	}
+}
+
+func (d *Daemon) add(params MarkParams) (any, error) {
+	window, err := d.targetWindow(params.WindowID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.storage.AddMark(window.WindowID, params.Mark, aerospace.NewWindowMetadata(*window))
+	if err != nil {
+		return nil, err
+	}
+
+	return markEvent(MethodAdd, window, fmt.Sprintf("Added mark: %s", params.Mark)), nil
+}
+
+func (d *Daemon) toggle(params MarkParams) (any, error) {
+	window, err := d.targetWindow(params.WindowID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.storage.ToggleMark(window.WindowID, params.Mark, aerospace.NewWindowMetadata(*window))
+	if err != nil {
+		return nil, err
+	}
+
+	return markEvent(MethodToggle, window, fmt.Sprintf("Toggling mark: %s", params.Mark)), nil
+}
+
+func (d *Daemon) replace(params MarkParams) (any, error) {
+	window, err := d.targetWindow(params.WindowID)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := d.storage.ReplaceAllMarks(
+		window.WindowID,
+		params.Mark,
+		aerospace.NewWindowMetadata(*window),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Marked window with '%s'", params.Mark)
+	if deleted > 0 {
+		message = fmt.Sprintf("Replaced all marks with '%s'", params.Mark)
+	}
+
+	return markEvent(MethodReplace, window, message), nil
+}
+
+func (d *Daemon) delete(params MarkParams) (any, error) {
+	deleted, err := d.storage.DeleteByMark(params.Mark)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, errkind.Errorf(errkind.MarkNotFound, "mark '%s' not found", params.Mark)
+	}
+
+	return format.OutputEvent{
+		Command: MethodDelete,
+		Action:  MethodDelete,
+		Result:  "success",
+		Message: fmt.Sprintf("Removed %d marks", deleted),
+	}, nil
+}
+
+func (d *Daemon) get(params MarkParams) (any, error) {
+	markedWindow, err := d.storage.GetWindowByMark(params.Mark)
+	if err != nil {
+		return nil, err
+	}
+
+	windowsList, err := d.refreshWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := aerospace.NewMarkResolver(d.storage, d.aerospace).Resolve(*markedWindow, windowsList)
+	if err != nil {
+		return nil, err
+	}
+
+	return aerospace.NewMarkedWindow(params.Mark, window), nil
+}
+
+// List returns the marked windows that are still alive, one per mark.
+func (d *Daemon) List() ([]format.MarkedWindow, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	marks, err := d.storage.GetMarks()
+	if err != nil {
+		return nil, err
+	}
+
+	windowsList, err := d.refreshWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	return aerospace.MarkedWindows(d.storage, marks, windowsList)
+}
+
+// Summon moves the window of a mark to the focused workspace.
+func (d *Daemon) Summon(mark string, shouldFocus bool) (format.OutputEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	markedWindow, err := d.storage.GetWindowByMark(mark)
+	if err != nil {
+		return format.OutputEvent{}, err
+	}
+
+	windowsList, err := d.refreshWindows()
+	if err != nil {
+		return format.OutputEvent{}, err
+	}
+
+	window, err := aerospace.NewMarkResolver(d.storage, d.aerospace).Resolve(*markedWindow, windowsList)
+	if err != nil {
+		return format.OutputEvent{}, err
+	}
+
+	return aerospace.SummonWindow(d.storage, d.aerospace, mark, window, shouldFocus)
+}
+
+// targetWindow returns the window with the given ID or the focused window.
+func (d *Daemon) targetWindow(windowID int) (*windows.Window, error) {
+	if windowID == 0 {
+		client, err := d.aerospace.Client()
+		if err != nil {
+			return nil, err
+		}
+		return client.Windows().GetFocusedWindow()
+	}
+	return d.aerospace.GetWindowByID(windowID)
+}
+
+func markEvent(method string, window *windows.Window, message string) format.OutputEvent {
+	return format.OutputEvent{
+		Command:   method,
+		Action:    method,
+		WindowID:  window.WindowID,
+		AppName:   window.AppName,
+		Workspace: window.Workspace,
+		Result:    "success",
+		Message:   message,
+	}
+}
@@ -0,0 +1,278 @@
+package daemon_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+func newTestServer(marksDaemon *daemon.Daemon) *daemon.Server {
+	server := daemon.NewServer()
+	marksDaemon.Register(server)
+	return server
+}
+
+// call sends a request to the server and decodes its result.
+func call(t *testing.T, server *daemon.Server, request string, result any) *daemon.RPCError {
+	t.Helper()
+
+	response := server.Handle([]byte(request))
+	assert.Equal(t, daemon.JSONRPCVersion, response.JSONRPC)
+	assert.JSONEq(t, "1", string(response.ID))
+	if response.Error != nil {
+		return response.Error
+	}
+	require.NoError(t, json.Unmarshal(response.Result, result))
+	return nil
+}
+
+func TestDaemonAPI(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	windows := []aerospace.Window{
+		{WindowID: 1, WindowTitle: "title1", AppName: "app1", Workspace: "1"},
+		{WindowID: 2, WindowTitle: "title2", AppName: "app2", AppBundleID: "com.app2", Workspace: "2"},
+	}
+
+	t.Run("adds a mark to the focused window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			AddMark(1, "a", storage.WindowMetadata{AppName: "app1", WindowTitle: "title1", Workspace: "1"}).
+			Return(nil).
+			Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(conn, windows[0]).Times(1)
+
+		server := newTestServer(daemon.New(strg, aerospaceClient))
+
+		var event format.OutputEvent
+		rpcErr := call(t, server, `{"jsonrpc":"2.0","id":1,"method":"add","params":{"mark":"a"}}`, &event)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, format.OutputEvent{
+			Command:   "add",
+			Action:    "add",
+			WindowID:  1,
+			AppName:   "app1",
+			Workspace: "1",
+			Result:    "success",
+			Message:   "Added mark: a",
+		}, event)
+	})
+
+	t.Run("toggles a mark of a given window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			ToggleMark(2, "b", storage.WindowMetadata{
+				AppBundleID: "com.app2",
+				AppName:     "app2",
+				WindowTitle: "title2",
+				Workspace:   "2",
+			}).
+			Return(nil).
+			Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		server := newTestServer(daemon.New(strg, aerospaceClient))
+
+		var event format.OutputEvent
+		rpcErr := call(
+			t,
+			server,
+			`{"jsonrpc":"2.0","id":1,"method":"toggle","params":{"mark":"b","window_id":2}}`,
+			&event,
+		)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "toggle", event.Action)
+		assert.Equal(t, 2, event.WindowID)
+		assert.Equal(t, "Toggling mark: b", event.Message)
+	})
+
+	t.Run("replaces all marks of the focused window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			ReplaceAllMarks(1, "c", gomock.Any()).
+			Return(int64(2), nil).
+			Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(conn, windows[0]).Times(1)
+
+		server := newTestServer(daemon.New(strg, aerospaceClient))
+
+		var event format.OutputEvent
+		rpcErr := call(t, server, `{"jsonrpc":"2.0","id":1,"method":"replace","params":{"mark":"c"}}`, &event)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "Replaced all marks with 'c'", event.Message)
+	})
+
+	t.Run("deletes a mark", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().DeleteByMark("a").Return(int64(1), nil).Times(1)
+		strg.EXPECT().DeleteByMark("missing").Return(int64(0), nil).Times(1)
+
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		server := newTestServer(daemon.New(strg, aerospaceClient))
+
+		var event format.OutputEvent
+		rpcErr := call(t, server, `{"jsonrpc":"2.0","id":1,"method":"delete","params":{"mark":"a"}}`, &event)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "Removed 1 marks", event.Message)
+
+		rpcErr = call(t, server, `{"jsonrpc":"2.0","id":1,"method":"delete","params":{"mark":"missing"}}`, &event)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, daemon.CodeInternalError, rpcErr.Code)
+		assert.Equal(t, "mark 'missing' not found", rpcErr.Message)
+		assert.Equal(t, errkind.MarkNotFound, errkind.Of(rpcErr))
+	})
+
+	t.Run("lists the marked windows", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarks().Return([]queries.Mark{
+			{WindowID: 1, Mark: "a"},
+			{WindowID: 1, Mark: "b"},
+			{WindowID: 2, Mark: "c"},
+			{WindowID: 3, Mark: "gone"},
+		}, nil).Times(1)
+		strg.EXPECT().GetTags().Return([]queries.WindowTag{
+			{WindowID: 2, Tag: "meeting"},
+		}, nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		server := newTestServer(daemon.New(strg, aerospaceClient))
+
+		var markedWindows []format.MarkedWindow
+		rpcErr := call(t, server, `{"jsonrpc":"2.0","id":1,"method":"list"}`, &markedWindows)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, []format.MarkedWindow{
+			{Mark: "a", WindowID: 1, AppName: "app1", WindowTitle: "title1", Workspace: "1"},
+			{Mark: "b", WindowID: 1, AppName: "app1", WindowTitle: "title1", Workspace: "1"},
+			{
+				Mark:        "c",
+				WindowID:    2,
+				AppName:     "app2",
+				WindowTitle: "title2",
+				Workspace:   "2",
+				AppBundleID: "com.app2",
+				Tags:        []string{"meeting"},
+			},
+		}, markedWindows)
+	})
+
+	t.Run("gets the window of a mark", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("c").
+			Return(&queries.Mark{WindowID: 2, Mark: "c"}, nil).
+			Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		server := newTestServer(daemon.New(strg, aerospaceClient))
+
+		var markedWindow format.MarkedWindow
+		rpcErr := call(t, server, `{"jsonrpc":"2.0","id":1,"method":"get","params":{"mark":"c"}}`, &markedWindow)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, 2, markedWindow.WindowID)
+		assert.Equal(t, "com.app2", markedWindow.AppBundleID)
+	})
+
+	t.Run("summons and focuses the window of a mark", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("c").
+			Return(&queries.Mark{WindowID: 2, Mark: "c"}, nil).
+			Times(1)
+		strg.EXPECT().SetMarkOrigin("c", "2").Return(nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+		mocks.ExpectGetFocusedWorkspace(conn, "1").Times(1)
+		mocks.ExpectCommand(conn, "move-node-to-workspace", gomock.Any()).Times(1)
+		mocks.ExpectGetFocusedWindow(conn, windows[0]).Times(1)
+		strg.EXPECT().PushFocusHistory(1).Return(nil).Times(1)
+		mocks.ExpectCommand(conn, "focus", []string{"--window-id", "2"}).Times(1)
+
+		server := newTestServer(daemon.New(strg, aerospaceClient))
+
+		var event format.OutputEvent
+		rpcErr := call(
+			t,
+			server,
+			`{"jsonrpc":"2.0","id":1,"method":"summon","params":{"mark":"c","focus":true}}`,
+			&event,
+		)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, format.OutputEvent{
+			Command:         "summon",
+			Action:          "summon_and_focus",
+			WindowID:        2,
+			AppName:         "app2",
+			Workspace:       "2",
+			TargetWorkspace: "1",
+			Result:          "success",
+			Message:         "Window 2 summoned to workspace 1 and focused",
+		}, event)
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		server := newTestServer(daemon.New(strg, aerospaceClient))
+
+		rpcErr := call(t, server, `{"jsonrpc":"2.0","id":1,"method":"add","params":{"mark":""}}`, nil)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, daemon.CodeInvalidParams, rpcErr.Code)
+
+		rpcErr = call(t, server, `{"jsonrpc":"2.0","id":1,"method":"get","params":["a"]}`, nil)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, daemon.CodeInvalidParams, rpcErr.Code)
+
+		response := server.Handle([]byte(`{not json`))
+		require.NotNil(t, response.Error)
+		assert.Equal(t, daemon.CodeParseError, response.Error.Code)
+	})
+}
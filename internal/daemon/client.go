@@ -0,0 +1,158 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/constants"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+)
+
+const (
+	// socketName is the name of the socket in DefaultSocketPath.
+	socketName = "aerospace-marks.sock"
+	// defaultCallTimeout bounds a single call, a hotkey should never hang.
+	// It is longer than the busy timeout of the database, so a daemon
+	// waiting for the database answers before the call gives up.
+	defaultCallTimeout = 2 * storage.BusyTimeout
+)
+
+// ErrNotRunning is returned by Call when the daemon can't be connected to,
+// the request wasn't sent.
+var ErrNotRunning = errors.New("daemon is not running")
+
+// Client calls methods of a running daemon.
+//
+// Each call opens a new connection, so a Client is safe for concurrent use.
+type Client struct {
+	socketPath string
+	timeout    time.Duration
+	requestID  atomic.Int64
+}
+
+// NewClient creates a Client for the daemon listening on socketPath.
+func NewClient(socketPath string) *Client {
+	return &Client{
+		socketPath: socketPath,
+		timeout:    defaultCallTimeout,
+	}
+}
+
+// DefaultSocketPath returns the socket used by the daemon when
+// AEROSPACE_MARKS_SOCKET is not set. It is in a directory of the current
+// user, $XDG_RUNTIME_DIR or else $TMPDIR (per user on macOS), so other users
+// can't take its place before the daemon starts.
+func DefaultSocketPath() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, socketName)
+}
+
+// SocketPath returns the socket path configured with AEROSPACE_MARKS_SOCKET
+// or DefaultSocketPath.
+func SocketPath() string {
+	socketPath := strings.TrimSpace(os.Getenv(constants.EnvAeroSpaceMarksSocket))
+	if socketPath == "" {
+		return DefaultSocketPath()
+	}
+	return socketPath
+}
+
+// Call invokes a method and decodes its result into result (which may be nil).
+//
+// Errors answered by the daemon are returned as *RPCError. An error wrapping
+// ErrNotRunning means the daemon couldn't be reached and the method wasn't
+// called. Any other error happened once connected: the daemon may have run
+// the method.
+func (c *Client) Call(method string, params any, result any) error {
+	conn, err := net.DialTimeout("unix", c.socketPath, c.timeout)
+	if err != nil {
+		return errkind.Errorf(
+			errkind.IPCUnavailable,
+			"failed to connect to daemon: %w: %w",
+			ErrNotRunning,
+			err,
+		)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return errkind.Wrap(errkind.IPCUnavailable, err)
+	}
+
+	request := Request{
+		JSONRPC: JSONRPCVersion,
+		ID:      json.RawMessage(fmt.Sprintf("%d", c.requestID.Add(1))),
+		Method:  method,
+	}
+	if params != nil {
+		data, marshalErr := json.Marshal(params)
+		if marshalErr != nil {
+			return errkind.Errorf(errkind.InvalidInput, "failed to encode params: %w", marshalErr)
+		}
+		request.Params = data
+	}
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return errkind.Errorf(errkind.IPCUnavailable, "failed to send request: %w", err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return errkind.Errorf(errkind.IPCUnavailable, "failed to read response: %w", err)
+	}
+
+	var response Response
+	if err := json.Unmarshal(line, &response); err != nil {
+		return errkind.Errorf(errkind.IPCUnavailable, "invalid response: %w", err)
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if result == nil || len(response.Result) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return errkind.Errorf(errkind.IPCUnavailable, "invalid result: %w", err)
+	}
+
+	return nil
+}
+
+// Ping checks the daemon is reachable.
+func (c *Client) Ping() error {
+	return c.Call(MethodPing, nil, nil)
+}
+
+// IsRPCError reports whether err was answered by the daemon, as opposed to
+// an error reaching it.
+func IsRPCError(err error) bool {
+	var rpcErr *RPCError
+	return errors.As(err, &rpcErr)
+}
+
+//nolint:gochecknoglobals // default client shared by the commands
+var defaultClient *Client
+
+// SetDefaultClient sets the client used by commands to delegate to a
+// running daemon. Passing nil disables the delegation.
+func SetDefaultClient(client *Client) {
+	defaultClient = client
+}
+
+// GetDefaultClient returns the client set with SetDefaultClient or nil.
+func GetDefaultClient() *Client {
+	return defaultClient
+}
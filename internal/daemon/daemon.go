@@ -0,0 +1,276 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// Methods exposed by the daemon.
+const (
+	MethodPing  = "ping"
+	MethodSync  = "sync"
+	MethodFocus = "focus"
+)
+
+// SyncResult reports the changes made by a sync.
+type SyncResult struct {
+	// Refreshed is the number of marks whose window metadata changed
+	Refreshed int `json:"refreshed"`
+	// Rebound is the number of marks moved to a new window ID
+	Rebound int `json:"rebound"`
+	// Pruned is the number of marks deleted because their window is gone
+	Pruned int64 `json:"pruned"`
+	// PrunedTags is the number of tags deleted because their window is gone
+	PrunedTags int64 `json:"pruned_tags"`
+}
+
+// FocusParams are the params of the focus method.
+type FocusParams struct {
+	Mark string `json:"mark,omitempty"`
+	// Back focuses the window that had focus before the last switch, Mark is ignored
+	Back bool `json:"back,omitempty"`
+	// NoToggle keeps focus on the marked window if it already has focus
+	NoToggle bool `json:"no_toggle,omitempty"`
+}
+
+// Daemon holds a single storage and AeroSpace connection and keeps the
+// marks consistent with the windows managed by AeroSpace.
+//
+// All operations are serialized, the AeroSpace connection isn't safe to
+// share between goroutines.
+type Daemon struct {
+	mu        sync.Mutex
+	storage   storage.MarkStorage
+	aerospace aerospace.AerosSpaceMarkWindows
+
+	// windows is the list of windows seen on the last sync
+	windows []windows.Window
+}
+
+// New creates a Daemon.
+func New(
+	storageClient storage.MarkStorage,
+	aerospaceClient aerospace.AerosSpaceMarkWindows,
+) *Daemon {
+	return &Daemon{
+		storage:   storageClient,
+		aerospace: aerospaceClient,
+	}
+}
+
+// Run syncs the marks every interval until the context is cancelled.
+//
+// Sync errors are logged, AeroSpace may be restarting.
+func (d *Daemon) Run(ctx context.Context, interval time.Duration) {
+	log := logger.GetDefaultLogger()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Sync(); err != nil {
+			log.LogError("failed to sync marks", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync refreshes the stored window metadata, re-binds marks of windows
+// that got a new ID and deletes marks and tags of windows that no longer
+// exist.
+func (d *Daemon) Sync() (SyncResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result SyncResult
+
+	marks, err := d.storage.GetMarks()
+	if err != nil {
+		return result, err
+	}
+
+	windowsList, err := d.refreshWindows()
+	if err != nil {
+		return result, err
+	}
+
+	liveWindows := make(map[int]windows.Window, len(windowsList))
+	for _, window := range windowsList {
+		liveWindows[window.WindowID] = window
+	}
+
+	orphans := make([]queries.Mark, 0)
+	for _, mark := range marks {
+		window, isLive := liveWindows[mark.WindowID]
+		if !isLive {
+			rebound := aerospace.FindWindowByMetadata(mark, windowsList)
+			if rebound == nil {
+				orphans = append(orphans, mark)
+				continue
+			}
+			window = *rebound
+		}
+
+		if !isLive {
+			if err = aerospace.RebindMark(d.storage, mark, window); err != nil {
+				return result, err
+			}
+			result.Rebound++
+			continue
+		}
+
+		metadata := aerospace.NewWindowMetadata(window)
+		if !hasMetadataChanged(mark, metadata) {
+			continue
+		}
+		if err = d.storage.RefreshMarkWindow(mark.Mark, window.WindowID, metadata); err != nil {
+			return result, err
+		}
+		result.Refreshed++
+	}
+
+	if len(orphans) > 0 {
+		result.Pruned, err = aerospace.PruneOrphanMarks(d.storage, marks, orphans)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.PrunedTags, err = aerospace.PruneOrphanTags(d.storage, windowsList)
+	if err != nil {
+		return result, err
+	}
+
+	if result != (SyncResult{}) {
+		logger.GetDefaultLogger().LogInfo(
+			"Marks synced",
+			"refreshed", result.Refreshed,
+			"rebound", result.Rebound,
+			"pruned", result.Pruned,
+			"prunedTags", result.PrunedTags,
+		)
+	}
+
+	return result, nil
+}
+
+// Focus moves the focus to the window of a mark.
+//
+// The windows seen on the last sync are used, so focusing doesn't wait
+// for AeroSpace to list all windows unless the mark is stale.
+// See FocusParams for going back to the previous window.
+func (d *Daemon) Focus(params FocusParams) (format.OutputEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switcher := aerospace.NewFocusSwitcher(d.storage, d.aerospace)
+
+	var windowID, focusedID int
+	if params.Back {
+		var err error
+		focusedID, err = switcher.Back()
+		if err != nil {
+			return format.OutputEvent{}, err
+		}
+	} else {
+		markedWindow, err := d.storage.GetWindowByMark(params.Mark)
+		if err != nil {
+			return format.OutputEvent{}, err
+		}
+
+		resolver := aerospace.NewMarkResolver(d.storage, d.aerospace)
+		window, err := resolver.Resolve(*markedWindow, d.windows)
+		if errors.Is(err, aerospace.ErrWindowGone) {
+			// The cached windows may be outdated, try again with fresh ones
+			windowsList, refreshErr := d.refreshWindows()
+			if refreshErr != nil {
+				return format.OutputEvent{}, refreshErr
+			}
+			window, err = resolver.Resolve(*markedWindow, windowsList)
+		}
+		if err != nil {
+			return format.OutputEvent{}, err
+		}
+		windowID = window.WindowID
+
+		focusedID, err = switcher.Focus(windowID, !params.NoToggle)
+		if err != nil {
+			return format.OutputEvent{}, err
+		}
+	}
+
+	event := format.OutputEvent{
+		Command:  "focus",
+		Action:   "focus",
+		WindowID: focusedID,
+		Result:   "success",
+		Message:  fmt.Sprintf("Focus moved to window ID %d", focusedID),
+	}
+	if focusedID != windowID {
+		event.Action = "focus_back"
+		event.Message = fmt.Sprintf("Focus moved back to window ID %d", focusedID)
+	}
+
+	return event, nil
+}
+
+// Register adds the daemon methods and the mark operations to the server.
+func (d *Daemon) Register(server *Server) {
+	server.Register(MethodPing, func(_ json.RawMessage) (any, error) {
+		return "pong", nil
+	})
+
+	server.Register(MethodSync, func(_ json.RawMessage) (any, error) {
+		return d.Sync()
+	})
+
+	server.Register(MethodFocus, func(params json.RawMessage) (any, error) {
+		var focusParams FocusParams
+		if err := DecodeParams(params, &focusParams); err != nil {
+			return nil, err
+		}
+		if focusParams.Mark == "" && !focusParams.Back {
+			return nil, NewRPCError(CodeInvalidParams, "mark is required")
+		}
+		return d.Focus(focusParams)
+	})
+
+	d.registerAPI(server)
+}
+
+func (d *Daemon) refreshWindows() ([]windows.Window, error) {
+	client, err := d.aerospace.Client()
+	if err != nil {
+		return nil, err
+	}
+	windowsList, err := client.Windows().GetAllWindows()
+	if err != nil {
+		return nil, err
+	}
+	d.windows = windowsList
+	return windowsList, nil
+}
+
+func hasMetadataChanged(mark queries.Mark, metadata storage.WindowMetadata) bool {
+	return mark.AppBundleID != metadata.AppBundleID ||
+		mark.AppName != metadata.AppName ||
+		mark.WindowTitle != metadata.WindowTitle ||
+		mark.Workspace != metadata.Workspace
+}
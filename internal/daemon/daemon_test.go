@@ -0,0 +1,342 @@
+package daemon_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/constants"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	aerospace "github.com/cristianoliveira/aerospace-ipc/pkg/aerospace"
+)
+
+// startServer serves the daemon methods on a temporary socket.
+//
+// Socket paths are limited to ~100 chars, so t.TempDir() can't be used.
+func startServer(t *testing.T, marksDaemon *daemon.Daemon) *daemon.Client {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "marks")
+	require.NoError(t, err)
+	socketPath := filepath.Join(dir, "d.sock")
+
+	listener, err := daemon.Listen(socketPath)
+	require.NoError(t, err)
+
+	server := daemon.NewServer()
+	marksDaemon.Register(server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = server.Serve(ctx, listener)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		_ = os.RemoveAll(dir)
+	})
+
+	return daemon.NewClient(socketPath)
+}
+
+func TestDaemonSync(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("refreshes, re-binds and prunes marks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		marks := []queries.Mark{
+			{WindowID: 1, Mark: "same", AppName: "app1", WindowTitle: "title1"},
+			{WindowID: 2, Mark: "renamed", AppName: "app2", WindowTitle: "old title"},
+			{
+				WindowID:    3,
+				Mark:        "moved",
+				AppName:     "Alacritty",
+				AppBundleID: "io.alacritty",
+				WindowTitle: "nvim",
+			},
+			{WindowID: 4, Mark: "gone", AppName: "Zoom", AppBundleID: "us.zoom.xos"},
+		}
+		windows := []aerospace.Window{
+			{WindowID: 1, AppName: "app1", WindowTitle: "title1"},
+			{WindowID: 2, AppName: "app2", WindowTitle: "new title"},
+			{WindowID: 30, AppName: "Alacritty", AppBundleID: "io.alacritty", WindowTitle: "nvim"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		gomock.InOrder(
+			strg.EXPECT().GetMarks().Return(marks, nil).Times(1),
+			strg.EXPECT().GetMarks().Return(marks[:3], nil).Times(1),
+		)
+		strg.EXPECT().
+			RefreshMarkWindow("renamed", 2, storage.WindowMetadata{AppName: "app2", WindowTitle: "new title"}).
+			Return(nil).
+			Times(1)
+		strg.EXPECT().
+			RefreshMarkWindow("moved", 30, storage.WindowMetadata{
+				AppName:     "Alacritty",
+				AppBundleID: "io.alacritty",
+				WindowTitle: "nvim",
+			}).
+			Return(nil).
+			Times(1)
+		strg.EXPECT().MoveWindowTags(3, 30).Return(nil).Times(1)
+		mocks.ExpectTransaction(strg).Times(3)
+		strg.EXPECT().DeleteByWindow(4).Return(int64(1), nil).Times(1)
+		strg.EXPECT().GetTags().Return([]queries.WindowTag{
+			{WindowID: 30, Tag: "editor", AppBundleID: "io.alacritty"},
+			{WindowID: 4, Tag: "meeting", AppBundleID: "us.zoom.xos"},
+		}, nil).Times(1)
+		strg.EXPECT().DeleteWindowTag(4, "meeting").Return(int64(1), nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		result, err := daemon.New(strg, aerospaceClient).Sync()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			daemon.SyncResult{Refreshed: 1, Rebound: 1, Pruned: 1, PrunedTags: 1},
+			result,
+		)
+	})
+
+	t.Run("does nothing when marks are up to date", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		marks := []queries.Mark{
+			{WindowID: 1, Mark: "same", AppName: "app1", WindowTitle: "title1"},
+		}
+		windows := []aerospace.Window{
+			{WindowID: 1, AppName: "app1", WindowTitle: "title1"},
+		}
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetMarks().Return(marks, nil).Times(1)
+		strg.EXPECT().GetTags().Return(nil, nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, windows).Times(1)
+
+		result, err := daemon.New(strg, aerospaceClient).Sync()
+		require.NoError(t, err)
+		assert.Equal(t, daemon.SyncResult{}, result)
+	})
+}
+
+func TestDaemonServer(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("answers ping", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		client := startServer(t, daemon.New(strg, aerospaceClient))
+
+		var result string
+		require.NoError(t, client.Call(daemon.MethodPing, nil, &result))
+		assert.Equal(t, "pong", result)
+	})
+
+	t.Run("focuses a marked window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("mark1").
+			Return(&queries.Mark{WindowID: 1, Mark: "mark1"}, nil).
+			Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(conn, []aerospace.Window{
+			{WindowID: 1, AppName: "app1", WindowTitle: "title1"},
+		}).Times(1)
+		mocks.ExpectGetFocusedWindow(conn, aerospace.Window{WindowID: 2, AppName: "app2"}).Times(1)
+		strg.EXPECT().PushFocusHistory(2).Return(nil).Times(1)
+		mocks.ExpectCommand(conn, "focus", gomock.Any()).Times(1)
+
+		client := startServer(t, daemon.New(strg, aerospaceClient))
+
+		var event format.OutputEvent
+		require.NoError(t, client.Call(daemon.MethodFocus, daemon.FocusParams{Mark: "mark1"}, &event))
+		assert.Equal(t, format.OutputEvent{
+			Command:  "focus",
+			Action:   "focus",
+			WindowID: 1,
+			Result:   "success",
+			Message:  "Focus moved to window ID 1",
+		}, event)
+	})
+
+	t.Run("focuses back the previous window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().GetPreviousFocus(1).Return(2, nil).Times(1)
+		strg.EXPECT().PushFocusHistory(1).Return(nil).Times(1)
+
+		conn, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetFocusedWindow(conn, aerospace.Window{WindowID: 1, AppName: "app1"}).Times(1)
+		mocks.ExpectCommand(conn, "focus", []string{"--window-id", "2"}).Times(1)
+
+		client := startServer(t, daemon.New(strg, aerospaceClient))
+
+		var event format.OutputEvent
+		require.NoError(t, client.Call(daemon.MethodFocus, daemon.FocusParams{Back: true}, &event))
+		assert.Equal(t, "focus_back", event.Action)
+		assert.Equal(t, 2, event.WindowID)
+	})
+
+	t.Run("reports errors as RPC errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		client := startServer(t, daemon.New(strg, aerospaceClient))
+
+		err := client.Call("unknown", nil, nil)
+		require.Error(t, err)
+		assert.True(t, daemon.IsRPCError(err))
+		assert.Equal(t, "method not found: unknown", err.Error())
+
+		err = client.Call(daemon.MethodFocus, nil, nil)
+		require.Error(t, err)
+		assert.True(t, daemon.IsRPCError(err))
+		assert.Equal(t, "missing params", err.Error())
+		assert.Equal(t, errkind.InvalidInput, errkind.Of(err))
+	})
+
+	t.Run("keeps the kind of errors across the socket", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		strg.EXPECT().
+			GetWindowByMark("missing").
+			Return(nil, errkind.New(errkind.MarkNotFound, "no window found for mark missing")).
+			Times(1)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		client := startServer(t, daemon.New(strg, aerospaceClient))
+
+		err := client.Call(daemon.MethodFocus, daemon.FocusParams{Mark: "missing"}, nil)
+		require.Error(t, err)
+		assert.True(t, daemon.IsRPCError(err))
+		assert.Equal(t, errkind.MarkNotFound, errkind.Of(err))
+		assert.Equal(t, errkind.ExitMarkNotFound, errkind.ExitCode(err))
+	})
+
+	t.Run("fails to connect when the daemon isn't running", func(t *testing.T) {
+		client := daemon.NewClient(filepath.Join(os.TempDir(), "aerospace-marks-missing.sock"))
+
+		err := client.Ping()
+		require.Error(t, err)
+		assert.False(t, daemon.IsRPCError(err))
+		require.ErrorIs(t, err, daemon.ErrNotRunning)
+		assert.Equal(t, errkind.IPCUnavailable, errkind.Of(err))
+	})
+
+	t.Run("fails without ErrNotRunning once connected", func(t *testing.T) {
+		// Short socket path, t.TempDir is too long for a socket on macOS
+		dir, err := os.MkdirTemp("", "marks")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		socketPath := filepath.Join(dir, "d.sock")
+		listener, err := net.Listen("unix", socketPath)
+		require.NoError(t, err)
+		defer listener.Close()
+		go func() {
+			// Hangs up without answering, the request may have been run
+			conn, acceptErr := listener.Accept()
+			if acceptErr == nil {
+				conn.Close()
+			}
+		}()
+
+		err = daemon.NewClient(socketPath).Ping()
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, daemon.ErrNotRunning)
+		assert.False(t, daemon.IsRPCError(err))
+		assert.Equal(t, errkind.IPCUnavailable, errkind.Of(err))
+	})
+
+	t.Run("refuses to listen on a socket in use", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, strg := mocks.MockStorageDBClient(ctrl)
+		_, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+
+		dir, err := os.MkdirTemp("", "marks")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		socketPath := filepath.Join(dir, "d.sock")
+
+		listener, err := daemon.Listen(socketPath)
+		require.NoError(t, err)
+		server := daemon.NewServer()
+		daemon.New(strg, aerospaceClient).Register(server)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = server.Serve(ctx, listener)
+		}()
+
+		_, err = daemon.Listen(socketPath)
+		require.Error(t, err)
+
+		cancel()
+		<-done
+
+		// The socket can be used again once the daemon stops
+		listener, err = daemon.Listen(socketPath)
+		require.NoError(t, err)
+		require.NoError(t, listener.Close())
+	})
+	t.Run("refuses to remove a file that isn't a socket", func(t *testing.T) {
+		dir, err := os.MkdirTemp("", "marks")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "d.sock")
+		require.NoError(t, os.WriteFile(path, []byte("data"), 0o600))
+
+		_, err = daemon.Listen(path)
+		require.Error(t, err)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(content))
+	})
+
+	t.Run("defaults the socket to the runtime directory", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceMarksSocket, "")
+		t.Setenv("XDG_RUNTIME_DIR", "/run/user/501")
+
+		assert.Equal(t, "/run/user/501/aerospace-marks.sock", daemon.SocketPath())
+	})
+}
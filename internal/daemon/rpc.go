@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+)
+
+// This module contains the wire protocol used on the daemon socket.
+//
+// Messages are JSON-RPC 2.0 objects, one per line (newline delimited).
+
+// JSONRPCVersion is the protocol version sent in every message.
+const JSONRPCVersion = "2.0"
+
+// JSON-RPC 2.0 error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a JSON-RPC request.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the error object of a JSON-RPC response.
+//
+// It is also returned by the Client when the daemon answers with an error,
+// so callers can tell daemon errors apart from connection errors.
+type RPCError struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    *RPCErrorData `json:"data,omitempty"`
+}
+
+// RPCErrorData is the data of an RPCError, it tells the kind of the error
+// so the client exits with the same code as without the daemon.
+type RPCErrorData struct {
+	Kind errkind.Kind `json:"kind"`
+}
+
+func (e *RPCError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error as its errkind.Kind, so errkind.Of classifies
+// errors answered by the daemon. Invalid params are invalid input.
+func (e *RPCError) Unwrap() error {
+	switch {
+	case e.Data != nil && e.Data.Kind != "":
+		return errkind.New(e.Data.Kind, e.Message)
+	case e.Code == CodeInvalidParams:
+		return errkind.New(errkind.InvalidInput, e.Message)
+	default:
+		return nil
+	}
+}
+
+// NewRPCError creates an RPCError with the given code.
+func NewRPCError(code int, format string, a ...any) *RPCError {
+	return &RPCError{
+		Code:    code,
+		Message: fmt.Sprintf(format, a...),
+	}
+}
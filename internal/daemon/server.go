@@ -0,0 +1,226 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+)
+
+const (
+	// socketFileMode restricts the socket to the current user.
+	socketFileMode = 0o600
+	// socketDirMode restricts a missing socket directory to the current user.
+	socketDirMode = 0o700
+	// maxMessageSize is the maximum size of a single request line.
+	maxMessageSize = 1024 * 1024
+)
+
+// HandlerFunc handles a JSON-RPC method call.
+//
+// The returned result is marshalled as the response result. Returning an
+// *RPCError sets its code on the response, any other error is reported
+// as an internal error.
+type HandlerFunc func(params json.RawMessage) (any, error)
+
+// Server dispatches JSON-RPC requests received on a Unix socket.
+type Server struct {
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// NewServer creates a Server without any method.
+func NewServer() *Server {
+	return &Server{
+		handlers: make(map[string]HandlerFunc),
+	}
+}
+
+// Register adds a method to the server. Registering the same method twice
+// replaces the previous handler.
+func (s *Server) Register(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// Listen creates the Unix socket at the given path.
+//
+// A stale socket left by a previous process is removed, but it fails if
+// another process is still listening on it or the path isn't a socket.
+func Listen(socketPath string) (net.Listener, error) {
+	if info, err := os.Lstat(socketPath); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", socketPath)
+		}
+		if conn, dialErr := net.Dial("unix", socketPath); dialErr == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("socket %s is already in use", socketPath)
+		}
+		if removeErr := os.Remove(socketPath); removeErr != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", socketPath, removeErr)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(socketPath), socketDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create the socket directory: %w", err)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", socketPath, err)
+	}
+
+	if err := os.Chmod(socketPath, socketFileMode); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+
+	return listener, nil
+}
+
+// Serve accepts connections until the context is cancelled or the listener
+// is closed. The listener is closed when Serve returns.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	log := logger.GetDefaultLogger()
+
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			log.LogError("failed to accept connection", "err", err)
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handleConn(ctx, conn)
+		}()
+	}
+}
+
+func (s *Server) handleConn(ctx context.Context, conn net.Conn) {
+	log := logger.GetDefaultLogger()
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.LogDebug("failed to close connection", "err", err)
+		}
+	}()
+
+	// Unblock the scanner when the server stops
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxMessageSize)
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		response := s.Handle(line)
+		if err := encoder.Encode(response); err != nil {
+			log.LogError("failed to write response", "err", err)
+			return
+		}
+	}
+}
+
+// Handle decodes a single request and returns its response.
+func (s *Server) Handle(message []byte) Response {
+	var request Request
+	if err := json.Unmarshal(message, &request); err != nil {
+		return errorResponse(nil, NewRPCError(CodeParseError, "invalid JSON: %s", err))
+	}
+	if request.Method == "" {
+		return errorResponse(request.ID, NewRPCError(CodeInvalidRequest, "missing method"))
+	}
+
+	s.mu.RLock()
+	handler, ok := s.handlers[request.Method]
+	s.mu.RUnlock()
+	if !ok {
+		return errorResponse(
+			request.ID,
+			NewRPCError(CodeMethodNotFound, "method not found: %s", request.Method),
+		)
+	}
+
+	logger.GetDefaultLogger().LogDebug("daemon request", "method", request.Method)
+	result, err := handler(request.Params)
+	if err != nil {
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			return errorResponse(request.ID, rpcErr)
+		}
+		internalErr := NewRPCError(CodeInternalError, "%s", err)
+		if kind := errkind.Of(err); kind != errkind.Unknown {
+			internalErr.Data = &RPCErrorData{Kind: kind}
+		}
+		return errorResponse(request.ID, internalErr)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(
+			request.ID,
+			NewRPCError(CodeInternalError, "failed to encode result: %s", err),
+		)
+	}
+
+	return Response{
+		JSONRPC: JSONRPCVersion,
+		ID:      request.ID,
+		Result:  data,
+	}
+}
+
+// DecodeParams decodes the request params into the given value.
+//
+// Returns an *RPCError with CodeInvalidParams on failure.
+func DecodeParams(params json.RawMessage, value any) error {
+	if len(params) == 0 {
+		return NewRPCError(CodeInvalidParams, "missing params")
+	}
+	if err := json.Unmarshal(params, value); err != nil {
+		return NewRPCError(CodeInvalidParams, "invalid params: %s", err)
+	}
+	return nil
+}
+
+func errorResponse(id json.RawMessage, err *RPCError) Response {
+	return Response{
+		JSONRPC: JSONRPCVersion,
+		ID:      id,
+		Error:   err,
+	}
+}
@@ -0,0 +1,132 @@
+// Package errkind classifies the errors of aerospace-marks so the CLI can
+// exit with a distinct code for each kind of failure.
+package errkind
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Kind is the kind of an error, as shown in JSON error output.
+type Kind string
+
+const (
+	// Unknown is the kind of errors that weren't classified.
+	Unknown Kind = "error"
+	// InvalidInput is the kind of errors caused by invalid arguments or flags.
+	InvalidInput Kind = "invalid_input"
+	// MarkNotFound is the kind of errors for marks that don't exist.
+	MarkNotFound Kind = "mark_not_found"
+	// WindowGone is the kind of errors for marked windows that were closed.
+	WindowGone Kind = "window_gone"
+	// IPCUnavailable is the kind of errors connecting to AeroSpace or the daemon.
+	IPCUnavailable Kind = "ipc_unavailable"
+	// Storage is the kind of errors reading or writing the marks database.
+	Storage Kind = "storage_failure"
+	// TagNotFound is the kind of errors for tags that no window has.
+	TagNotFound Kind = "tag_not_found"
+)
+
+// Exit codes of the CLI, one per error kind.
+const (
+	ExitOK             = 0
+	ExitFailure        = 1
+	ExitInvalidInput   = 2
+	ExitMarkNotFound   = 3
+	ExitWindowGone     = 4
+	ExitIPCUnavailable = 5
+	ExitStorage        = 6
+	ExitTagNotFound    = 7
+)
+
+// ExitCode returns the exit code of the kind.
+func (k Kind) ExitCode() int {
+	switch k {
+	case InvalidInput:
+		return ExitInvalidInput
+	case MarkNotFound:
+		return ExitMarkNotFound
+	case WindowGone:
+		return ExitWindowGone
+	case IPCUnavailable:
+		return ExitIPCUnavailable
+	case Storage:
+		return ExitStorage
+	case TagNotFound:
+		return ExitTagNotFound
+	case Unknown:
+		return ExitFailure
+	default:
+		return ExitFailure
+	}
+}
+
+// Error is an error of a known kind. Its message is the message of the
+// wrapped error.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error of the given kind with a message.
+func New(kind Kind, message string) error {
+	return &Error{Kind: kind, Err: errors.New(message)}
+}
+
+// Errorf returns an error of the given kind formatted as fmt.Errorf does,
+// so %w keeps wrapped errors matchable with errors.Is.
+func Errorf(kind Kind, format string, a ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, a...)}
+}
+
+// Wrap marks err as an error of the given kind. It returns nil for a nil
+// error and keeps the kind of errors that are already classified.
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var kindErr *Error
+	if errors.As(err, &kindErr) {
+		return err
+	}
+
+	return &Error{Kind: kind, Err: err}
+}
+
+// Of returns the kind of err: the outermost kind in its chain or, for
+// unclassified errors, IPCUnavailable when a socket couldn't be reached.
+func Of(err error) Kind {
+	var kindErr *Error
+	if errors.As(err, &kindErr) {
+		return kindErr.Kind
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return IPCUnavailable
+	}
+
+	return Unknown
+}
+
+// ExitCode returns the exit code for err, ExitOK when it is nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	return Of(err).ExitCode()
+}
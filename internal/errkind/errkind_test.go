@@ -0,0 +1,74 @@
+package errkind_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"no error", nil, errkind.ExitOK},
+		{"unclassified error", errors.New("boom"), errkind.ExitFailure},
+		{"invalid input", errkind.New(errkind.InvalidInput, "bad"), errkind.ExitInvalidInput},
+		{"mark not found", errkind.New(errkind.MarkNotFound, "missing"), errkind.ExitMarkNotFound},
+		{"window gone", errkind.New(errkind.WindowGone, "gone"), errkind.ExitWindowGone},
+		{"ipc", errkind.New(errkind.IPCUnavailable, "down"), errkind.ExitIPCUnavailable},
+		{"storage failure", errkind.New(errkind.Storage, "locked"), errkind.ExitStorage},
+		{"tag not found", errkind.New(errkind.TagNotFound, "missing"), errkind.ExitTagNotFound},
+		{
+			"wrapped with fmt.Errorf",
+			fmt.Errorf("failed: %w", errkind.New(errkind.WindowGone, "gone")),
+			errkind.ExitWindowGone,
+		},
+		{
+			"socket error",
+			&net.OpError{Op: "dial", Net: "unix", Err: syscall.ECONNREFUSED},
+			errkind.ExitIPCUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errkind.ExitCode(tt.err))
+		})
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	sentinel := errors.New("window no longer exists")
+	err := errkind.Errorf(errkind.WindowGone, "%w: mark '%s'", sentinel, "term")
+
+	assert.Equal(t, "window no longer exists: mark 'term'", err.Error())
+	require.ErrorIs(t, err, sentinel)
+	assert.Equal(t, errkind.WindowGone, errkind.Of(err))
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("returns nil for no error", func(t *testing.T) {
+		require.NoError(t, errkind.Wrap(errkind.Storage, nil))
+	})
+
+	t.Run("keeps the message", func(t *testing.T) {
+		err := errkind.Wrap(errkind.Storage, errors.New("database is locked"))
+
+		assert.Equal(t, "database is locked", err.Error())
+		assert.Equal(t, errkind.Storage, errkind.Of(err))
+	})
+
+	t.Run("keeps the kind of classified errors", func(t *testing.T) {
+		err := errkind.Wrap(errkind.Storage, errkind.New(errkind.MarkNotFound, "missing"))
+
+		assert.Equal(t, errkind.MarkNotFound, errkind.Of(err))
+	})
+}
@@ -0,0 +1,46 @@
+package format
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+)
+
+// DocumentFormats returns the output formats of reports, like the ones of
+// info and doctor, which aren't lists of windows or command results.
+func DocumentFormats() []OutputFormat {
+	return []OutputFormat{OutputFormatText, OutputFormatJSON, OutputFormatYAML}
+}
+
+// ParseDocumentFormat validates a user given output format of a report.
+func ParseDocumentFormat(format string) (OutputFormat, error) {
+	normalized := OutputFormat(strings.ToLower(strings.TrimSpace(format)))
+	if slices.Contains(DocumentFormats(), normalized) {
+		return normalized, nil
+	}
+
+	return "", errkind.Errorf(
+		errkind.InvalidInput,
+		"unsupported output format: %s (valid formats: text, json or yaml)",
+		format,
+	)
+}
+
+// WriteDocument writes a report as JSON or YAML, commands write the text
+// format themselves.
+func WriteDocument(w io.Writer, outputFormat OutputFormat, value any) error {
+	if outputFormat == OutputFormatYAML {
+		return writeYAML(w, value)
+	}
+	if outputFormat == OutputFormatJSON {
+		return writeJSON(w, value)
+	}
+
+	return errkind.Errorf(
+		errkind.InvalidInput,
+		"output format %s is not a document format",
+		outputFormat,
+	)
+}
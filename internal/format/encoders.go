@@ -0,0 +1,154 @@
+package format
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// structuredOutput is what the machine readable formats shared by every
+// formatter write: json, ndjson, yaml, csv and tsv.
+type structuredOutput[T any] struct {
+	items   []T
+	columns []column[T]
+	// projected encodes only the columns as keys in json, ndjson and yaml,
+	// instead of the whole items
+	projected bool
+	// single encodes the only item as an object in json and yaml, instead
+	// of as a list
+	single bool
+}
+
+// write writes the items in outputFormat: as a whole in json and yaml, as
+// one line per item in ndjson and as one row per item in csv and tsv.
+func (s structuredOutput[T]) write(w io.Writer, outputFormat OutputFormat) error {
+	switch outputFormat {
+	case OutputFormatJSON:
+		return writeJSON(w, s.value())
+	case OutputFormatNDJSON:
+		return writeNDJSON(w, s.objects())
+	case OutputFormatYAML:
+		return writeYAML(w, s.value())
+	case OutputFormatCSV:
+		return writeCSV(w, columnNames(s.columns), s.rows())
+	case OutputFormatTSV:
+		return writeTSV(w, columnNames(s.columns), s.rows())
+	case OutputFormatText, OutputFormatTemplate:
+		return fmt.Errorf("output format %s is not a structured format", outputFormat)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+// objects returns the items, or their projections when projected.
+func (s structuredOutput[T]) objects() []any {
+	objects := make([]any, len(s.items))
+	for i, item := range s.items {
+		if s.projected {
+			objects[i] = project(item, s.columns)
+		} else {
+			objects[i] = item
+		}
+	}
+
+	return objects
+}
+
+// value returns the objects, or the only object when single.
+func (s structuredOutput[T]) value() any {
+	objects := s.objects()
+	if s.single && len(objects) == 1 {
+		return objects[0]
+	}
+
+	return objects
+}
+
+func (s structuredOutput[T]) rows() [][]string {
+	rows := make([][]string, len(s.items))
+	for i, item := range s.items {
+		rows[i] = columnTexts(item, s.columns)
+	}
+
+	return rows
+}
+
+// writeJSON writes value as indented JSON.
+func writeJSON(w io.Writer, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// writeNDJSON writes every item as compact JSON on its own line. Nothing is
+// written for no items, so the output can be streamed and concatenated.
+func writeNDJSON(w io.Writer, items []any) error {
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		if _, err = fmt.Fprintln(w, string(data)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeYAML writes value as a YAML document indented with two spaces.
+func writeYAML(w io.Writer, value any) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+// writeCSV writes the header and rows as comma-separated values.
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	return writer.Error()
+}
+
+// writeTSV writes the header and rows as tab-separated values. Fields are
+// never quoted; backslashes, tabs and line breaks are escaped as \\, \t, \n
+// and \r so every row stays on one line and splits cleanly on tabs.
+func writeTSV(w io.Writer, header []string, rows [][]string) error {
+	escaper := strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+	var b strings.Builder
+	for _, row := range append([][]string{header}, rows...) {
+		for i, field := range row {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			b.WriteString(escaper.Replace(field))
+		}
+		b.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,153 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"gopkg.in/yaml.v3"
+)
+
+// WithFields limits the output of ListOutputFormatter to the given fields,
+// in the given order. Fields are named as the JSON keys, e.g. "mark" or
+// "window_id". The template output format ignores it.
+func WithFields(fields []string) FormatterOption {
+	return func(opts *formatterOptions) {
+		opts.fields = fields
+	}
+}
+
+// column is a field of T as shown in every output format.
+type column[T any] struct {
+	// name is the JSON and YAML key and the CSV and TSV header
+	name string
+	// value is the JSON and YAML value when only some fields are selected
+	value func(item T) any
+	// text is the value in the text, CSV and TSV formats
+	text func(item T) string
+}
+
+// stringColumn is a column of a string field, shown as is in every format.
+func stringColumn[T any](name string, text func(item T) string) column[T] {
+	return column[T]{
+		name:  name,
+		value: func(item T) any { return text(item) },
+		text:  text,
+	}
+}
+
+// selectColumns returns the columns with the given names, in order, or every
+// column when no name is given.
+func selectColumns[T any](all []column[T], names []string) ([]column[T], error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	selected := make([]column[T], 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, col := range all {
+			if col.name == name {
+				selected = append(selected, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errkind.Errorf(
+				errkind.InvalidInput,
+				"unknown field '%s' (valid fields: %s)",
+				name,
+				strings.Join(columnNames(all), ", "),
+			)
+		}
+	}
+
+	return selected, nil
+}
+
+func columnNames[T any](cols []column[T]) []string {
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.name
+	}
+
+	return names
+}
+
+// columnTexts returns the text value of every column of item.
+func columnTexts[T any](item T, cols []column[T]) []string {
+	texts := make([]string, len(cols))
+	for i, col := range cols {
+		texts[i] = col.text(item)
+	}
+
+	return texts
+}
+
+// projectedObject is an object with only the selected fields, encoded with
+// its keys in the order they were selected.
+type projectedObject struct {
+	keys   []string
+	values []any
+}
+
+func project[T any](item T, cols []column[T]) projectedObject {
+	obj := projectedObject{
+		keys:   make([]string, len(cols)),
+		values: make([]any, len(cols)),
+	}
+	for i, col := range cols {
+		obj.keys[i] = col.name
+		obj.values[i] = col.value(item)
+	}
+
+	return obj
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o projectedObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		keyData, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueData, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(keyData)
+		b.WriteByte(':')
+		b.Write(valueData)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (o projectedObject) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i, key := range o.keys {
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(o.values[i]); err != nil {
+			return nil, err
+		}
+
+		node.Content = append(
+			node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+			valueNode,
+		)
+	}
+
+	return node, nil
+}
@@ -1,7 +1,6 @@
 package format
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -10,7 +9,7 @@ import (
 
 // FormatTableList formats a list of Mark objects into a string
 // Receive a | separated list of strings and make sure that
-// the columns are aligned with the same width
+// the columns are aligned with the same display width
 //
 // Example:
 //
@@ -32,10 +31,11 @@ func FormatTableList(list []string) string {
 		fields := strings.Split(line, "|")
 		for i := range fields {
 			fields[i] = strings.TrimSpace(fields[i])
+			width := DisplayWidth(fields[i])
 			if len(colWidths) <= i {
-				colWidths = append(colWidths, len(fields[i]))
-			} else if len(fields[i]) > colWidths[i] {
-				colWidths[i] = len(fields[i])
+				colWidths = append(colWidths, width)
+			} else if width > colWidths[i] {
+				colWidths[i] = width
 			}
 		}
 		rows = append(rows, fields)
@@ -44,7 +44,8 @@ func FormatTableList(list []string) string {
 	var b strings.Builder
 	for i, row := range rows {
 		for j, field := range row {
-			fmt.Fprintf(&b, "%-*s", colWidths[j], field)
+			b.WriteString(field)
+			b.WriteString(strings.Repeat(" ", colWidths[j]-DisplayWidth(field)))
 			if j < len(row)-1 {
 				b.WriteString(" | ")
 			}
@@ -39,3 +39,18 @@ func TestTableOutFormatWithSixColumns(t *testing.T) {
 	assert.Equal(t, "mark2 | 2 | app2 super long | title2 | _          | bundle2", lines[1])
 	assert.Equal(t, "mark3 | 3 | app3            | _      | workspace3 | _      ", lines[2])
 }
+
+func TestTableOutFormatWideCharacters(t *testing.T) {
+	list := []string{
+		"1 | 日本語 | title1",
+		"2 | café | title2",
+		"3 | app | title3",
+	}
+	result := format.FormatTableList(list)
+	lines := strings.Split(result, "\n")
+
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "1 | 日本語 | title1", lines[0])
+	assert.Equal(t, "2 | café   | title2", lines[1])
+	assert.Equal(t, "3 | app    | title3", lines[2])
+}
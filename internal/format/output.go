@@ -1,12 +1,14 @@
 package format
 
 import (
-	"encoding/csv"
-	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
+	"text/template"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
 )
 
 // OutputFormat represents the output format type.
@@ -17,176 +19,358 @@ const (
 	OutputFormatText OutputFormat = "text"
 	// OutputFormatJSON outputs data as JSON array.
 	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatNDJSON outputs data as one JSON object per line.
+	OutputFormatNDJSON OutputFormat = "ndjson"
+	// OutputFormatYAML outputs data as a YAML document.
+	OutputFormatYAML OutputFormat = "yaml"
 	// OutputFormatCSV outputs data as comma-separated values.
 	OutputFormatCSV OutputFormat = "csv"
+	// OutputFormatTSV outputs data as tab-separated values.
+	OutputFormatTSV OutputFormat = "tsv"
+	// OutputFormatTemplate renders each item with a Go text/template.
+	OutputFormatTemplate OutputFormat = "template"
 )
 
-const (
-	// textFormatColumnCount is the number of columns in the text output format.
-	textFormatColumnCount = 6
-)
+// OutputFormats returns every supported output format.
+func OutputFormats() []OutputFormat {
+	return []OutputFormat{
+		OutputFormatText,
+		OutputFormatJSON,
+		OutputFormatNDJSON,
+		OutputFormatYAML,
+		OutputFormatCSV,
+		OutputFormatTSV,
+		OutputFormatTemplate,
+	}
+}
+
+// OutputFormatNames returns the supported output formats as a readable
+// list, e.g. for flag help: "text, json, ... or template".
+func OutputFormatNames() string {
+	formats := OutputFormats()
+	names := make([]string, len(formats)-1)
+	for i, f := range formats[:len(formats)-1] {
+		names[i] = string(f)
+	}
+
+	return strings.Join(names, ", ") + " or " + string(formats[len(formats)-1])
+}
+
+// parseOutputFormat validates a user given output format.
+func parseOutputFormat(format string) (OutputFormat, error) {
+	normalized := OutputFormat(strings.ToLower(strings.TrimSpace(format)))
+	if slices.Contains(OutputFormats(), normalized) {
+		return normalized, nil
+	}
+
+	return "", errkind.Errorf(
+		errkind.InvalidInput,
+		"unsupported output format: %s (valid formats: %s)",
+		format,
+		OutputFormatNames(),
+	)
+}
 
 // MarkedWindow represents a window with its mark.
 type MarkedWindow struct {
-	Mark        string `json:"mark"`
-	WindowID    int    `json:"window_id"`
-	AppName     string `json:"app_name"`
-	WindowTitle string `json:"window_title"`
-	Workspace   string `json:"workspace"`
-	AppBundleID string `json:"app_bundle_id"`
+	Mark        string `json:"mark" yaml:"mark"`
+	WindowID    int    `json:"window_id" yaml:"window_id"`
+	AppName     string `json:"app_name" yaml:"app_name"`
+	WindowTitle string `json:"window_title" yaml:"window_title"`
+	Workspace   string `json:"workspace" yaml:"workspace"`
+	AppBundleID string `json:"app_bundle_id" yaml:"app_bundle_id"`
+	// Tags are the group tags of the window
+	Tags []string `json:"tags" yaml:"tags"`
+}
+
+// WindowMarks represents a window with all of its marks.
+type WindowMarks struct {
+	Marks       []string `json:"marks" yaml:"marks"`
+	WindowID    int      `json:"window_id" yaml:"window_id"`
+	AppName     string   `json:"app_name" yaml:"app_name"`
+	WindowTitle string   `json:"window_title" yaml:"window_title"`
+	Workspace   string   `json:"workspace" yaml:"workspace"`
+	AppBundleID string   `json:"app_bundle_id" yaml:"app_bundle_id"`
+	// Tags are the group tags of the window
+	Tags []string `json:"tags" yaml:"tags"`
+}
+
+// GroupByWindow aggregates marked windows into one entry per window,
+// keeping the order in which windows first appear.
+func GroupByWindow(windows []MarkedWindow) []WindowMarks {
+	grouped := make([]WindowMarks, 0, len(windows))
+	indexByID := make(map[int]int, len(windows))
+	for _, w := range windows {
+		if i, ok := indexByID[w.WindowID]; ok {
+			grouped[i].Marks = append(grouped[i].Marks, w.Mark)
+			continue
+		}
+
+		indexByID[w.WindowID] = len(grouped)
+		grouped = append(grouped, WindowMarks{
+			Marks:       []string{w.Mark},
+			WindowID:    w.WindowID,
+			AppName:     w.AppName,
+			WindowTitle: w.WindowTitle,
+			Workspace:   w.Workspace,
+			AppBundleID: w.AppBundleID,
+			Tags:        w.Tags,
+		})
+	}
+
+	return grouped
+}
+
+// markedWindowColumns returns the fields of MarkedWindow.
+func markedWindowColumns() []column[MarkedWindow] {
+	return []column[MarkedWindow]{
+		stringColumn("mark", func(w MarkedWindow) string { return w.Mark }),
+		{
+			name:  "window_id",
+			value: func(w MarkedWindow) any { return w.WindowID },
+			text:  func(w MarkedWindow) string { return strconv.Itoa(w.WindowID) },
+		},
+		stringColumn("app_name", func(w MarkedWindow) string { return w.AppName }),
+		stringColumn("window_title", func(w MarkedWindow) string { return w.WindowTitle }),
+		stringColumn("workspace", func(w MarkedWindow) string { return w.Workspace }),
+		stringColumn("app_bundle_id", func(w MarkedWindow) string { return w.AppBundleID }),
+		{
+			name:  "tags",
+			value: func(w MarkedWindow) any { return nonNilList(w.Tags) },
+			text:  func(w MarkedWindow) string { return strings.Join(w.Tags, ",") },
+		},
+	}
+}
+
+// windowMarksColumns returns the fields of WindowMarks.
+func windowMarksColumns() []column[WindowMarks] {
+	return []column[WindowMarks]{
+		{
+			name:  "marks",
+			value: func(w WindowMarks) any { return nonNilList(w.Marks) },
+			text:  func(w WindowMarks) string { return strings.Join(w.Marks, ",") },
+		},
+		{
+			name:  "window_id",
+			value: func(w WindowMarks) any { return w.WindowID },
+			text:  func(w WindowMarks) string { return strconv.Itoa(w.WindowID) },
+		},
+		stringColumn("app_name", func(w WindowMarks) string { return w.AppName }),
+		stringColumn("window_title", func(w WindowMarks) string { return w.WindowTitle }),
+		stringColumn("workspace", func(w WindowMarks) string { return w.Workspace }),
+		stringColumn("app_bundle_id", func(w WindowMarks) string { return w.AppBundleID }),
+		{
+			name:  "tags",
+			value: func(w WindowMarks) any { return nonNilList(w.Tags) },
+			text:  func(w WindowMarks) string { return strings.Join(w.Tags, ",") },
+		},
+	}
+}
+
+// MarkedWindowFields returns the fields of a marked window accepted by
+// WithFields.
+func MarkedWindowFields() []string {
+	return columnNames(markedWindowColumns())
+}
+
+// WindowMarksFields returns the fields of a window with its marks accepted
+// by WithFields.
+func WindowMarksFields() []string {
+	return columnNames(windowMarksColumns())
+}
+
+// nonNilList makes lists without items encode as [] rather than null.
+func nonNilList(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+
+	return list
 }
 
 // ListOutputFormatter formats a list of marked windows.
 type ListOutputFormatter struct {
-	format OutputFormat
-	writer io.Writer
+	format   OutputFormat
+	writer   io.Writer
+	template *template.Template
+	fields   []string
+	table    tableOptions
 }
 
 // NewListOutputFormatter creates a new ListOutputFormatter.
-func NewListOutputFormatter(w io.Writer, format string) (*ListOutputFormatter, error) {
-	normalized := strings.ToLower(strings.TrimSpace(format))
-	switch normalized {
-	case string(OutputFormatText):
-		return &ListOutputFormatter{format: OutputFormatText, writer: w}, nil
-	case string(OutputFormatJSON):
-		return &ListOutputFormatter{format: OutputFormatJSON, writer: w}, nil
-	case string(OutputFormatCSV):
-		return &ListOutputFormatter{format: OutputFormatCSV, writer: w}, nil
-	default:
-		return nil, fmt.Errorf(
-			"unsupported output format: %s (valid formats: text, json, csv)",
-			format,
-		)
+//
+// The template output format requires the WithTemplate option, the template
+// is rendered once per window. WithFields selects the fields of the other
+// output formats, WithMaxWidth, WithHeader and WithColor style the text
+// format.
+func NewListOutputFormatter(
+	w io.Writer,
+	format string,
+	opts ...FormatterOption,
+) (*ListOutputFormatter, error) {
+	outputFormat, err := parseOutputFormat(format)
+	if err != nil {
+		return nil, err
 	}
+
+	tmpl, options, err := newFormatterOptions(outputFormat, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListOutputFormatter{
+		format:   outputFormat,
+		writer:   w,
+		template: tmpl,
+		fields:   options.fields,
+		table:    options.table,
+	}, nil
 }
 
 // Format formats and writes the list of marked windows.
 func (f *ListOutputFormatter) Format(windows []MarkedWindow) error {
-	switch f.format {
-	case OutputFormatJSON:
-		return f.formatJSON(windows)
-	case OutputFormatCSV:
-		return f.formatCSV(windows)
-	case OutputFormatText:
-		return f.formatText(windows)
-	default:
-		return fmt.Errorf("unsupported output format: %s", f.format)
+	// Windows without tags have an empty list rather than null
+	if f.format != OutputFormatTemplate {
+		for i := range windows {
+			windows[i].Tags = nonNilList(windows[i].Tags)
+		}
 	}
+
+	return formatList(f, windows, markedWindowColumns())
 }
 
-// FormatEmpty formats and writes empty results with an optional message for text format.
-// For JSON, outputs "[]". For CSV, outputs header only. For text, outputs the message.
-func (f *ListOutputFormatter) FormatEmpty(message string) error {
-	switch f.format {
-	case OutputFormatJSON:
-		_, err := fmt.Fprintln(f.writer, "[]")
+// FormatByWindow formats and writes the list of windows with their marks.
+// Marks are a list in JSON, NDJSON and YAML and comma separated in text,
+// CSV and TSV.
+func (f *ListOutputFormatter) FormatByWindow(windows []WindowMarks) error {
+	// Windows without tags have an empty list rather than null
+	if f.format != OutputFormatTemplate {
+		for i := range windows {
+			windows[i].Tags = nonNilList(windows[i].Tags)
+		}
+	}
+
+	return formatList(f, windows, windowMarksColumns())
+}
+
+// FormatWindow formats and writes a single marked window: an object rather
+// than a list in JSON and YAML. In text, a single field is written bare,
+// without a new line, so it can be used in command substitutions.
+func (f *ListOutputFormatter) FormatWindow(window MarkedWindow) error {
+	cols, err := selectColumns(markedWindowColumns(), f.fields)
+	if err != nil {
 		return err
-	case OutputFormatCSV:
-		return f.formatCSV([]MarkedWindow{})
+	}
+
+	switch f.format {
+	case OutputFormatTemplate:
+		return executeTemplate(f.writer, f.template, window)
 	case OutputFormatText:
-		if message != "" {
-			_, err := fmt.Fprintln(f.writer, message)
+		if len(cols) == 1 {
+			_, err = fmt.Fprint(f.writer, cols[0].text(window))
 			return err
 		}
-		return nil
+		return writeTable(
+			f.writer,
+			columnNames(cols),
+			textRows([]MarkedWindow{window}, cols),
+			f.table,
+		)
 	default:
-		return fmt.Errorf("unsupported output format: %s", f.format)
+		window.Tags = nonNilList(window.Tags)
+		return structuredOutput[MarkedWindow]{
+			items:     []MarkedWindow{window},
+			columns:   cols,
+			projected: len(f.fields) > 0,
+			single:    true,
+		}.write(f.writer, f.format)
 	}
 }
 
-// formatText formats windows as pipe-separated aligned columns.
-func (f *ListOutputFormatter) formatText(windows []MarkedWindow) error {
-	if len(windows) == 0 {
-		return nil
+// ValidateFields checks the fields given with WithFields before anything is
+// formatted, against the fields of FormatByWindow when byWindow is set.
+func (f *ListOutputFormatter) ValidateFields(byWindow bool) error {
+	var err error
+	if byWindow {
+		_, err = selectColumns(windowMarksColumns(), f.fields)
+	} else {
+		_, err = selectColumns(markedWindowColumns(), f.fields)
 	}
 
-	// Convert to string rows for alignment
-	rows := make([][]string, len(windows))
-	for i, w := range windows {
-		rows[i] = []string{
-			w.Mark,
-			strconv.Itoa(w.WindowID),
-			f.emptyToUnderscore(w.AppName),
-			f.emptyToUnderscore(w.WindowTitle),
-			f.emptyToUnderscore(w.Workspace),
-			f.emptyToUnderscore(w.AppBundleID),
-		}
-	}
+	return err
+}
 
-	// Calculate column widths
-	colWidths := make([]int, textFormatColumnCount)
-	for _, row := range rows {
-		for j, field := range row {
-			if len(field) > colWidths[j] {
-				colWidths[j] = len(field)
-			}
-		}
+// FormatEmpty formats and writes empty results with an optional message for text format.
+// For JSON and YAML, outputs "[]". For CSV and TSV, outputs header only. For text,
+// outputs the message. For NDJSON and template, outputs nothing.
+func (f *ListOutputFormatter) FormatEmpty(message string) error {
+	if f.format == OutputFormatText {
+		return f.formatEmptyText(message)
 	}
 
-	// Format and write
-	var b strings.Builder
-	for i, row := range rows {
-		for j, field := range row {
-			fmt.Fprintf(&b, "%-*s", colWidths[j], field)
-			if j < len(row)-1 {
-				b.WriteString(" | ")
-			}
-		}
-		if i < len(rows)-1 {
-			b.WriteByte('\n')
-		}
+	return f.Format([]MarkedWindow{})
+}
+
+// FormatByWindowEmpty is FormatEmpty for FormatByWindow: the CSV and TSV
+// header has the by-window columns.
+func (f *ListOutputFormatter) FormatByWindowEmpty(message string) error {
+	if f.format == OutputFormatText {
+		return f.formatEmptyText(message)
 	}
 
-	_, err := fmt.Fprintln(f.writer, b.String())
-	return err
+	return f.FormatByWindow([]WindowMarks{})
 }
 
-// formatJSON formats windows as JSON array.
-func (f *ListOutputFormatter) formatJSON(windows []MarkedWindow) error {
-	data, err := json.MarshalIndent(windows, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+func (f *ListOutputFormatter) formatEmptyText(message string) error {
+	if message == "" {
+		return nil
 	}
-	_, err = fmt.Fprintln(f.writer, string(data))
+
+	_, err := fmt.Fprintln(f.writer, message)
 	return err
 }
 
-// formatCSV formats windows as CSV with headers.
-func (f *ListOutputFormatter) formatCSV(windows []MarkedWindow) error {
-	writer := csv.NewWriter(f.writer)
-	defer writer.Flush()
-
-	headers := []string{
-		"mark",
-		"window_id",
-		"app_name",
-		"window_title",
-		"workspace",
-		"app_bundle_id",
-	}
-	if err := writer.Write(headers); err != nil {
-		return fmt.Errorf("failed to write CSV header: %w", err)
+// formatList writes items with the fields selected with WithFields, or
+// every field.
+func formatList[T any](f *ListOutputFormatter, items []T, all []column[T]) error {
+	cols, err := selectColumns(all, f.fields)
+	if err != nil {
+		return err
 	}
 
-	for _, w := range windows {
-		row := []string{
-			w.Mark,
-			strconv.Itoa(w.WindowID),
-			w.AppName,
-			w.WindowTitle,
-			w.Workspace,
-			w.AppBundleID,
+	switch f.format {
+	case OutputFormatTemplate:
+		return formatTemplateItems(f.writer, f.template, items)
+	case OutputFormatText:
+		if len(items) == 0 {
+			return nil
 		}
-		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("failed to write CSV row: %w", err)
+		return writeTable(f.writer, columnNames(cols), textRows(items, cols), f.table)
+	default:
+		return structuredOutput[T]{
+			items:     items,
+			columns:   cols,
+			projected: len(f.fields) > 0,
+		}.write(f.writer, f.format)
+	}
+}
+
+// textRows returns the text of the columns of every item, with "_" for
+// empty values.
+func textRows[T any](items []T, cols []column[T]) [][]string {
+	rows := make([][]string, len(items))
+	for i, item := range items {
+		rows[i] = columnTexts(item, cols)
+		for j := range rows[i] {
+			rows[i][j] = emptyToUnderscore(rows[i][j])
 		}
 	}
 
-	return writer.Error()
+	return rows
 }
 
 // emptyToUnderscore converts empty strings to "_" for text format.
-func (f *ListOutputFormatter) emptyToUnderscore(s string) string {
+func emptyToUnderscore(s string) string {
 	if s == "" {
 		return "_"
 	}
@@ -195,51 +379,80 @@ func (f *ListOutputFormatter) emptyToUnderscore(s string) string {
 
 // OutputEvent describes a single command result in a structured way.
 type OutputEvent struct {
-	Command         string `json:"command"`
-	Action          string `json:"action"`
-	WindowID        int    `json:"window_id"`
-	AppName         string `json:"app_name"`
-	Workspace       string `json:"workspace"`
-	TargetWorkspace string `json:"target_workspace"`
-	Result          string `json:"result"`
-	Message         string `json:"message"`
+	Command         string `json:"command" yaml:"command"`
+	Action          string `json:"action" yaml:"action"`
+	WindowID        int    `json:"window_id" yaml:"window_id"`
+	AppName         string `json:"app_name" yaml:"app_name"`
+	Workspace       string `json:"workspace" yaml:"workspace"`
+	TargetWorkspace string `json:"target_workspace" yaml:"target_workspace"`
+	Result          string `json:"result" yaml:"result"`
+	Message         string `json:"message" yaml:"message"`
+}
+
+// ErrorEvent describes a failed command. It has the fields of OutputEvent,
+// with Result "error" and the error as Message, plus the kind of the error
+// and the exit code of the command.
+type ErrorEvent struct {
+	OutputEvent `yaml:",inline"`
+
+	ErrorKind string `json:"error_kind" yaml:"error_kind"`
+	ExitCode  int    `json:"exit_code" yaml:"exit_code"`
+}
+
+// NewErrorEvent describes err as the failure of command.
+func NewErrorEvent(command string, err error) ErrorEvent {
+	return ErrorEvent{
+		OutputEvent: OutputEvent{
+			Command: command,
+			Result:  "error",
+			Message: err.Error(),
+		},
+		ErrorKind: string(errkind.Of(err)),
+		ExitCode:  errkind.ExitCode(err),
+	}
 }
 
 // OutputEventFormatter formats a single command result event.
 type OutputEventFormatter struct {
-	format OutputFormat
-	writer io.Writer
+	format   OutputFormat
+	writer   io.Writer
+	template *template.Template
 }
 
 // NewOutputEventFormatter creates a new OutputEventFormatter.
-func NewOutputEventFormatter(w io.Writer, format string) (*OutputEventFormatter, error) {
-	normalized := strings.ToLower(strings.TrimSpace(format))
-	switch normalized {
-	case string(OutputFormatText):
-		return &OutputEventFormatter{format: OutputFormatText, writer: w}, nil
-	case string(OutputFormatJSON):
-		return &OutputEventFormatter{format: OutputFormatJSON, writer: w}, nil
-	case string(OutputFormatCSV):
-		return &OutputEventFormatter{format: OutputFormatCSV, writer: w}, nil
-	default:
-		return nil, fmt.Errorf(
-			"unsupported output format: %s (valid formats: text, json, csv)",
-			format,
-		)
+//
+// The template output format requires the WithTemplate option.
+func NewOutputEventFormatter(
+	w io.Writer,
+	format string,
+	opts ...FormatterOption,
+) (*OutputEventFormatter, error) {
+	outputFormat, err := parseOutputFormat(format)
+	if err != nil {
+		return nil, err
 	}
+
+	tmpl, _, err := newFormatterOptions(outputFormat, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutputEventFormatter{format: outputFormat, writer: w, template: tmpl}, nil
 }
 
 // Format formats and writes a single output event.
 func (f *OutputEventFormatter) Format(event OutputEvent) error {
 	switch f.format {
-	case OutputFormatJSON:
-		return f.formatJSON(event)
-	case OutputFormatCSV:
-		return f.formatCSV(event)
 	case OutputFormatText:
 		return f.formatText(event)
+	case OutputFormatTemplate:
+		return executeTemplate(f.writer, f.template, event)
 	default:
-		return fmt.Errorf("unsupported output format: %s", f.format)
+		return structuredOutput[OutputEvent]{
+			items:   []OutputEvent{event},
+			columns: outputEventColumns(),
+			single:  true,
+		}.write(f.writer, f.format)
 	}
 }
 
@@ -281,48 +494,20 @@ func (f *OutputEventFormatter) formatText(event OutputEvent) error {
 	return err
 }
 
-// formatJSON formats event as JSON object.
-func (f *OutputEventFormatter) formatJSON(event OutputEvent) error {
-	data, err := json.MarshalIndent(event, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-	_, err = fmt.Fprintln(f.writer, string(data))
-	return err
-}
-
-// formatCSV formats event as CSV with header.
-func (f *OutputEventFormatter) formatCSV(event OutputEvent) error {
-	writer := csv.NewWriter(f.writer)
-	defer writer.Flush()
-
-	headers := []string{
-		"command",
-		"action",
-		"window_id",
-		"app_name",
-		"workspace",
-		"target_workspace",
-		"result",
-		"message",
+// outputEventColumns returns the fields of OutputEvent.
+func outputEventColumns() []column[OutputEvent] {
+	return []column[OutputEvent]{
+		stringColumn("command", func(e OutputEvent) string { return e.Command }),
+		stringColumn("action", func(e OutputEvent) string { return e.Action }),
+		{
+			name:  "window_id",
+			value: func(e OutputEvent) any { return e.WindowID },
+			text:  func(e OutputEvent) string { return strconv.Itoa(e.WindowID) },
+		},
+		stringColumn("app_name", func(e OutputEvent) string { return e.AppName }),
+		stringColumn("workspace", func(e OutputEvent) string { return e.Workspace }),
+		stringColumn("target_workspace", func(e OutputEvent) string { return e.TargetWorkspace }),
+		stringColumn("result", func(e OutputEvent) string { return e.Result }),
+		stringColumn("message", func(e OutputEvent) string { return e.Message }),
 	}
-	if err := writer.Write(headers); err != nil {
-		return fmt.Errorf("failed to write CSV header: %w", err)
-	}
-
-	row := []string{
-		event.Command,
-		event.Action,
-		strconv.Itoa(event.WindowID),
-		event.AppName,
-		event.Workspace,
-		event.TargetWorkspace,
-		event.Result,
-		event.Message,
-	}
-	if err := writer.Write(row); err != nil {
-		return fmt.Errorf("failed to write CSV row: %w", err)
-	}
-
-	return writer.Error()
 }
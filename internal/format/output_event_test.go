@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
 	"github.com/cristianoliveira/aerospace-marks/internal/format"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,6 +22,9 @@ func TestNewOutputEventFormatter(t *testing.T) {
 		{"valid text", "text", false},
 		{"valid json", "json", false},
 		{"valid csv", "csv", false},
+		{"valid ndjson", "ndjson", false},
+		{"valid yaml", "yaml", false},
+		{"valid tsv", "tsv", false},
 		{"case insensitive", "JSON", false},
 		{"with spaces", "  text  ", false},
 		{"invalid format", "invalid", true},
@@ -177,3 +181,76 @@ func TestOutputEventFormatter_FormatCSV(t *testing.T) {
 		records[1],
 	)
 }
+
+func TestOutputEventFormatter_StructuredFormats(t *testing.T) {
+	event := format.OutputEvent{
+		Command:  "focus",
+		Action:   "focus",
+		WindowID: 42,
+		AppName:  "Ghostty",
+		Result:   "success",
+		Message:  "Focused window 42",
+	}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			"ndjson",
+			`{"command":"focus","action":"focus","window_id":42,"app_name":"Ghostty",` +
+				`"workspace":"","target_workspace":"","result":"success",` +
+				`"message":"Focused window 42"}` + "\n",
+		},
+		{
+			"yaml",
+			`command: focus
+action: focus
+window_id: 42
+app_name: Ghostty
+workspace: ""
+target_workspace: ""
+result: success
+message: Focused window 42
+`,
+		},
+		{
+			"tsv",
+			"command\taction\twindow_id\tapp_name\tworkspace\ttarget_workspace\tresult\tmessage\n" +
+				"focus\tfocus\t42\tGhostty\t\t\tsuccess\tFocused window 42\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter, err := format.NewOutputEventFormatter(&buf, tt.format)
+			require.NoError(t, err)
+
+			err = formatter.Format(event)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestNewErrorEvent(t *testing.T) {
+	err := errkind.New(errkind.MarkNotFound, "no window found for mark term")
+	event := format.NewErrorEvent("focus", err)
+
+	data, marshalErr := json.Marshal(event)
+	require.NoError(t, marshalErr)
+	assert.JSONEq(t, `{
+		"command": "focus",
+		"action": "",
+		"window_id": 0,
+		"app_name": "",
+		"workspace": "",
+		"target_workspace": "",
+		"result": "error",
+		"message": "no window found for mark term",
+		"error_kind": "mark_not_found",
+		"exit_code": 3
+	}`, string(data))
+}
@@ -21,6 +21,9 @@ func TestNewListOutputFormatter(t *testing.T) {
 		{"valid text", "text", false},
 		{"valid json", "json", false},
 		{"valid csv", "csv", false},
+		{"valid ndjson", "ndjson", false},
+		{"valid yaml", "yaml", false},
+		{"valid tsv", "tsv", false},
 		{"case insensitive", "JSON", false},
 		{"with spaces", "  text  ", false},
 		{"invalid format", "invalid", true},
@@ -103,6 +106,7 @@ func TestListOutputFormatter_FormatJSON(t *testing.T) {
 			WindowTitle: "title1",
 			Workspace:   "workspace1",
 			AppBundleID: "bundle1",
+			Tags:        []string{"meeting"},
 		},
 		{
 			Mark:        "mark2",
@@ -125,6 +129,8 @@ func TestListOutputFormatter_FormatJSON(t *testing.T) {
 	assert.Equal(t, "mark1", jsonResult[0].Mark)
 	assert.Equal(t, 1, jsonResult[0].WindowID)
 	assert.Empty(t, jsonResult[1].WindowTitle) // Empty strings preserved
+	assert.Equal(t, []string{"meeting"}, jsonResult[0].Tags)
+	assert.Contains(t, result, `"tags": []`) // No tags is an empty list, not null
 }
 
 func TestListOutputFormatter_FormatJSON_Empty(t *testing.T) {
@@ -156,6 +162,7 @@ func TestListOutputFormatter_FormatCSV(t *testing.T) {
 			WindowTitle: "title1",
 			Workspace:   "workspace1",
 			AppBundleID: "bundle1",
+			Tags:        []string{"meeting", "work"},
 		},
 		{
 			Mark:        "mark2",
@@ -177,11 +184,15 @@ func TestListOutputFormatter_FormatCSV(t *testing.T) {
 	assert.Len(t, records, 3) // Header + 2 rows
 	assert.Equal(
 		t,
-		[]string{"mark", "window_id", "app_name", "window_title", "workspace", "app_bundle_id"},
+		[]string{"mark", "window_id", "app_name", "window_title", "workspace", "app_bundle_id", "tags"},
 		records[0],
 	)
-	assert.Equal(t, []string{"mark1", "1", "app1", "title1", "workspace1", "bundle1"}, records[1])
-	assert.Equal(t, []string{"mark2", "2", "app2", "", "", ""}, records[2])
+	assert.Equal(
+		t,
+		[]string{"mark1", "1", "app1", "title1", "workspace1", "bundle1", "meeting,work"},
+		records[1],
+	)
+	assert.Equal(t, []string{"mark2", "2", "app2", "", "", "", ""}, records[2])
 }
 
 func TestListOutputFormatter_FormatCSV_Empty(t *testing.T) {
@@ -199,7 +210,7 @@ func TestListOutputFormatter_FormatCSV_Empty(t *testing.T) {
 	assert.Len(t, records, 1) // Header only
 	assert.Equal(
 		t,
-		[]string{"mark", "window_id", "app_name", "window_title", "workspace", "app_bundle_id"},
+		[]string{"mark", "window_id", "app_name", "window_title", "workspace", "app_bundle_id", "tags"},
 		records[0],
 	)
 }
@@ -265,8 +276,8 @@ func TestListOutputFormatter_FormatText_ColumnAlignment(t *testing.T) {
 	// Verify columns are aligned (all lines should have same structure)
 	fields1 := strings.Split(lines[0], "|")
 	fields2 := strings.Split(lines[1], "|")
-	assert.Len(t, fields1, 6)
-	assert.Len(t, fields2, 6)
+	assert.Len(t, fields1, 7)
+	assert.Len(t, fields2, 7)
 
 	// Verify pipe separators exist
 	assert.Contains(t, lines[0], "|")
@@ -430,14 +441,19 @@ func TestListOutputFormatter_FormatCSV_ExactFormat(t *testing.T) {
 		"window_title",
 		"workspace",
 		"app_bundle_id",
+		"tags",
 	}
 	assert.Equal(t, expectedHeader, records[0])
 
 	// Verify first row
-	assert.Equal(t, []string{"mark1", "1", "App1", "Title1", "Workspace1", "com.app1"}, records[1])
+	assert.Equal(
+		t,
+		[]string{"mark1", "1", "App1", "Title1", "Workspace1", "com.app1", ""},
+		records[1],
+	)
 
 	// Verify second row with empty values
-	assert.Equal(t, []string{"mark2", "2", "", "", "", ""}, records[2])
+	assert.Equal(t, []string{"mark2", "2", "", "", "", "", ""}, records[2])
 }
 
 func TestListOutputFormatter_FormatText_ExactFormat(t *testing.T) {
@@ -471,10 +487,10 @@ func TestListOutputFormatter_FormatText_ExactFormat(t *testing.T) {
 	lines := strings.Split(result, "\n")
 	assert.Len(t, lines, 2)
 
-	// Verify structure: mark | window_id | app_name | window_title | workspace | app_bundle_id
+	// Verify structure: mark | window_id | app_name | window_title | workspace | app_bundle_id | tags
 	for _, line := range lines {
 		fields := strings.Split(line, "|")
-		assert.Len(t, fields, 6, "Each line should have 6 fields separated by |")
+		assert.Len(t, fields, 7, "Each line should have 7 fields separated by |")
 		// Trim spaces from fields
 		for i, field := range fields {
 			fields[i] = strings.TrimSpace(field)
@@ -695,6 +711,7 @@ func TestListOutputFormatter_FormatEmpty(t *testing.T) {
 			"window_title",
 			"workspace",
 			"app_bundle_id",
+			"tags",
 		}
 		assert.Equal(t, expectedHeader, records[0])
 	})
@@ -722,4 +739,305 @@ func TestListOutputFormatter_FormatEmpty(t *testing.T) {
 		result := strings.TrimSpace(buf.String())
 		assert.Empty(t, result)
 	})
+
+	t.Run("NDJSON format outputs nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(&buf, "ndjson")
+		require.NoError(t, err)
+
+		err = formatter.FormatEmpty("No marks found")
+		require.NoError(t, err)
+
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("YAML format outputs empty list", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(&buf, "yaml")
+		require.NoError(t, err)
+
+		err = formatter.FormatEmpty("No marks found")
+		require.NoError(t, err)
+
+		assert.Equal(t, "[]\n", buf.String())
+	})
+
+	t.Run("TSV format outputs header only", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(&buf, "tsv")
+		require.NoError(t, err)
+
+		err = formatter.FormatByWindowEmpty("No marks found")
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			"marks\twindow_id\tapp_name\twindow_title\tworkspace\tapp_bundle_id\ttags\n",
+			buf.String(),
+		)
+	})
+}
+
+func TestListOutputFormatter_FormatNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := format.NewListOutputFormatter(&buf, "ndjson")
+	require.NoError(t, err)
+
+	windows := []format.MarkedWindow{
+		{Mark: "mark1", WindowID: 1, AppName: "App1", WindowTitle: "Title\n1"},
+		{Mark: "mark2", WindowID: 2, AppName: "App2", Tags: []string{"web"}},
+	}
+
+	err = formatter.Format(windows)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(
+		t,
+		`{"mark":"mark1","window_id":1,"app_name":"App1","window_title":"Title\n1",`+
+			`"workspace":"","app_bundle_id":"","tags":[]}`,
+		lines[0],
+	)
+
+	var second format.MarkedWindow
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, windows[1], second)
+}
+
+func TestListOutputFormatter_FormatYAML(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := format.NewListOutputFormatter(&buf, "yaml")
+	require.NoError(t, err)
+
+	windows := []format.MarkedWindow{
+		{Mark: "1", WindowID: 1, AppName: "App1", WindowTitle: "Title: one", Tags: []string{"web"}},
+	}
+
+	err = formatter.Format(windows)
+	require.NoError(t, err)
+
+	expected := `- mark: "1"
+  window_id: 1
+  app_name: App1
+  window_title: 'Title: one'
+  workspace: ""
+  app_bundle_id: ""
+  tags:
+    - web
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestListOutputFormatter_FormatTSV(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := format.NewListOutputFormatter(&buf, "tsv")
+	require.NoError(t, err)
+
+	windows := []format.MarkedWindow{
+		{
+			Mark:        "mark1",
+			WindowID:    1,
+			AppName:     "App, Inc",
+			WindowTitle: "a\tb\nc\\d",
+			Workspace:   "ws1",
+			Tags:        []string{"web", "work"},
+		},
+	}
+
+	err = formatter.Format(windows)
+	require.NoError(t, err)
+
+	expected := "mark\twindow_id\tapp_name\twindow_title\tworkspace\tapp_bundle_id\ttags\n" +
+		"mark1\t1\tApp, Inc\ta\\tb\\nc\\\\d\tws1\t\tweb,work\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestGroupByWindow(t *testing.T) {
+	windows := []format.MarkedWindow{
+		{Mark: "a", WindowID: 1, AppName: "app1"},
+		{Mark: "b", WindowID: 2, AppName: "app2", Tags: []string{"work"}},
+		{Mark: "c", WindowID: 1, AppName: "app1"},
+		{Mark: "d", WindowID: 1, AppName: "app1"},
+	}
+
+	grouped := format.GroupByWindow(windows)
+	assert.Equal(t, []format.WindowMarks{
+		{Marks: []string{"a", "c", "d"}, WindowID: 1, AppName: "app1"},
+		{Marks: []string{"b"}, WindowID: 2, AppName: "app2", Tags: []string{"work"}},
+	}, grouped)
+	assert.Empty(t, format.GroupByWindow(nil))
+}
+
+func TestListOutputFormatter_FormatByWindow(t *testing.T) {
+	windows := []format.WindowMarks{
+		{Marks: []string{"a", "c"}, WindowID: 1, AppName: "app1"},
+		{Marks: []string{"b"}, WindowID: 22, AppName: "app2", Tags: []string{"work"}},
+	}
+
+	t.Run("text joins the marks", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(&buf, "text")
+		require.NoError(t, err)
+
+		require.NoError(t, formatter.FormatByWindow(windows))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Equal(t, []string{
+			"a,c | 1  | app1 | _ | _ | _ | _   ",
+			"b   | 22 | app2 | _ | _ | _ | work",
+		}, lines)
+	})
+
+	t.Run("json has a marks list", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(&buf, "json")
+		require.NoError(t, err)
+
+		require.NoError(t, formatter.FormatByWindow(windows))
+		var jsonResult []format.WindowMarks
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &jsonResult))
+		assert.Equal(t, []string{"a", "c"}, jsonResult[0].Marks)
+		assert.Equal(t, []string{}, jsonResult[0].Tags)
+	})
+
+	t.Run("csv joins the marks", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(&buf, "csv")
+		require.NoError(t, err)
+
+		require.NoError(t, formatter.FormatByWindow(windows))
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			[]string{"marks", "window_id", "app_name", "window_title", "workspace", "app_bundle_id", "tags"},
+			records[0],
+		)
+		assert.Equal(t, []string{"a,c", "1", "app1", "", "", "", ""}, records[1])
+	})
+
+	t.Run("empty csv has the by-window header", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(&buf, "csv")
+		require.NoError(t, err)
+
+		require.NoError(t, formatter.FormatByWindowEmpty("No marks found"))
+		assert.Equal(
+			t,
+			"marks,window_id,app_name,window_title,workspace,app_bundle_id,tags",
+			strings.TrimSpace(buf.String()),
+		)
+	})
+}
+
+func TestListOutputFormatter_WithFields(t *testing.T) {
+	windows := []format.MarkedWindow{
+		{Mark: "mark1", WindowID: 1, AppName: "App1", Workspace: "ws1"},
+		{Mark: "mark2", WindowID: 22, AppName: "App2", Tags: []string{"web"}},
+	}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"text", "ws1 | mark1\n_   | mark2\n"},
+		{"csv", "workspace,mark\nws1,mark1\n,mark2\n"},
+		{"tsv", "workspace\tmark\nws1\tmark1\n\tmark2\n"},
+		{
+			"ndjson",
+			`{"workspace":"ws1","mark":"mark1"}` + "\n" + `{"workspace":"","mark":"mark2"}` + "\n",
+		},
+		{"yaml", "- workspace: ws1\n  mark: mark1\n- workspace: \"\"\n  mark: mark2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter, err := format.NewListOutputFormatter(
+				&buf,
+				tt.format,
+				format.WithFields([]string{"workspace", "mark"}),
+			)
+			require.NoError(t, err)
+
+			err = formatter.Format(windows)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(
+			&buf,
+			"json",
+			format.WithFields([]string{"mark", "title"}),
+		)
+		require.NoError(t, err)
+
+		require.EqualError(
+			t,
+			formatter.ValidateFields(false),
+			"unknown field 'title' (valid fields: mark, window_id, app_name, window_title, "+
+				"workspace, app_bundle_id, tags)",
+		)
+		require.Error(t, formatter.Format(windows))
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("marks field by window", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(
+			&buf,
+			"csv",
+			format.WithFields([]string{"marks", "window_id"}),
+		)
+		require.NoError(t, err)
+
+		require.NoError(t, formatter.ValidateFields(true))
+		require.Error(t, formatter.ValidateFields(false))
+
+		err = formatter.FormatByWindow(format.GroupByWindow(windows))
+		require.NoError(t, err)
+		assert.Equal(t, "marks,window_id\nmark1,1\nmark2,22\n", buf.String())
+	})
+}
+
+func TestListOutputFormatter_FormatWindow(t *testing.T) {
+	window := format.MarkedWindow{Mark: "mark1", WindowID: 1, AppName: "App1", Workspace: "ws1"}
+
+	tests := []struct {
+		name     string
+		format   string
+		fields   []string
+		expected string
+	}{
+		{"single field text is bare", "text", []string{"window_id"}, "1"},
+		{"text", "text", []string{"mark", "app_name"}, "mark1 | App1\n"},
+		{
+			"json object",
+			"json",
+			[]string{"mark", "window_id"},
+			"{\n  \"mark\": \"mark1\",\n  \"window_id\": 1\n}\n",
+		},
+		{"yaml object", "yaml", []string{"mark", "tags"}, "mark: mark1\ntags: []\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter, err := format.NewListOutputFormatter(
+				&buf,
+				tt.format,
+				format.WithFields(tt.fields),
+			)
+			require.NoError(t, err)
+
+			err = formatter.FormatWindow(window)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
 }
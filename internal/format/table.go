@@ -0,0 +1,150 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// tableSeparator separates the columns of text tables.
+	tableSeparator = " | "
+	// tableEllipsis ends the truncated window titles.
+	tableEllipsis = "…"
+	// minTitleWidth is the narrowest a window title is truncated to.
+	minTitleWidth = 10
+
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// tableOptions configure the text tables of ListOutputFormatter.
+type tableOptions struct {
+	// maxWidth is the width window titles are truncated to fit, 0 for none
+	maxWidth int
+	header   bool
+	color    bool
+}
+
+// WithMaxWidth truncates the window titles of the text format, ending them
+// with "…", so rows fit in width display columns. 0 doesn't truncate.
+func WithMaxWidth(width int) FormatterOption {
+	return func(opts *formatterOptions) {
+		opts.table.maxWidth = width
+	}
+}
+
+// WithHeader adds a header row with the column names to the text format.
+func WithHeader(header bool) FormatterOption {
+	return func(opts *formatterOptions) {
+		opts.table.header = header
+	}
+}
+
+// WithColor colors the marks and workspaces of the text format with ANSI
+// escape codes.
+func WithColor(color bool) FormatterOption {
+	return func(opts *formatterOptions) {
+		opts.table.color = color
+	}
+}
+
+// DisplayWidth returns the number of terminal columns s takes: wide
+// characters like CJK and emoji take two, combining marks none.
+func DisplayWidth(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// writeTable writes rows as columns padded to the widest field by display
+// width and separated by " | ". names are the column names, for the header
+// row, the window title to truncate and the columns to color.
+func writeTable(w io.Writer, names []string, rows [][]string, opts tableOptions) error {
+	if opts.header {
+		header := make([]string, len(names))
+		for i, name := range names {
+			header[i] = strings.ToUpper(name)
+		}
+		rows = append([][]string{header}, rows...)
+	}
+
+	colWidths := make([]int, len(names))
+	for _, row := range rows {
+		for j, field := range row {
+			colWidths[j] = max(colWidths[j], DisplayWidth(field))
+		}
+	}
+
+	truncateTitles(names, rows, colWidths, opts.maxWidth)
+
+	var b strings.Builder
+	for i, row := range rows {
+		isHeader := opts.header && i == 0
+		for j, field := range row {
+			padding := strings.Repeat(" ", colWidths[j]-DisplayWidth(field))
+			b.WriteString(colorize(field, names[j], isHeader, opts.color))
+			b.WriteString(padding)
+			if j < len(row)-1 {
+				b.WriteString(tableSeparator)
+			}
+		}
+		if i < len(rows)-1 {
+			b.WriteByte('\n')
+		}
+	}
+
+	_, err := fmt.Fprintln(w, b.String())
+	return err
+}
+
+// truncateTitles shortens the window_title column, when there is one, so
+// rows fit in maxWidth. Titles are never cut below minTitleWidth.
+func truncateTitles(names []string, rows [][]string, colWidths []int, maxWidth int) {
+	if maxWidth <= 0 {
+		return
+	}
+
+	title := -1
+	rowWidth := len(tableSeparator) * (len(colWidths) - 1)
+	for j, width := range colWidths {
+		rowWidth += width
+		if names[j] == "window_title" {
+			title = j
+		}
+	}
+	if title < 0 || rowWidth <= maxWidth {
+		return
+	}
+
+	titleWidth := max(colWidths[title]-(rowWidth-maxWidth), minTitleWidth)
+	if titleWidth >= colWidths[title] {
+		return
+	}
+
+	for _, row := range rows {
+		row[title] = runewidth.Truncate(row[title], titleWidth, tableEllipsis)
+	}
+	colWidths[title] = titleWidth
+}
+
+// colorize wraps the fields of the mark and workspace columns, and the
+// header, in ANSI colors.
+func colorize(field string, name string, isHeader bool, color bool) string {
+	if !color {
+		return field
+	}
+
+	switch {
+	case isHeader:
+		return ansiBold + field + ansiReset
+	case name == "mark" || name == "marks":
+		return ansiBold + ansiYellow + field + ansiReset
+	case name == "workspace":
+		return ansiCyan + field + ansiReset
+	default:
+		return field
+	}
+}
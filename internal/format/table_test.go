@@ -0,0 +1,150 @@
+package format_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+	}{
+		{"abc", 3},
+		{"café", 4},
+		{"日本語", 6},
+		{"🚀 ship", 7},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.width, format.DisplayWidth(tt.text))
+		})
+	}
+}
+
+func TestListOutputFormatter_FormatText_WideCharacters(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := format.NewListOutputFormatter(
+		&buf,
+		"text",
+		format.WithFields([]string{"mark", "window_title", "workspace"}),
+	)
+	require.NoError(t, err)
+
+	windows := []format.MarkedWindow{
+		{Mark: "a", WindowTitle: "日本語のタイトル", Workspace: "1"},
+		{Mark: "b", WindowTitle: "🚀 launch", Workspace: "2"},
+		{Mark: "c", WindowTitle: "café", Workspace: "3"},
+	}
+	require.NoError(t, formatter.Format(windows))
+
+	expected := "a | 日本語のタイトル | 1\n" +
+		"b | 🚀 launch        | 2\n" +
+		"c | café             | 3\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestListOutputFormatter_FormatText_MaxWidth(t *testing.T) {
+	windows := []format.MarkedWindow{
+		{Mark: "web", WindowID: 1, AppName: "Browser", WindowTitle: "A very long window title here"},
+		{Mark: "t", WindowID: 2, AppName: "Term", WindowTitle: "zsh"},
+	}
+
+	t.Run("truncates titles to fit", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(
+			&buf,
+			"text",
+			format.WithFields([]string{"mark", "app_name", "window_title"}),
+			format.WithMaxWidth(30),
+		)
+		require.NoError(t, err)
+		require.NoError(t, formatter.Format(windows))
+
+		expected := "web | Browser | A very long w…\n" +
+			"t   | Term    | zsh           \n"
+		assert.Equal(t, expected, buf.String())
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			assert.LessOrEqual(t, format.DisplayWidth(line), 30)
+		}
+	})
+
+	t.Run("keeps a minimum title width", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(
+			&buf,
+			"text",
+			format.WithFields([]string{"mark", "app_name", "window_title"}),
+			format.WithMaxWidth(5),
+		)
+		require.NoError(t, err)
+		require.NoError(t, formatter.Format(windows))
+
+		assert.Equal(
+			t,
+			"web | Browser | A very lo…\nt   | Term    | zsh       \n",
+			buf.String(),
+		)
+	})
+
+	t.Run("rows that fit are kept whole", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(
+			&buf,
+			"text",
+			format.WithFields([]string{"mark", "window_title"}),
+			format.WithMaxWidth(80),
+		)
+		require.NoError(t, err)
+		require.NoError(t, formatter.Format(windows))
+
+		assert.Contains(t, buf.String(), "A very long window title here")
+	})
+}
+
+func TestListOutputFormatter_FormatText_Header(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := format.NewListOutputFormatter(
+		&buf,
+		"text",
+		format.WithFields([]string{"mark", "window_id", "workspace"}),
+		format.WithHeader(true),
+	)
+	require.NoError(t, err)
+
+	windows := []format.MarkedWindow{{Mark: "web", WindowID: 1, Workspace: "2"}}
+	require.NoError(t, formatter.Format(windows))
+
+	expected := "MARK | WINDOW_ID | WORKSPACE\n" +
+		"web  | 1         | 2        \n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestListOutputFormatter_FormatText_Color(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := format.NewListOutputFormatter(
+		&buf,
+		"text",
+		format.WithFields([]string{"mark", "app_name", "workspace"}),
+		format.WithColor(true),
+	)
+	require.NoError(t, err)
+
+	windows := []format.MarkedWindow{
+		{Mark: "web", AppName: "Browser", Workspace: "2"},
+		{Mark: "t", AppName: "Term", Workspace: "10"},
+	}
+	require.NoError(t, formatter.Format(windows))
+
+	// Padding stays outside of the escape codes
+	expected := "\x1b[1m\x1b[33mweb\x1b[0m | Browser | \x1b[36m2\x1b[0m \n" +
+		"\x1b[1m\x1b[33mt\x1b[0m   | Term    | \x1b[36m10\x1b[0m\n"
+	assert.Equal(t, expected, buf.String())
+}
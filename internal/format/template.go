@@ -0,0 +1,153 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/mattn/go-runewidth"
+)
+
+// ErrMissingTemplate is returned when the template output format is used
+// without a template.
+var ErrMissingTemplate = errkind.New(
+	errkind.InvalidInput,
+	"the template output format requires a template",
+)
+
+// FormatterOption configures ListOutputFormatter and OutputEventFormatter.
+type FormatterOption func(*formatterOptions)
+
+type formatterOptions struct {
+	template string
+	fields   []string
+	table    tableOptions
+}
+
+// WithTemplate sets the Go text/template used by the template output format.
+func WithTemplate(text string) FormatterOption {
+	return func(opts *formatterOptions) {
+		opts.template = text
+	}
+}
+
+// newFormatterOptions applies the options and parses the template when
+// the output format is template.
+func newFormatterOptions(
+	outputFormat OutputFormat,
+	opts []FormatterOption,
+) (*template.Template, formatterOptions, error) {
+	options := formatterOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if outputFormat != OutputFormatTemplate {
+		return nil, options, nil
+	}
+
+	tmpl, err := parseTemplate(options.template)
+	return tmpl, options, err
+}
+
+// parseTemplate parses an output template with the TemplateFuncs helpers.
+func parseTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrMissingTemplate
+	}
+
+	tmpl, err := template.New("output").Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, errkind.Errorf(errkind.InvalidInput, "invalid template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// executeTemplate writes data with the template, followed by a new line
+// unless the template already ends with one.
+func executeTemplate(w io.Writer, tmpl *template.Template, data any) error {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	output := b.String()
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+
+	_, err := io.WriteString(w, output)
+	return err
+}
+
+// formatTemplateItems writes every item with the template.
+func formatTemplateItems[T any](w io.Writer, tmpl *template.Template, items []T) error {
+	for _, item := range items {
+		if err := executeTemplate(w, tmpl, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TemplateFuncs returns the helper functions available in output templates:
+//
+//   - truncate N S: S cut to N display columns, ending with "…" when cut
+//   - pad N S: S padded with spaces on the right to N display columns
+//   - padLeft N S: S padded with spaces on the left to N display columns
+//   - json V: V encoded as JSON, e.g. a quoted and escaped string
+//   - join SEP LIST: the items of LIST separated by SEP
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"truncate": templateTruncate,
+		"pad":      templatePad,
+		"padLeft":  templatePadLeft,
+		"json":     templateJSON,
+		"join":     templateJoin,
+	}
+}
+
+func templateTruncate(length int, s string) string {
+	if length <= 0 {
+		return ""
+	}
+
+	return runewidth.Truncate(s, length, tableEllipsis)
+}
+
+func templatePad(length int, s string) string {
+	return s + templatePadding(length, s)
+}
+
+func templatePadLeft(length int, s string) string {
+	return templatePadding(length, s) + s
+}
+
+func templatePadding(length int, s string) string {
+	missing := length - DisplayWidth(s)
+	if missing <= 0 {
+		return ""
+	}
+
+	return strings.Repeat(" ", missing)
+}
+
+func templateJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// templateJoin takes the separator first, so it works in pipelines like
+// '{{.Tags | join ","}}'.
+func templateJoin(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
@@ -0,0 +1,133 @@
+package format_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOutputFormatter_FormatTemplate(t *testing.T) {
+	windows := []format.MarkedWindow{
+		{Mark: "mark1", WindowID: 1, AppName: "app1", WindowTitle: "a very long title"},
+		{Mark: "mark2", WindowID: 22, AppName: "app2", Tags: []string{"a", "b"}},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"fields", "{{.Mark}} {{.AppName}}", "mark1 app1\nmark2 app2\n"},
+		{"truncate", "{{.WindowTitle | truncate 6}}", "a ver…\n\n"},
+		{
+			"pad",
+			"{{.Mark | pad 7}}|{{.WindowID | printf \"%d\" | padLeft 3}}",
+			"mark1  |  1\nmark2  | 22\n",
+		},
+		{"json", "{{json .Mark}}: {{json .Tags}}", "\"mark1\": null\n\"mark2\": [\"a\",\"b\"]\n"},
+		{"join", "{{.Tags | join \",\"}}", "\na,b\n"},
+		{"keeps the template new line", "{{.Mark}}\n", "mark1\nmark2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			formatter, err := format.NewListOutputFormatter(
+				&buf,
+				"template",
+				format.WithTemplate(tt.template),
+			)
+			require.NoError(t, err)
+
+			require.NoError(t, formatter.Format(windows))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestListOutputFormatter_FormatTemplate_ByWindow(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := format.NewListOutputFormatter(
+		&buf,
+		"template",
+		format.WithTemplate(`{{.WindowID}}: {{join " " .Marks}}`),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, formatter.FormatByWindow([]format.WindowMarks{
+		{Marks: []string{"a", "b"}, WindowID: 1},
+	}))
+	assert.Equal(t, "1: a b\n", buf.String())
+}
+
+func TestListOutputFormatter_FormatTemplate_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := format.NewListOutputFormatter(
+		&buf,
+		"template",
+		format.WithTemplate("{{.Mark}}"),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, formatter.FormatEmpty("No marks found"))
+	assert.Empty(t, buf.String())
+}
+
+func TestOutputEventFormatter_FormatTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := format.NewOutputEventFormatter(
+		&buf,
+		"template",
+		format.WithTemplate("{{.Action}} {{.WindowID}} {{json .Message}}"),
+	)
+	require.NoError(t, err)
+
+	err = formatter.Format(format.OutputEvent{
+		Action:   "focus",
+		WindowID: 1,
+		Message:  `say "hi"`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "focus 1 \"say \\\"hi\\\"\"\n", buf.String())
+}
+
+func TestFormatterTemplateErrors(t *testing.T) {
+	t.Run("template format requires a template", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := format.NewListOutputFormatter(&buf, "template")
+		require.ErrorIs(t, err, format.ErrMissingTemplate)
+
+		_, err = format.NewOutputEventFormatter(&buf, "template", format.WithTemplate("  "))
+		require.ErrorIs(t, err, format.ErrMissingTemplate)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := format.NewListOutputFormatter(&buf, "template", format.WithTemplate("{{.Mark"))
+		require.ErrorContains(t, err, "invalid template")
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(
+			&buf,
+			"template",
+			format.WithTemplate("{{.Nope}}"),
+		)
+		require.NoError(t, err)
+
+		err = formatter.Format([]format.MarkedWindow{{Mark: "mark1"}})
+		require.ErrorContains(t, err, "failed to execute template")
+	})
+
+	t.Run("template is ignored by other formats", func(t *testing.T) {
+		var buf bytes.Buffer
+		formatter, err := format.NewListOutputFormatter(&buf, "csv", format.WithTemplate("{{.Mark"))
+		require.NoError(t, err)
+		require.NoError(t, formatter.FormatEmpty(""))
+		assert.Contains(t, buf.String(), "mark,window_id")
+	})
+}
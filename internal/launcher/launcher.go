@@ -0,0 +1,94 @@
+package launcher
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+const (
+	// DefaultTimeout is how long to wait for the window of a launched application.
+	DefaultTimeout = 10 * time.Second
+	// DefaultPollInterval is how often windows are listed while waiting.
+	DefaultPollInterval = 200 * time.Millisecond
+)
+
+// ErrLaunchTimeout is returned when no new window shows up after launching.
+var ErrLaunchTimeout = errkind.New(errkind.WindowGone, "timed out waiting for the launched window")
+
+// Launcher starts an application and waits for its window.
+type Launcher struct {
+	aerospace aerospace.AerosSpaceMarkWindows
+	runner    Runner
+
+	// Timeout is how long to wait for the new window
+	Timeout time.Duration
+	// PollInterval is how often windows are listed while waiting
+	PollInterval time.Duration
+}
+
+// New creates a Launcher with the default timeout and poll interval.
+func New(aerospaceClient aerospace.AerosSpaceMarkWindows, runner Runner) *Launcher {
+	return &Launcher{
+		aerospace:    aerospaceClient,
+		runner:       runner,
+		Timeout:      DefaultTimeout,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// Launch runs the command and returns the first window that didn't exist
+// before. When appBundleID is set, only windows of that application match.
+//
+// Returns an error wrapping ErrLaunchTimeout if no window shows up in time.
+func (l *Launcher) Launch(command string, appBundleID string) (*windows.Window, error) {
+	log := logger.GetDefaultLogger()
+
+	client, err := l.aerospace.Client()
+	if err != nil {
+		return nil, err
+	}
+	windowsList, err := client.Windows().GetAllWindows()
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[int]bool, len(windowsList))
+	for _, window := range windowsList {
+		existing[window.WindowID] = true
+	}
+
+	log.LogInfo("Launching application", "command", command, "appBundleID", appBundleID)
+	if runErr := l.runner.Run(command); runErr != nil {
+		return nil, fmt.Errorf("failed to launch '%s': %w", command, runErr)
+	}
+
+	deadline := time.Now().Add(l.Timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(l.PollInterval)
+
+		windowsList, err = client.Windows().GetAllWindows()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range windowsList {
+			window := windowsList[i]
+			if existing[window.WindowID] {
+				continue
+			}
+			if appBundleID != "" && window.AppBundleID != appBundleID {
+				continue
+			}
+
+			log.LogDebug("Launched window found", "windowID", window.WindowID)
+			return &window, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: '%s' after %s", ErrLaunchTimeout, command, l.Timeout)
+}
@@ -0,0 +1,121 @@
+package launcher_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/launcher"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cristianoliveira/aerospace-ipc/pkg/aerospace/windows"
+)
+
+// fakeRunner records launch commands instead of running them.
+type fakeRunner struct {
+	commands []string
+	err      error
+}
+
+func (r *fakeRunner) Run(command string) error {
+	r.commands = append(r.commands, command)
+	return r.err
+}
+
+func TestLauncherLaunch(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	before := []windows.Window{
+		{WindowID: 1, AppName: "Brave", AppBundleID: "com.brave.Browser", Workspace: "1"},
+	}
+	other := windows.Window{
+		WindowID:    20,
+		AppName:     "Mail",
+		AppBundleID: "com.apple.mail",
+		Workspace:   "1",
+	}
+	launched := windows.Window{
+		WindowID:    30,
+		AppName:     "Alacritty",
+		AppBundleID: "io.alacritty",
+		Workspace:   "1",
+	}
+
+	newLauncher := func(
+		t *testing.T,
+		runner launcher.Runner,
+		windowsLists ...[]windows.Window,
+	) *launcher.Launcher {
+		t.Helper()
+
+		ctrl := gomock.NewController(t)
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		for _, windowsList := range windowsLists {
+			mocks.ExpectGetAllWindows(mockAeroSpaceConnection, windowsList).Times(1)
+		}
+
+		l := launcher.New(aerospaceClient, runner)
+		l.Timeout = 50 * time.Millisecond
+		l.PollInterval = time.Millisecond
+		return l
+	}
+
+	t.Run("returns the window that appears after launching", func(t *testing.T) {
+		runner := &fakeRunner{}
+		l := newLauncher(
+			t,
+			runner,
+			before,
+			before,
+			append([]windows.Window{other, launched}, before...),
+		)
+
+		window, err := l.Launch("open -a Alacritty", "io.alacritty")
+		require.NoError(t, err)
+		assert.Equal(t, launched, *window)
+		assert.Equal(t, []string{"open -a Alacritty"}, runner.commands)
+	})
+
+	t.Run("returns any new window without an app bundle ID", func(t *testing.T) {
+		l := newLauncher(t, &fakeRunner{}, before, append([]windows.Window{other}, before...))
+
+		window, err := l.Launch("open -a Mail", "")
+		require.NoError(t, err)
+		assert.Equal(t, other, *window)
+	})
+
+	t.Run("fails when the launch command fails", func(t *testing.T) {
+		runner := &fakeRunner{err: errors.New("exit status 127")}
+		l := newLauncher(t, runner, before)
+
+		window, err := l.Launch("not-an-app", "io.alacritty")
+		require.Error(t, err)
+		assert.Nil(t, window)
+		assert.ErrorIs(t, err, runner.err)
+		assert.NotErrorIs(t, err, launcher.ErrLaunchTimeout)
+	})
+
+	t.Run("times out when no window of the app appears", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAeroSpaceConnection, aerospaceClient := mocks.MockAerospaceConnection(ctrl)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, before).Times(1)
+		// Only windows of other apps show up while waiting
+		waiting := append([]windows.Window{other}, before...)
+		mocks.ExpectGetAllWindows(mockAeroSpaceConnection, waiting).MinTimes(1)
+
+		l := launcher.New(aerospaceClient, &fakeRunner{})
+		l.Timeout = 20 * time.Millisecond
+		l.PollInterval = time.Millisecond
+
+		window, err := l.Launch("open -a Alacritty", "io.alacritty")
+		require.Error(t, err)
+		assert.Nil(t, window)
+		assert.ErrorIs(t, err, launcher.ErrLaunchTimeout)
+		assert.Equal(t, errkind.ExitWindowGone, errkind.ExitCode(err))
+	})
+}
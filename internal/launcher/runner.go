@@ -0,0 +1,40 @@
+package launcher
+
+import (
+	"os/exec"
+)
+
+// Runner starts the launch command of a mark.
+//
+// Implementations must not wait for the application to exit.
+type Runner interface {
+	Run(command string) error
+}
+
+// ShellRunner runs launch commands with `/bin/sh -c`.
+type ShellRunner struct{}
+
+// Run starts the command in the background and returns once it started.
+func (r *ShellRunner) Run(command string) error {
+	//nolint:gosec // launch commands are configured by the user on purpose
+	process := exec.Command("/bin/sh", "-c", command)
+	if err := process.Start(); err != nil {
+		return err
+	}
+
+	// The application outlives this process, don't wait for it
+	return process.Process.Release()
+}
+
+//nolint:gochecknoglobals // default runner shared by the commands
+var defaultRunner Runner = &ShellRunner{}
+
+// SetDefaultRunner sets the runner used to start launch commands.
+func SetDefaultRunner(runner Runner) {
+	defaultRunner = runner
+}
+
+// GetDefaultRunner returns the runner set with SetDefaultRunner.
+func GetDefaultRunner() Runner {
+	return defaultRunner
+}
@@ -12,6 +12,10 @@ import (
 //nolint:gochecknoglobals // defaultLogger is a package-level singleton
 var defaultLogger Logger
 
+// LevelDisabled is the level of the configuration of EmptyLogger, when
+// logging is off.
+const LevelDisabled = "DISABLED"
+
 type LogConfig struct {
 	// Path to the log file
 	Path string `json:"path"`
@@ -107,7 +111,7 @@ func (l *EmptyLogger) GetConfig() LogConfig {
 	// No-op
 	return LogConfig{
 		Path:  "/tmp/aerospace-marks.log",
-		Level: "DISABLED",
+		Level: LevelDisabled,
 	}
 }
 func (l *EmptyLogger) AsJSON(data any) string {
@@ -8,6 +8,7 @@ import (
 	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
 	aerospacecli_mock "github.com/cristianoliveira/aerospace-marks/internal/mocks/aerospacecli"
 	storage_mock "github.com/cristianoliveira/aerospace-marks/internal/mocks/storage"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
 	"go.uber.org/mock/gomock"
 
@@ -28,6 +29,16 @@ func MockStorageDBClient(ctrl *gomock.Controller) (
 	return storageDBClient, newStorage
 }
 
+// ExpectTransaction mocks `WithTransaction` running the function on the
+// mocked storage itself, so the calls of the function are expected on it.
+func ExpectTransaction(markStorage *storage_mock.MockMarkStorage) *gomock.Call {
+	return markStorage.EXPECT().
+		WithTransaction(gomock.Any()).
+		DoAndReturn(func(fn func(storage.MarkStorage) error) error {
+			return fn(markStorage)
+		})
+}
+
 func MockAerospaceConnection(ctrl *gomock.Controller) (
 	*aerospacecli_mock.MockAeroSpaceConnection,
 	aerospace.AerosSpaceMarkWindows,
@@ -47,6 +58,68 @@ func MockAerospaceConnection(ctrl *gomock.Controller) (
 	return mockAeroSpaceConnection, aerospaceClient
 }
 
+// ExpectGetAllWindows mocks the response of `Windows().GetAllWindows()`.
+func ExpectGetAllWindows(
+	conn *aerospacecli_mock.MockAeroSpaceConnection,
+	windowsList []aerospaceipc.Window,
+) *gomock.Call {
+	return conn.EXPECT().
+		SendCommand("list-windows", allWindowsArgs).
+		Return(successResponse(windowsList), nil)
+}
+
+// ExpectGetFocusedWindow mocks the response of `Windows().GetFocusedWindow()`.
+func ExpectGetFocusedWindow(
+	conn *aerospacecli_mock.MockAeroSpaceConnection,
+	window aerospaceipc.Window,
+) *gomock.Call {
+	return conn.EXPECT().
+		SendCommand("list-windows", focusedWindowArgs).
+		Return(successResponse([]aerospaceipc.Window{window}), nil)
+}
+
+// ExpectGetFocusedWorkspace mocks the response of `Workspaces().GetFocusedWorkspace()`.
+func ExpectGetFocusedWorkspace(
+	conn *aerospacecli_mock.MockAeroSpaceConnection,
+	workspace string,
+) *gomock.Call {
+	return conn.EXPECT().
+		SendCommand("list-workspaces", gomock.Any()).
+		Return(successResponse([]aerospaceipc.Workspace{{Workspace: workspace}}), nil)
+}
+
+// ExpectCommand mocks a successful AeroSpace command with an empty output.
+func ExpectCommand(
+	conn *aerospacecli_mock.MockAeroSpaceConnection,
+	command string,
+	args any,
+) *gomock.Call {
+	return conn.EXPECT().
+		SendCommand(command, args).
+		Return(&aerospacecli.Response{ServerVersion: "1.0"}, nil)
+}
+
+//nolint:gochecknoglobals // arguments sent by aerospace-ipc for listing windows
+var (
+	windowFormatArgs  = "%{window-id} %{window-title} %{app-name} %{app-bundle-id} %{workspace} %{window-layout} %{window-parent-container-layout}"
+	allWindowsArgs    = []string{"--all", "--json", "--format", windowFormatArgs}
+	focusedWindowArgs = []string{"--focused", "--json", "--format", windowFormatArgs}
+)
+
+func successResponse(data any) *aerospacecli.Response {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal mocked response: %w", err))
+	}
+
+	return &aerospacecli.Response{
+		ServerVersion: "1.0",
+		StdOut:        string(jsonData),
+		StdErr:        "",
+		ExitCode:      0,
+	}
+}
+
 func LoadMarksFixture(jsonFilePath string) ([]queries.Mark, error) {
 	file, err := os.ReadFile(jsonFilePath)
 	if err != nil {
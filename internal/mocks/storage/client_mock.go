@@ -137,6 +137,21 @@ func (mr *MockStorageDBClientMockRecorder) GetStorageConfig() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorageConfig", reflect.TypeOf((*MockStorageDBClient)(nil).GetStorageConfig))
 }
 
+// GetVersion mocks base method.
+func (m *MockStorageDBClient) GetVersion() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockStorageDBClientMockRecorder) GetVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockStorageDBClient)(nil).GetVersion))
+}
+
 // MockDatabaseConnector is a mock of DatabaseConnector interface.
 type MockDatabaseConnector struct {
 	ctrl     *gomock.Controller
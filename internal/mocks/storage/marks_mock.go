@@ -42,17 +42,31 @@ func (m *MockMarkStorage) EXPECT() *MockMarkStorageMockRecorder {
 }
 
 // AddMark mocks base method.
-func (m *MockMarkStorage) AddMark(id int, mark string) error {
+func (m *MockMarkStorage) AddMark(id int, mark string, metadata storage.WindowMetadata) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddMark", id, mark)
+	ret := m.ctrl.Call(m, "AddMark", id, mark, metadata)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddMark indicates an expected call of AddMark.
-func (mr *MockMarkStorageMockRecorder) AddMark(id, mark any) *gomock.Call {
+func (mr *MockMarkStorageMockRecorder) AddMark(id, mark, metadata any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMark", reflect.TypeOf((*MockMarkStorage)(nil).AddMark), id, mark)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMark", reflect.TypeOf((*MockMarkStorage)(nil).AddMark), id, mark, metadata)
+}
+
+// AddTag mocks base method.
+func (m *MockMarkStorage) AddTag(windowID int, tag string, appBundleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTag", windowID, tag, appBundleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTag indicates an expected call of AddTag.
+func (mr *MockMarkStorageMockRecorder) AddTag(windowID, tag, appBundleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTag", reflect.TypeOf((*MockMarkStorage)(nil).AddTag), windowID, tag, appBundleID)
 }
 
 // Client mocks base method.
@@ -114,18 +128,137 @@ func (mr *MockMarkStorageMockRecorder) DeleteByMark(mark any) *gomock.Call {
 }
 
 // DeleteByWindow mocks base method.
-func (m *MockMarkStorage) DeleteByWindow(windowId int) (int64, error) {
+func (m *MockMarkStorage) DeleteByWindow(windowID int) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByWindow", windowId)
+	ret := m.ctrl.Call(m, "DeleteByWindow", windowID)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteByWindow indicates an expected call of DeleteByWindow.
-func (mr *MockMarkStorageMockRecorder) DeleteByWindow(windowId any) *gomock.Call {
+func (mr *MockMarkStorageMockRecorder) DeleteByWindow(windowID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByWindow", reflect.TypeOf((*MockMarkStorage)(nil).DeleteByWindow), windowID)
+}
+
+// DeleteMarkOrigin mocks base method.
+func (m *MockMarkStorage) DeleteMarkOrigin(mark string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMarkOrigin", mark)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMarkOrigin indicates an expected call of DeleteMarkOrigin.
+func (mr *MockMarkStorageMockRecorder) DeleteMarkOrigin(mark any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMarkOrigin", reflect.TypeOf((*MockMarkStorage)(nil).DeleteMarkOrigin), mark)
+}
+
+// DeleteTag mocks base method.
+func (m *MockMarkStorage) DeleteTag(tag string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", tag)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockMarkStorageMockRecorder) DeleteTag(tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockMarkStorage)(nil).DeleteTag), tag)
+}
+
+// DeleteWindowTag mocks base method.
+func (m *MockMarkStorage) DeleteWindowTag(windowID int, tag string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWindowTag", windowID, tag)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWindowTag indicates an expected call of DeleteWindowTag.
+func (mr *MockMarkStorageMockRecorder) DeleteWindowTag(windowID, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWindowTag", reflect.TypeOf((*MockMarkStorage)(nil).DeleteWindowTag), windowID, tag)
+}
+
+// GetFocusCycle mocks base method.
+func (m *MockMarkStorage) GetFocusCycle() (*storage.FocusCycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFocusCycle")
+	ret0, _ := ret[0].(*storage.FocusCycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFocusCycle indicates an expected call of GetFocusCycle.
+func (mr *MockMarkStorageMockRecorder) GetFocusCycle() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFocusCycle", reflect.TypeOf((*MockMarkStorage)(nil).GetFocusCycle))
+}
+
+// GetFocusRecency mocks base method.
+func (m *MockMarkStorage) GetFocusRecency() (map[int]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFocusRecency")
+	ret0, _ := ret[0].(map[int]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFocusRecency indicates an expected call of GetFocusRecency.
+func (mr *MockMarkStorageMockRecorder) GetFocusRecency() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByWindow", reflect.TypeOf((*MockMarkStorage)(nil).DeleteByWindow), windowId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFocusRecency", reflect.TypeOf((*MockMarkStorage)(nil).GetFocusRecency))
+}
+
+// GetHistory mocks base method.
+func (m *MockMarkStorage) GetHistory(limit int) ([]storage.JournalChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", limit)
+	ret0, _ := ret[0].([]storage.JournalChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockMarkStorageMockRecorder) GetHistory(limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockMarkStorage)(nil).GetHistory), limit)
+}
+
+// GetMarkLauncher mocks base method.
+func (m *MockMarkStorage) GetMarkLauncher(mark string) (*queries.MarkLauncher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMarkLauncher", mark)
+	ret0, _ := ret[0].(*queries.MarkLauncher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMarkLauncher indicates an expected call of GetMarkLauncher.
+func (mr *MockMarkStorageMockRecorder) GetMarkLauncher(mark any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarkLauncher", reflect.TypeOf((*MockMarkStorage)(nil).GetMarkLauncher), mark)
+}
+
+// GetMarkOrigin mocks base method.
+func (m *MockMarkStorage) GetMarkOrigin(mark string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMarkOrigin", mark)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMarkOrigin indicates an expected call of GetMarkOrigin.
+func (mr *MockMarkStorageMockRecorder) GetMarkOrigin(mark any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarkOrigin", reflect.TypeOf((*MockMarkStorage)(nil).GetMarkOrigin), mark)
 }
 
 // GetMarks mocks base method.
@@ -158,6 +291,36 @@ func (mr *MockMarkStorageMockRecorder) GetMarksByWindowID(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarksByWindowID", reflect.TypeOf((*MockMarkStorage)(nil).GetMarksByWindowID), id)
 }
 
+// GetPreviousFocus mocks base method.
+func (m *MockMarkStorage) GetPreviousFocus(currentWindowID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreviousFocus", currentWindowID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreviousFocus indicates an expected call of GetPreviousFocus.
+func (mr *MockMarkStorageMockRecorder) GetPreviousFocus(currentWindowID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreviousFocus", reflect.TypeOf((*MockMarkStorage)(nil).GetPreviousFocus), currentWindowID)
+}
+
+// GetTags mocks base method.
+func (m *MockMarkStorage) GetTags() ([]queries.WindowTag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags")
+	ret0, _ := ret[0].([]queries.WindowTag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockMarkStorageMockRecorder) GetTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockMarkStorage)(nil).GetTags))
+}
+
 // GetWindowByMark mocks base method.
 func (m *MockMarkStorage) GetWindowByMark(mark string) (*queries.Mark, error) {
 	m.ctrl.T.Helper()
@@ -188,31 +351,188 @@ func (mr *MockMarkStorageMockRecorder) GetWindowIDByMark(mark any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWindowIDByMark", reflect.TypeOf((*MockMarkStorage)(nil).GetWindowIDByMark), mark)
 }
 
+// GetWindowTagsByTag mocks base method.
+func (m *MockMarkStorage) GetWindowTagsByTag(tag string) ([]queries.WindowTag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWindowTagsByTag", tag)
+	ret0, _ := ret[0].([]queries.WindowTag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWindowTagsByTag indicates an expected call of GetWindowTagsByTag.
+func (mr *MockMarkStorageMockRecorder) GetWindowTagsByTag(tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWindowTagsByTag", reflect.TypeOf((*MockMarkStorage)(nil).GetWindowTagsByTag), tag)
+}
+
+// MoveWindowTags mocks base method.
+func (m *MockMarkStorage) MoveWindowTags(fromWindowID int, toWindowID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveWindowTags", fromWindowID, toWindowID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveWindowTags indicates an expected call of MoveWindowTags.
+func (mr *MockMarkStorageMockRecorder) MoveWindowTags(fromWindowID, toWindowID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveWindowTags", reflect.TypeOf((*MockMarkStorage)(nil).MoveWindowTags), fromWindowID, toWindowID)
+}
+
+// PushFocusHistory mocks base method.
+func (m *MockMarkStorage) PushFocusHistory(windowID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushFocusHistory", windowID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushFocusHistory indicates an expected call of PushFocusHistory.
+func (mr *MockMarkStorageMockRecorder) PushFocusHistory(windowID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushFocusHistory", reflect.TypeOf((*MockMarkStorage)(nil).PushFocusHistory), windowID)
+}
+
+// Redo mocks base method.
+func (m *MockMarkStorage) Redo() (*storage.JournalChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redo")
+	ret0, _ := ret[0].(*storage.JournalChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redo indicates an expected call of Redo.
+func (mr *MockMarkStorageMockRecorder) Redo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redo", reflect.TypeOf((*MockMarkStorage)(nil).Redo))
+}
+
+// RefreshMarkWindow mocks base method.
+func (m *MockMarkStorage) RefreshMarkWindow(mark string, id int, metadata storage.WindowMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshMarkWindow", mark, id, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshMarkWindow indicates an expected call of RefreshMarkWindow.
+func (mr *MockMarkStorageMockRecorder) RefreshMarkWindow(mark, id, metadata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshMarkWindow", reflect.TypeOf((*MockMarkStorage)(nil).RefreshMarkWindow), mark, id, metadata)
+}
+
 // ReplaceAllMarks mocks base method.
-func (m *MockMarkStorage) ReplaceAllMarks(id int, mark string) (int64, error) {
+func (m *MockMarkStorage) ReplaceAllMarks(id int, mark string, metadata storage.WindowMetadata) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReplaceAllMarks", id, mark)
+	ret := m.ctrl.Call(m, "ReplaceAllMarks", id, mark, metadata)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ReplaceAllMarks indicates an expected call of ReplaceAllMarks.
-func (mr *MockMarkStorageMockRecorder) ReplaceAllMarks(id, mark any) *gomock.Call {
+func (mr *MockMarkStorageMockRecorder) ReplaceAllMarks(id, mark, metadata any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAllMarks", reflect.TypeOf((*MockMarkStorage)(nil).ReplaceAllMarks), id, mark)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAllMarks", reflect.TypeOf((*MockMarkStorage)(nil).ReplaceAllMarks), id, mark, metadata)
+}
+
+// SetFocusCycle mocks base method.
+func (m *MockMarkStorage) SetFocusCycle(cycle storage.FocusCycle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFocusCycle", cycle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFocusCycle indicates an expected call of SetFocusCycle.
+func (mr *MockMarkStorageMockRecorder) SetFocusCycle(cycle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFocusCycle", reflect.TypeOf((*MockMarkStorage)(nil).SetFocusCycle), cycle)
+}
+
+// SetMarkLauncher mocks base method.
+func (m *MockMarkStorage) SetMarkLauncher(mark string, command string, appBundleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMarkLauncher", mark, command, appBundleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMarkLauncher indicates an expected call of SetMarkLauncher.
+func (mr *MockMarkStorageMockRecorder) SetMarkLauncher(mark, command, appBundleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMarkLauncher", reflect.TypeOf((*MockMarkStorage)(nil).SetMarkLauncher), mark, command, appBundleID)
+}
+
+// SetMarkOrigin mocks base method.
+func (m *MockMarkStorage) SetMarkOrigin(mark string, workspace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMarkOrigin", mark, workspace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMarkOrigin indicates an expected call of SetMarkOrigin.
+func (mr *MockMarkStorageMockRecorder) SetMarkOrigin(mark, workspace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMarkOrigin", reflect.TypeOf((*MockMarkStorage)(nil).SetMarkOrigin), mark, workspace)
 }
 
 // ToggleMark mocks base method.
-func (m *MockMarkStorage) ToggleMark(id int, mark string) error {
+func (m *MockMarkStorage) ToggleMark(id int, mark string, metadata storage.WindowMetadata) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ToggleMark", id, mark)
+	ret := m.ctrl.Call(m, "ToggleMark", id, mark, metadata)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ToggleMark indicates an expected call of ToggleMark.
-func (mr *MockMarkStorageMockRecorder) ToggleMark(id, mark any) *gomock.Call {
+func (mr *MockMarkStorageMockRecorder) ToggleMark(id, mark, metadata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleMark", reflect.TypeOf((*MockMarkStorage)(nil).ToggleMark), id, mark, metadata)
+}
+
+// Undo mocks base method.
+func (m *MockMarkStorage) Undo(n int) ([]storage.JournalChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undo", n)
+	ret0, _ := ret[0].([]storage.JournalChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undo indicates an expected call of Undo.
+func (mr *MockMarkStorageMockRecorder) Undo(n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undo", reflect.TypeOf((*MockMarkStorage)(nil).Undo), n)
+}
+
+// UpdateMarkWindow mocks base method.
+func (m *MockMarkStorage) UpdateMarkWindow(mark string, id int, metadata storage.WindowMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMarkWindow", mark, id, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMarkWindow indicates an expected call of UpdateMarkWindow.
+func (mr *MockMarkStorageMockRecorder) UpdateMarkWindow(mark, id, metadata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMarkWindow", reflect.TypeOf((*MockMarkStorage)(nil).UpdateMarkWindow), mark, id, metadata)
+}
+
+// WithTransaction mocks base method.
+func (m *MockMarkStorage) WithTransaction(fn func(storage.MarkStorage) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTransaction", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTransaction indicates an expected call of WithTransaction.
+func (mr *MockMarkStorageMockRecorder) WithTransaction(fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleMark", reflect.TypeOf((*MockMarkStorage)(nil).ToggleMark), id, mark)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransaction", reflect.TypeOf((*MockMarkStorage)(nil).WithTransaction), fn)
 }
@@ -1,24 +1,45 @@
 package stdout
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/format"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 )
 
 //nolint:gochecknoglobals // ShouldExit is a test configuration flag
 var ShouldExit = true
 
-// ErrorAndExit is a function that prints an error message to stderr and exits the program with a non-zero status code.
+// errorOutput is how errors of the running command are printed.
+//
+//nolint:gochecknoglobals // set once per command run by SetErrorOutput
+var errorOutput struct {
+	command      string
+	outputFormat string
+}
+
+// SetErrorOutput sets the command whose errors are printed and its output
+// format. With json or ndjson, errors are printed on stderr as a JSON
+// format.ErrorEvent instead of as a plain message.
+func SetErrorOutput(command string, outputFormat string) {
+	errorOutput.command = command
+	errorOutput.outputFormat = outputFormat
+}
+
+// ErrorAndExit is a function that prints an error message to stderr and exits the program
+// with the exit code of the error kind, see errkind.ExitCode.
 func ErrorAndExit(err error) {
 	if err != nil {
 		logger := logger.GetDefaultLogger()
 		logger.LogError("ERROR:", "msg", err)
 		errorMessage := fmt.Errorf("error: %w", err)
-		fmt.Fprintln(os.Stderr, err.Error())
+		printError(err)
 		if ShouldExit {
-			os.Exit(1)
+			os.Exit(errkind.ExitCode(err))
 		}
 
 		fmt.Fprintln(os.Stdout, errorMessage)
@@ -26,10 +47,27 @@ func ErrorAndExit(err error) {
 }
 
 func ErrorAndExitf(format string, a ...any) {
+	err := fmt.Errorf(format, a...)
 	logger := logger.GetDefaultLogger()
-	logger.LogError("ERROR", "msg", fmt.Errorf(format, a...))
-	fmt.Fprintln(os.Stderr, fmt.Errorf(format, a...))
+	logger.LogError("ERROR", "msg", err)
+	printError(err)
 	if ShouldExit {
-		os.Exit(1)
+		os.Exit(errkind.ExitCode(err))
 	}
 }
+
+// printError prints err on stderr, as JSON when the output is json or ndjson.
+func printError(err error) {
+	outputFormat := format.OutputFormat(
+		strings.ToLower(strings.TrimSpace(errorOutput.outputFormat)),
+	)
+	if outputFormat == format.OutputFormatJSON || outputFormat == format.OutputFormatNDJSON {
+		data, marshalErr := json.Marshal(format.NewErrorEvent(errorOutput.command, err))
+		if marshalErr == nil {
+			fmt.Fprintln(os.Stderr, string(data))
+			return
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, err.Error())
+}
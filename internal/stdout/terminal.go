@@ -0,0 +1,72 @@
+package stdout
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"golang.org/x/term"
+)
+
+// IsTerminal reports whether stdout is a terminal rather than a pipe or a file.
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// ForceInteractive makes IsInteractive report true, for tests of prompts.
+//
+//nolint:gochecknoglobals // ForceInteractive is a test configuration flag
+var ForceInteractive = false
+
+// IsInteractive reports whether stdin, the input of a command, is a terminal
+// a user can answer prompts on.
+func IsInteractive(stdin io.Reader) bool {
+	if ForceInteractive {
+		return true
+	}
+
+	file, ok := stdin.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// TerminalWidth returns the width of the terminal on stdout, from the COLUMNS
+// environment variable when set. It returns 0 when stdout isn't a terminal or
+// its width is unknown.
+func TerminalWidth() int {
+	if !IsTerminal() {
+		return 0
+	}
+
+	columns, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS")))
+	if err == nil && columns > 0 {
+		return columns
+	}
+
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// ColorEnabled resolves a --color mode: "always", "never" or "auto", which
+// enables colors when stdout is a terminal and NO_COLOR isn't set.
+func ColorEnabled(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto", "":
+		return IsTerminal() && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb", nil
+	default:
+		return false, errkind.Errorf(
+			errkind.InvalidInput,
+			"invalid color '%s', expected one of: auto, always, never",
+			mode,
+		)
+	}
+}
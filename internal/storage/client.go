@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/cristianoliveira/aerospace-marks/internal/constants"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
@@ -44,6 +45,8 @@ type StorageDBClient interface {
 	GetStorageConfig() StorageConfig
 	// GetDB returns the underlying database connection for SQLC
 	GetDB() *sql.DB
+	// GetVersion returns the version of the last migration applied
+	GetVersion() (int64, error)
 }
 
 type StorageClient struct {
@@ -104,6 +107,24 @@ func (c *StorageClient) GetVersion() (int64, error) {
 	return version, nil
 }
 
+// LatestMigrationVersion returns the version of the last migration embedded
+// in this build, the version an up to date database is at.
+func LatestMigrationVersion() (int64, error) {
+	goose.SetBaseFS(embedMigrations)
+
+	migrations, err := goose.CollectMigrations("db/migrations", 0, goose.MaxVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	last, err := migrations.Last()
+	if err != nil {
+		return 0, err
+	}
+
+	return last.Version, nil
+}
+
 // GetStorageConfig returns the storage configuration.
 func (c *StorageClient) GetStorageConfig() StorageConfig {
 	log := logger.GetDefaultLogger()
@@ -126,6 +147,22 @@ type DatabaseConnector interface {
 type MarksDatabaseConnector struct{}
 
 func (c *MarksDatabaseConnector) Connect() (StorageDBClient, error) {
+	client, err := openDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	// Run migrations to ensure database is up to date
+	if migrateErr := client.migrate(); migrateErr != nil {
+		logger.GetDefaultLogger().LogError("failed to run migrations", migrateErr)
+		return nil, migrateErr
+	}
+
+	return client, nil
+}
+
+// openDatabase opens the database without migrating it.
+func openDatabase() (*StorageClient, error) {
 	log := logger.GetDefaultLogger()
 	dbConfig := GetDatabaseConfig()
 
@@ -138,23 +175,57 @@ func (c *MarksDatabaseConnector) Connect() (StorageDBClient, error) {
 	dbPath := fmt.Sprintf("%s/storage.db", dbConfig.DBPath)
 
 	log.LogInfo("connecting to database", dbPath)
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", dbPath+fmt.Sprintf(connectionOptions, BusyTimeout.Milliseconds()))
 	if err != nil {
 		return nil, err
 	}
 
-	client := &StorageClient{
+	return &StorageClient{
 		dbConfig: dbConfig,
 		db:       db,
+	}, nil
+}
+
+// BusyTimeout is how long a connection waits for another process to
+// release the database, see connectionOptions.
+const BusyTimeout = 5 * time.Second
+
+// connectionOptions configures the SQLite connections for many processes
+// at once, e.g. several hotkeys pressed in a row:
+//   - WAL lets readers work while another process writes
+//   - a busy timeout of BusyTimeout, in milliseconds, waits for another
+//     process to release the database instead of failing right away with
+//     "database is locked"
+//   - immediate transactions take the write lock when they begin, so two
+//     transactions can't both read and then fail to upgrade their lock
+const connectionOptions = "?_journal_mode=WAL&_busy_timeout=%d&_txlock=immediate"
+
+// migrate runs the migrations unless the database is already at the latest
+// version, which is the case of nearly every start.
+func (c *StorageClient) migrate() error {
+	if c.isMigrated() {
+		return nil
 	}
 
-	// Run migrations to ensure database is up to date
-	if migrateErr := client.runMigrations(); migrateErr != nil {
-		log.LogError("failed to run migrations", migrateErr)
-		return nil, migrateErr
+	err := c.runMigrations()
+	if err != nil && c.isMigrated() {
+		// Another process migrated the database at the same time
+		return nil
 	}
 
-	return client, nil
+	return err
+}
+
+// isMigrated reports whether the database is at the version of the last
+// migration embedded in this build.
+func (c *StorageClient) isMigrated() bool {
+	latest, err := LatestMigrationVersion()
+	if err != nil {
+		return false
+	}
+
+	version, err := c.GetVersion()
+	return err == nil && version == latest
 }
 
 func GetDatabaseConfig() StorageConfig {
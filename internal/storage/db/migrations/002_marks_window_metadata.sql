@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE marks ADD COLUMN app_bundle_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE marks ADD COLUMN app_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE marks ADD COLUMN window_title TEXT NOT NULL DEFAULT '';
+ALTER TABLE marks ADD COLUMN workspace TEXT NOT NULL DEFAULT '';
+ALTER TABLE marks ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE marks ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;
+UPDATE marks SET created_at = strftime('%s', 'now'), updated_at = strftime('%s', 'now');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE marks DROP COLUMN updated_at;
+ALTER TABLE marks DROP COLUMN created_at;
+ALTER TABLE marks DROP COLUMN workspace;
+ALTER TABLE marks DROP COLUMN window_title;
+ALTER TABLE marks DROP COLUMN app_name;
+ALTER TABLE marks DROP COLUMN app_bundle_id;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS mark_origins (
+    mark TEXT NOT NULL PRIMARY KEY,
+    workspace TEXT NOT NULL,
+    updated_at INTEGER NOT NULL DEFAULT 0
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mark_origins;
+-- +goose StatementEnd
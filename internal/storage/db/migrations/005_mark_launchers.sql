@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS mark_launchers (
+    mark TEXT NOT NULL PRIMARY KEY,
+    command TEXT NOT NULL,
+    app_bundle_id TEXT NOT NULL DEFAULT '',
+    updated_at INTEGER NOT NULL DEFAULT 0
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mark_launchers;
+-- +goose StatementEnd
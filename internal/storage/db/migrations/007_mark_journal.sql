@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS mark_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    operation TEXT NOT NULL,
+    -- target_id is the change reverted by an undo or applied again by a redo
+    target_id INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL DEFAULT 0
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS mark_journal (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    change_id INTEGER NOT NULL REFERENCES mark_changes (id),
+    mark TEXT NOT NULL,
+    -- previous_window_id is 0 when the mark was added by the change
+    previous_window_id INTEGER NOT NULL DEFAULT 0,
+    previous_app_bundle_id TEXT NOT NULL DEFAULT '',
+    previous_app_name TEXT NOT NULL DEFAULT '',
+    previous_window_title TEXT NOT NULL DEFAULT '',
+    previous_workspace TEXT NOT NULL DEFAULT '',
+    -- window_id is 0 when the mark was removed by the change
+    window_id INTEGER NOT NULL DEFAULT 0,
+    app_bundle_id TEXT NOT NULL DEFAULT '',
+    app_name TEXT NOT NULL DEFAULT '',
+    window_title TEXT NOT NULL DEFAULT '',
+    workspace TEXT NOT NULL DEFAULT ''
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS mark_journal_change_id ON mark_journal (change_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mark_journal;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mark_changes;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS focus_cycle (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    pattern TEXT NOT NULL,
+    window_ids TEXT NOT NULL,
+    window_id INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL DEFAULT 0
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS focus_cycle;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE window_tags ADD COLUMN app_bundle_id TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE window_tags DROP COLUMN app_bundle_id;
+-- +goose StatementEnd
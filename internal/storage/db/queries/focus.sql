@@ -0,0 +1,33 @@
+-- name: AddFocusHistory :exec
+INSERT INTO focus_history (window_id, focused_at)
+VALUES (?, strftime('%s', 'now'));
+
+-- name: GetFocusRecency :many
+SELECT window_id, CAST(MAX(id) AS INTEGER) AS last_id
+FROM focus_history
+GROUP BY window_id;
+
+-- name: GetPreviousFocus :one
+SELECT id, window_id, focused_at
+FROM focus_history
+WHERE window_id != ?
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: TrimFocusHistory :exec
+DELETE FROM focus_history
+WHERE id <= (SELECT MAX(id) FROM focus_history) - ?;
+
+-- name: SetFocusCycle :exec
+INSERT INTO focus_cycle (id, pattern, window_ids, window_id, updated_at)
+VALUES (1, ?, ?, ?, strftime('%s', 'now'))
+ON CONFLICT (id) DO UPDATE SET
+    pattern = excluded.pattern,
+    window_ids = excluded.window_ids,
+    window_id = excluded.window_id,
+    updated_at = excluded.updated_at;
+
+-- name: GetFocusCycle :one
+SELECT pattern, window_ids, window_id, updated_at
+FROM focus_cycle
+WHERE id = 1;
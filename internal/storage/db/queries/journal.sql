@@ -0,0 +1,42 @@
+-- name: AddMarkChange :execlastid
+INSERT INTO mark_changes (operation, target_id, created_at)
+VALUES (?, ?, strftime('%s', 'now'));
+
+-- name: AddMarkJournalEntry :exec
+INSERT INTO mark_journal (
+    change_id, mark,
+    previous_window_id, previous_app_bundle_id, previous_app_name,
+    previous_window_title, previous_workspace,
+    window_id, app_bundle_id, app_name, window_title, workspace
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetMarkChanges :many
+SELECT id, operation, target_id, created_at
+FROM mark_changes
+ORDER BY id;
+
+-- name: GetMarkChange :one
+SELECT id, operation, target_id, created_at
+FROM mark_changes
+WHERE id = ?;
+
+-- name: GetMarkJournalEntries :many
+SELECT id, change_id, mark,
+    previous_window_id, previous_app_bundle_id, previous_app_name,
+    previous_window_title, previous_workspace,
+    window_id, app_bundle_id, app_name, window_title, workspace
+FROM mark_journal
+WHERE change_id = ?
+ORDER BY id;
+
+-- name: TrimMarkChanges :exec
+DELETE FROM mark_changes
+WHERE id <= (SELECT MAX(id) FROM mark_changes) - sqlc.arg(keep)
+    AND id NOT IN (
+        SELECT target_id FROM mark_changes
+        WHERE id > (SELECT MAX(id) FROM mark_changes) - sqlc.arg(keep)
+    );
+
+-- name: TrimMarkJournal :exec
+DELETE FROM mark_journal
+WHERE change_id NOT IN (SELECT id FROM mark_changes);
@@ -0,0 +1,15 @@
+-- name: SetMarkLauncher :exec
+INSERT INTO mark_launchers (mark, command, app_bundle_id, updated_at)
+VALUES (?, ?, ?, strftime('%s', 'now'))
+ON CONFLICT (mark) DO UPDATE SET
+    command = excluded.command,
+    app_bundle_id = excluded.app_bundle_id,
+    updated_at = excluded.updated_at;
+
+-- name: GetMarkLauncher :one
+SELECT mark, command, app_bundle_id, updated_at
+FROM mark_launchers
+WHERE mark = ?;
+
+-- name: DeleteUnmarkedLaunchers :exec
+DELETE FROM mark_launchers WHERE mark NOT IN (SELECT mark FROM marks);
@@ -1,16 +1,30 @@
 -- name: AddMark :exec
-INSERT INTO marks (window_id, mark) VALUES (?, ?);
+INSERT INTO marks (
+    window_id, mark, app_bundle_id, app_name, window_title, workspace, created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, strftime('%s', 'now'), strftime('%s', 'now'));
 
 -- name: GetAllMarks :many
-SELECT window_id, mark FROM marks;
+SELECT window_id, mark, app_bundle_id, app_name, window_title, workspace, created_at, updated_at
+FROM marks;
 
 -- name: GetMarksByWindowID :many
-SELECT window_id, mark
+SELECT window_id, mark, app_bundle_id, app_name, window_title, workspace, created_at, updated_at
 FROM marks
 WHERE window_id = ?;
 
 -- name: GetWindowByMark :one
-SELECT window_id, mark FROM marks WHERE mark = ?;
+SELECT window_id, mark, app_bundle_id, app_name, window_title, workspace, created_at, updated_at
+FROM marks WHERE mark = ?;
+
+-- name: UpdateMarkWindow :execresult
+UPDATE marks
+SET window_id = ?,
+    app_bundle_id = ?,
+    app_name = ?,
+    window_title = ?,
+    workspace = ?,
+    updated_at = strftime('%s', 'now')
+WHERE mark = ?;
 
 -- name: DeleteAllMarks :execresult
 DELETE FROM marks;
@@ -22,4 +36,4 @@ DELETE FROM marks WHERE mark = ?;
 DELETE FROM marks WHERE window_id = ?;
 
 -- name: DeleteMarksByWindowIDOrMark :execresult
-DELETE FROM marks WHERE window_id = ? OR mark = ?;
\ No newline at end of file
+DELETE FROM marks WHERE window_id = ? OR mark = ?;
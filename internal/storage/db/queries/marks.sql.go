@@ -11,11 +11,29 @@ import (
 )
 
 const addMark = `-- name: AddMark :exec
-INSERT INTO marks (window_id, mark) VALUES (?, ?)
+INSERT INTO marks (
+    window_id, mark, app_bundle_id, app_name, window_title, workspace, created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, strftime('%s', 'now'), strftime('%s', 'now'))
 `
 
-func (q *Queries) AddMark(ctx context.Context, windowID int, mark string) error {
-	_, err := q.db.ExecContext(ctx, addMark, windowID, mark)
+type AddMarkParams struct {
+	WindowID    int    `json:"window_id"`
+	Mark        string `json:"mark"`
+	AppBundleID string `json:"app_bundle_id"`
+	AppName     string `json:"app_name"`
+	WindowTitle string `json:"window_title"`
+	Workspace   string `json:"workspace"`
+}
+
+func (q *Queries) AddMark(ctx context.Context, arg AddMarkParams) error {
+	_, err := q.db.ExecContext(ctx, addMark,
+		arg.WindowID,
+		arg.Mark,
+		arg.AppBundleID,
+		arg.AppName,
+		arg.WindowTitle,
+		arg.Workspace,
+	)
 	return err
 }
 
@@ -52,7 +70,8 @@ func (q *Queries) DeleteMarksByWindowIDOrMark(ctx context.Context, windowID int,
 }
 
 const getAllMarks = `-- name: GetAllMarks :many
-SELECT window_id, mark FROM marks
+SELECT window_id, mark, app_bundle_id, app_name, window_title, workspace, created_at, updated_at
+FROM marks
 `
 
 func (q *Queries) GetAllMarks(ctx context.Context) ([]Mark, error) {
@@ -64,7 +83,16 @@ func (q *Queries) GetAllMarks(ctx context.Context) ([]Mark, error) {
 	var items []Mark
 	for rows.Next() {
 		var i Mark
-		if err := rows.Scan(&i.WindowID, &i.Mark); err != nil {
+		if err := rows.Scan(
+			&i.WindowID,
+			&i.Mark,
+			&i.AppBundleID,
+			&i.AppName,
+			&i.WindowTitle,
+			&i.Workspace,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -79,7 +107,7 @@ func (q *Queries) GetAllMarks(ctx context.Context) ([]Mark, error) {
 }
 
 const getMarksByWindowID = `-- name: GetMarksByWindowID :many
-SELECT window_id, mark
+SELECT window_id, mark, app_bundle_id, app_name, window_title, workspace, created_at, updated_at
 FROM marks
 WHERE window_id = ?
 `
@@ -93,7 +121,16 @@ func (q *Queries) GetMarksByWindowID(ctx context.Context, windowID int) ([]Mark,
 	var items []Mark
 	for rows.Next() {
 		var i Mark
-		if err := rows.Scan(&i.WindowID, &i.Mark); err != nil {
+		if err := rows.Scan(
+			&i.WindowID,
+			&i.Mark,
+			&i.AppBundleID,
+			&i.AppName,
+			&i.WindowTitle,
+			&i.Workspace,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -108,12 +145,53 @@ func (q *Queries) GetMarksByWindowID(ctx context.Context, windowID int) ([]Mark,
 }
 
 const getWindowByMark = `-- name: GetWindowByMark :one
-SELECT window_id, mark FROM marks WHERE mark = ?
+SELECT window_id, mark, app_bundle_id, app_name, window_title, workspace, created_at, updated_at
+FROM marks WHERE mark = ?
 `
 
 func (q *Queries) GetWindowByMark(ctx context.Context, mark string) (Mark, error) {
 	row := q.db.QueryRowContext(ctx, getWindowByMark, mark)
 	var i Mark
-	err := row.Scan(&i.WindowID, &i.Mark)
+	err := row.Scan(
+		&i.WindowID,
+		&i.Mark,
+		&i.AppBundleID,
+		&i.AppName,
+		&i.WindowTitle,
+		&i.Workspace,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
 	return i, err
 }
+
+const updateMarkWindow = `-- name: UpdateMarkWindow :execresult
+UPDATE marks
+SET window_id = ?,
+    app_bundle_id = ?,
+    app_name = ?,
+    window_title = ?,
+    workspace = ?,
+    updated_at = strftime('%s', 'now')
+WHERE mark = ?
+`
+
+type UpdateMarkWindowParams struct {
+	WindowID    int    `json:"window_id"`
+	AppBundleID string `json:"app_bundle_id"`
+	AppName     string `json:"app_name"`
+	WindowTitle string `json:"window_title"`
+	Workspace   string `json:"workspace"`
+	Mark        string `json:"mark"`
+}
+
+func (q *Queries) UpdateMarkWindow(ctx context.Context, arg UpdateMarkWindowParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateMarkWindow,
+		arg.WindowID,
+		arg.AppBundleID,
+		arg.AppName,
+		arg.WindowTitle,
+		arg.Workspace,
+		arg.Mark,
+	)
+}
@@ -6,7 +6,90 @@ package queries
 
 // Mark represents a mark in the database
 // This is an alias to the Mark struct in the parent storage package
+//
+// Window metadata (app, title, workspace) is a snapshot taken when the
+// mark was stored and is used to re-bind the mark once the window ID is gone.
 type Mark = struct {
-	WindowID int    `json:"window_id"`
-	Mark     string `json:"mark"`
+	WindowID    int    `json:"window_id"`
+	Mark        string `json:"mark"`
+	AppBundleID string `json:"app_bundle_id,omitempty"`
+	AppName     string `json:"app_name,omitempty"`
+	WindowTitle string `json:"window_title,omitempty"`
+	Workspace   string `json:"workspace,omitempty"`
+	CreatedAt   int64  `json:"created_at,omitempty"`
+	UpdatedAt   int64  `json:"updated_at,omitempty"`
+}
+
+// MarkOrigin is the workspace a marked window was taken from
+// (e.g. by summon or scratchpad) so it can be sent back.
+type MarkOrigin struct {
+	Mark      string `json:"mark"`
+	Workspace string `json:"workspace"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// FocusHistory is a window that had focus before a focus switch.
+type FocusHistory struct {
+	ID        int64 `json:"id"`
+	WindowID  int   `json:"window_id"`
+	FocusedAt int64 `json:"focused_at"`
+}
+
+// FocusCycle is the order of the windows focus-next and focus-prev step
+// through, kept while cycling so the order doesn't change on every step.
+// WindowIds is a comma separated list of window IDs and WindowID the window
+// the last step focused.
+type FocusCycle struct {
+	Pattern   string `json:"pattern"`
+	WindowIds string `json:"window_ids"`
+	WindowID  int    `json:"window_id"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// MarkLauncher is the command that starts the application of a mark when
+// it has no live window.
+type MarkLauncher struct {
+	Mark        string `json:"mark"`
+	Command     string `json:"command"`
+	AppBundleID string `json:"app_bundle_id"`
+	UpdatedAt   int64  `json:"updated_at"`
+}
+
+// WindowTag is a non-unique label of a window. Unlike marks, the same tag
+// can be set on many windows to act on them as a group.
+//
+// The app bundle ID of the window tells a tagged window apart from an
+// unrelated one that got the same window ID later.
+type WindowTag struct {
+	WindowID    int    `json:"window_id"`
+	Tag         string `json:"tag"`
+	AppBundleID string `json:"app_bundle_id"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// MarkChange is a change of the marks in the undo journal, e.g. a mark
+// command or an undo of a previous change.
+type MarkChange struct {
+	ID        int64  `json:"id"`
+	Operation string `json:"operation"`
+	TargetID  int64  `json:"target_id"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// MarkJournal is the state of a mark before and after a MarkChange. A
+// window ID of 0 means the mark didn't exist.
+type MarkJournal struct {
+	ID                  int64  `json:"id"`
+	ChangeID            int64  `json:"change_id"`
+	Mark                string `json:"mark"`
+	PreviousWindowID    int    `json:"previous_window_id"`
+	PreviousAppBundleID string `json:"previous_app_bundle_id"`
+	PreviousAppName     string `json:"previous_app_name"`
+	PreviousWindowTitle string `json:"previous_window_title"`
+	PreviousWorkspace   string `json:"previous_workspace"`
+	WindowID            int    `json:"window_id"`
+	AppBundleID         string `json:"app_bundle_id"`
+	AppName             string `json:"app_name"`
+	WindowTitle         string `json:"window_title"`
+	Workspace           string `json:"workspace"`
 }
@@ -0,0 +1,17 @@
+-- name: SetMarkOrigin :exec
+INSERT INTO mark_origins (mark, workspace, updated_at)
+VALUES (?, ?, strftime('%s', 'now'))
+ON CONFLICT (mark) DO UPDATE SET
+    workspace = excluded.workspace,
+    updated_at = excluded.updated_at;
+
+-- name: GetMarkOrigin :one
+SELECT mark, workspace, updated_at
+FROM mark_origins
+WHERE mark = ?;
+
+-- name: DeleteMarkOrigin :execresult
+DELETE FROM mark_origins WHERE mark = ?;
+
+-- name: DeleteUnmarkedOrigins :exec
+DELETE FROM mark_origins WHERE mark NOT IN (SELECT mark FROM marks);
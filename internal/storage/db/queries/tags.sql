@@ -0,0 +1,30 @@
+-- name: AddTag :exec
+INSERT INTO window_tags (window_id, tag, app_bundle_id, created_at)
+VALUES (?, ?, ?, strftime('%s', 'now'))
+ON CONFLICT (window_id, tag) DO UPDATE SET
+    app_bundle_id = excluded.app_bundle_id;
+
+-- name: GetAllTags :many
+SELECT window_id, tag, app_bundle_id, created_at
+FROM window_tags
+ORDER BY tag, created_at, rowid;
+
+-- name: GetWindowTagsByTag :many
+SELECT window_id, tag, app_bundle_id, created_at
+FROM window_tags
+WHERE tag = ?
+ORDER BY created_at, rowid;
+
+-- name: DeleteTag :execresult
+DELETE FROM window_tags WHERE tag = ?;
+
+-- name: DeleteWindowTag :execresult
+DELETE FROM window_tags WHERE window_id = ? AND tag = ?;
+
+-- name: DeleteWindowTags :execresult
+DELETE FROM window_tags WHERE window_id = ?;
+
+-- name: MoveWindowTags :exec
+UPDATE OR IGNORE window_tags
+SET window_id = sqlc.arg(to_window_id)
+WHERE window_id = sqlc.arg(from_window_id);
@@ -0,0 +1,427 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+)
+
+// Operations of the journal changes made by Undo and Redo, the other
+// changes are named after the mutation, e.g. "add" or "delete_all".
+const (
+	OperationUndo = "undo"
+	OperationRedo = "redo"
+)
+
+// maxJournalChanges is how many changes the journal keeps, besides the
+// changes they undo or redo. The older ones can't be undone anymore.
+const maxJournalChanges = 1000
+
+var (
+	// ErrNothingToUndo is returned by Undo when no change is left to revert.
+	ErrNothingToUndo = errors.New("nothing to undo")
+	// ErrNothingToRedo is returned by Redo when no undone change is left.
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// JournalChange is a change of the marks recorded in the journal.
+type JournalChange struct {
+	ID int64
+	// Operation is the mutation that made the change, mutations made in a
+	// single transaction are joined with "," (e.g. "delete,add")
+	Operation string
+	// TargetID is the change reverted by an undo or applied again by a redo
+	TargetID  int64
+	CreatedAt time.Time
+	// Undone is true when the change is reverted
+	Undone  bool
+	Entries []queries.MarkJournal
+}
+
+// markJournal collects the mutations of a transaction until they are
+// written to the journal as a single change.
+type markJournal struct {
+	operations []string
+	// before is the state of the marks before the first mutation
+	before map[string]queries.Mark
+	// written is true once a change was added in the transaction
+	written bool
+}
+
+// record runs change in a transaction and records the marks it changed in
+// the journal under operation.
+func (c *MarkStorageClient) record(
+	operation string,
+	change func(tx *MarkStorageClient) error,
+) error {
+	return c.withTx(func(tx *MarkStorageClient) error {
+		if tx.journal.before == nil {
+			before, err := tx.markStates()
+			if err != nil {
+				return err
+			}
+			tx.journal.before = before
+		}
+		if !slices.Contains(tx.journal.operations, operation) {
+			tx.journal.operations = append(tx.journal.operations, operation)
+		}
+
+		return change(tx)
+	})
+}
+
+// flushJournal writes the mutations recorded so far in the transaction as a
+// change of the journal. Nothing is written when the marks didn't change.
+func (c *MarkStorageClient) flushJournal() error {
+	if len(c.journal.operations) == 0 {
+		return nil
+	}
+
+	_, err := c.writeChange(strings.Join(c.journal.operations, ","), 0, c.journal.before)
+	c.journal.operations, c.journal.before = nil, nil
+
+	return err
+}
+
+// writeChange writes a change of the journal with an entry for each mark
+// that differs from before. Returns the ID of the change, or 0 when no mark
+// changed and nothing was written.
+func (c *MarkStorageClient) writeChange(
+	operation string,
+	targetID int64,
+	before map[string]queries.Mark,
+) (int64, error) {
+	after, err := c.markStates()
+	if err != nil {
+		return 0, err
+	}
+
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	ctx := context.Background()
+	var changeID int64
+	for _, name := range names {
+		previous, current := before[name], after[name]
+		if sameMarkState(previous, current) {
+			continue
+		}
+
+		if changeID == 0 {
+			changeID, err = c.addChange(operation, targetID)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		err = c.queries.AddMarkJournalEntry(ctx, queries.AddMarkJournalEntryParams{
+			ChangeID:            changeID,
+			Mark:                name,
+			PreviousWindowID:    previous.WindowID,
+			PreviousAppBundleID: previous.AppBundleID,
+			PreviousAppName:     previous.AppName,
+			PreviousWindowTitle: previous.WindowTitle,
+			PreviousWorkspace:   previous.Workspace,
+			WindowID:            current.WindowID,
+			AppBundleID:         current.AppBundleID,
+			AppName:             current.AppName,
+			WindowTitle:         current.WindowTitle,
+			Workspace:           current.Workspace,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return changeID, nil
+}
+
+// addChange adds a change to the journal and returns its ID.
+func (c *MarkStorageClient) addChange(operation string, targetID int64) (int64, error) {
+	changeID, err := c.queries.AddMarkChange(context.Background(), queries.AddMarkChangeParams{
+		Operation: operation,
+		TargetID:  targetID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	c.journal.written = true
+
+	return changeID, nil
+}
+
+// trimJournal keeps the last maxJournalChanges changes of the journal, and
+// the changes they undo or redo, once a transaction added changes.
+func (c *MarkStorageClient) trimJournal() error {
+	if !c.journal.written {
+		return nil
+	}
+
+	ctx := context.Background()
+	if err := c.queries.TrimMarkChanges(ctx, maxJournalChanges); err != nil {
+		return err
+	}
+
+	return c.queries.TrimMarkJournal(ctx)
+}
+
+// markStates returns the current marks by name.
+func (c *MarkStorageClient) markStates() (map[string]queries.Mark, error) {
+	marks, err := c.queries.GetAllMarks(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]queries.Mark, len(marks))
+	for _, mark := range marks {
+		states[mark.Mark] = mark
+	}
+
+	return states, nil
+}
+
+// sameMarkState reports whether two states of a mark are the same, the
+// timestamps aren't part of the state.
+func sameMarkState(a, b queries.Mark) bool {
+	return a.WindowID == b.WindowID &&
+		a.AppBundleID == b.AppBundleID &&
+		a.AppName == b.AppName &&
+		a.WindowTitle == b.WindowTitle &&
+		a.Workspace == b.Workspace
+}
+
+// journalStacks replays the journal and returns the changes that can be
+// undone and the ones that can be redone, the next one last. Every change
+// that was undone at some point is in undone. Undos and redos of changes
+// trimmed from the journal are skipped.
+func journalStacks(changes []queries.MarkChange) ([]int64, []int64, map[int64]bool) {
+	var done, redo []int64
+	undone := make(map[int64]bool)
+	kept := make(map[int64]bool, len(changes))
+	for _, change := range changes {
+		isTarget := change.Operation == OperationUndo || change.Operation == OperationRedo
+		if isTarget && !kept[change.TargetID] {
+			continue
+		}
+
+		switch change.Operation {
+		case OperationUndo:
+			done = slices.DeleteFunc(done, func(id int64) bool { return id == change.TargetID })
+			redo = append(redo, change.TargetID)
+			undone[change.TargetID] = true
+		case OperationRedo:
+			redo = slices.DeleteFunc(redo, func(id int64) bool { return id == change.TargetID })
+			done = append(done, change.TargetID)
+			undone[change.TargetID] = false
+		default:
+			kept[change.ID] = true
+			done = append(done, change.ID)
+			// A new change drops the changes left to redo
+			redo = nil
+		}
+	}
+
+	return done, redo, undone
+}
+
+// GetHistory returns the last limit changes of the journal, the most
+// recent first, or all of them when limit is 0.
+func (c *MarkStorageClient) GetHistory(limit int) ([]JournalChange, error) {
+	changes, err := c.queries.GetMarkChanges(context.Background())
+	if err != nil {
+		return nil, storageError(err)
+	}
+	_, _, undone := journalStacks(changes)
+
+	slices.Reverse(changes)
+	if limit > 0 && len(changes) > limit {
+		changes = changes[:limit]
+	}
+
+	history := make([]JournalChange, 0, len(changes))
+	for _, change := range changes {
+		journalChange, changeErr := c.journalChange(change)
+		if changeErr != nil {
+			return nil, storageError(changeErr)
+		}
+		journalChange.Undone = undone[change.ID]
+		history = append(history, journalChange)
+	}
+
+	return history, nil
+}
+
+// Undo reverts the last n changes that aren't undone yet, the most recent
+// first, and returns them. Each revert is recorded as an undo change.
+// Returns an error wrapping ErrNothingToUndo when there is none.
+func (c *MarkStorageClient) Undo(n int) ([]JournalChange, error) {
+	var reverted []JournalChange
+	err := c.withTx(func(tx *MarkStorageClient) error {
+		done, _, err := tx.stacks()
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			return errkind.Wrap(errkind.InvalidInput, ErrNothingToUndo)
+		}
+
+		for i := len(done) - 1; i >= 0 && len(reverted) < n; i-- {
+			change, applyErr := tx.applyChange(OperationUndo, done[i])
+			if applyErr != nil {
+				return applyErr
+			}
+			reverted = append(reverted, change)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return reverted, nil
+}
+
+// Redo applies again the last undone change and returns it. It is recorded
+// as a redo change. Returns an error wrapping ErrNothingToRedo when no change
+// is undone or a change was made since.
+func (c *MarkStorageClient) Redo() (*JournalChange, error) {
+	var applied JournalChange
+	err := c.withTx(func(tx *MarkStorageClient) error {
+		_, redo, err := tx.stacks()
+		if err != nil {
+			return err
+		}
+		if len(redo) == 0 {
+			return errkind.Wrap(errkind.InvalidInput, ErrNothingToRedo)
+		}
+
+		applied, err = tx.applyChange(OperationRedo, redo[len(redo)-1])
+		return err
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return &applied, nil
+}
+
+// stacks returns the changes that can be undone and the ones that can be
+// redone, see journalStacks.
+func (c *MarkStorageClient) stacks() ([]int64, []int64, error) {
+	changes, err := c.queries.GetMarkChanges(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	done, redo, _ := journalStacks(changes)
+	return done, redo, nil
+}
+
+// applyChange sets the marks of the change targetID back to their previous
+// state for an undo, or to their new state for a redo, records it and
+// returns the target change.
+func (c *MarkStorageClient) applyChange(operation string, targetID int64) (JournalChange, error) {
+	// Mutations made earlier in the transaction are a change of their own
+	if err := c.flushJournal(); err != nil {
+		return JournalChange{}, err
+	}
+
+	ctx := context.Background()
+	target, err := c.queries.GetMarkChange(ctx, targetID)
+	if err != nil {
+		return JournalChange{}, err
+	}
+	change, err := c.journalChange(target)
+	if err != nil {
+		return JournalChange{}, err
+	}
+	before, err := c.markStates()
+	if err != nil {
+		return JournalChange{}, err
+	}
+
+	entries := slices.Clone(change.Entries)
+	if operation == OperationUndo {
+		slices.Reverse(entries)
+	}
+	for _, entry := range entries {
+		if err = c.restoreEntry(entry, operation == OperationUndo); err != nil {
+			return JournalChange{}, err
+		}
+	}
+
+	changeID, err := c.writeChange(operation, targetID, before)
+	if err != nil {
+		return JournalChange{}, err
+	}
+	if changeID == 0 {
+		// The marks are already in that state, the change is recorded anyway
+		// to move the target between the undo and redo stacks
+		if _, err = c.addChange(operation, targetID); err != nil {
+			return JournalChange{}, err
+		}
+	}
+
+	change.Undone = operation == OperationUndo
+	return change, nil
+}
+
+// restoreEntry sets the mark of entry to its previous state, or to its new
+// state when previous is false.
+func (c *MarkStorageClient) restoreEntry(entry queries.MarkJournal, previous bool) error {
+	windowID, metadata := entry.WindowID, WindowMetadata{
+		AppBundleID: entry.AppBundleID,
+		AppName:     entry.AppName,
+		WindowTitle: entry.WindowTitle,
+		Workspace:   entry.Workspace,
+	}
+	if previous {
+		windowID, metadata = entry.PreviousWindowID, WindowMetadata{
+			AppBundleID: entry.PreviousAppBundleID,
+			AppName:     entry.PreviousAppName,
+			WindowTitle: entry.PreviousWindowTitle,
+			Workspace:   entry.PreviousWorkspace,
+		}
+	}
+
+	if _, err := c.deleteByMark(entry.Mark); err != nil {
+		return err
+	}
+	if windowID == 0 {
+		// The mark didn't exist in that state
+		return nil
+	}
+
+	return c.addMark(windowID, entry.Mark, metadata)
+}
+
+// journalChange returns a change of the journal with its entries.
+func (c *MarkStorageClient) journalChange(change queries.MarkChange) (JournalChange, error) {
+	entries, err := c.queries.GetMarkJournalEntries(context.Background(), change.ID)
+	if err != nil {
+		return JournalChange{}, err
+	}
+
+	return JournalChange{
+		ID:        change.ID,
+		Operation: change.Operation,
+		TargetID:  change.TargetID,
+		CreatedAt: time.Unix(change.CreatedAt, 0),
+		Entries:   entries,
+	}, nil
+}
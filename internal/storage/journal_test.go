@@ -0,0 +1,265 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func markWindows(t *testing.T, client storage.MarkStorage) map[string]int {
+	t.Helper()
+	marks, err := client.GetMarks()
+	require.NoError(t, err)
+
+	windows := make(map[string]int, len(marks))
+	for _, mark := range marks {
+		windows[mark.Mark] = mark.WindowID
+	}
+
+	return windows
+}
+
+func operations(t *testing.T, client storage.MarkStorage) []string {
+	t.Helper()
+	history, err := client.GetHistory(0)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(history))
+	for _, change := range history {
+		names = append(names, change.Operation)
+	}
+
+	return names
+}
+
+func TestJournal(t *testing.T) {
+	t.Run("records the marks changed by each mutation", func(t *testing.T) {
+		client := newMarkClient(t)
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{AppName: "Alacritty"}))
+		_, err := client.ReplaceAllMarks(2, "term", storage.WindowMetadata{AppName: "Mail"})
+		require.NoError(t, err)
+		// Deleting a mark that isn't set changes nothing
+		_, err = client.DeleteByMark("missing")
+		require.NoError(t, err)
+
+		history, err := client.GetHistory(0)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+
+		assert.Equal(t, "replace", history[0].Operation)
+		require.Len(t, history[0].Entries, 1)
+		entry := history[0].Entries[0]
+		assert.Equal(t, "term", entry.Mark)
+		assert.Equal(t, 1, entry.PreviousWindowID)
+		assert.Equal(t, "Alacritty", entry.PreviousAppName)
+		assert.Equal(t, 2, entry.WindowID)
+		assert.Equal(t, "Mail", entry.AppName)
+
+		assert.Equal(t, "add", history[1].Operation)
+		assert.Equal(t, 0, history[1].Entries[0].PreviousWindowID)
+	})
+
+	t.Run("records a transaction as a single change", func(t *testing.T) {
+		client := newMarkClient(t)
+		err := client.WithTransaction(func(tx storage.MarkStorage) error {
+			if err := tx.AddMark(1, "term", storage.WindowMetadata{}); err != nil {
+				return err
+			}
+			_, err := tx.DeleteByMark("term")
+			if err != nil {
+				return err
+			}
+			return tx.AddMark(2, "mail", storage.WindowMetadata{})
+		})
+		require.NoError(t, err)
+
+		history, err := client.GetHistory(0)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "add,delete", history[0].Operation)
+		// term was added and removed in the transaction, only mail changed
+		require.Len(t, history[0].Entries, 1)
+		assert.Equal(t, "mail", history[0].Entries[0].Mark)
+	})
+
+	t.Run("limits the history to the most recent changes", func(t *testing.T) {
+		client := newMarkClient(t)
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+		require.NoError(t, client.AddMark(2, "mail", storage.WindowMetadata{}))
+		require.NoError(t, client.ToggleMark(3, "web", storage.WindowMetadata{}))
+
+		history, err := client.GetHistory(2)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, "toggle", history[0].Operation)
+		assert.Equal(t, "add", history[1].Operation)
+		assert.Equal(t, "mail", history[1].Entries[0].Mark)
+	})
+}
+
+func TestUndoRedo(t *testing.T) {
+	t.Run("restores the marks removed by delete_all", func(t *testing.T) {
+		client := newMarkClient(t)
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{AppName: "Alacritty"}))
+		require.NoError(t, client.AddMark(2, "mail", storage.WindowMetadata{}))
+		_, err := client.DeleteAllMarks()
+		require.NoError(t, err)
+
+		reverted, err := client.Undo(1)
+		require.NoError(t, err)
+		require.Len(t, reverted, 1)
+		assert.Equal(t, "delete_all", reverted[0].Operation)
+		assert.True(t, reverted[0].Undone)
+
+		assert.Equal(t, map[string]int{"term": 1, "mail": 2}, markWindows(t, client))
+		term, err := client.GetWindowByMark("term")
+		require.NoError(t, err)
+		assert.Equal(t, "Alacritty", term.AppName)
+	})
+
+	t.Run("reverts the last n changes, the most recent first", func(t *testing.T) {
+		client := newMarkClient(t)
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+		_, err := client.ReplaceAllMarks(2, "term", storage.WindowMetadata{})
+		require.NoError(t, err)
+		require.NoError(t, client.UpdateMarkWindow("term", 3, storage.WindowMetadata{}))
+
+		reverted, err := client.Undo(2)
+		require.NoError(t, err)
+		require.Len(t, reverted, 2)
+		assert.Equal(t, "rebind", reverted[0].Operation)
+		assert.Equal(t, "replace", reverted[1].Operation)
+		assert.Equal(t, map[string]int{"term": 1}, markWindows(t, client))
+
+		// Undoing more changes than there are reverts all of them
+		reverted, err = client.Undo(5)
+		require.NoError(t, err)
+		require.Len(t, reverted, 1)
+		assert.Empty(t, markWindows(t, client))
+
+		_, err = client.Undo(1)
+		require.ErrorIs(t, err, storage.ErrNothingToUndo)
+		assert.Equal(t, errkind.InvalidInput, errkind.Of(err))
+	})
+
+	t.Run("applies the undone changes again", func(t *testing.T) {
+		client := newMarkClient(t)
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+		_, err := client.DeleteByWindow(1)
+		require.NoError(t, err)
+		_, err = client.Undo(2)
+		require.NoError(t, err)
+
+		applied, err := client.Redo()
+		require.NoError(t, err)
+		assert.Equal(t, "add", applied.Operation)
+		assert.False(t, applied.Undone)
+		assert.Equal(t, map[string]int{"term": 1}, markWindows(t, client))
+
+		applied, err = client.Redo()
+		require.NoError(t, err)
+		assert.Equal(t, "delete_window", applied.Operation)
+		assert.Empty(t, markWindows(t, client))
+
+		_, err = client.Redo()
+		require.ErrorIs(t, err, storage.ErrNothingToRedo)
+
+		// The redone changes can be undone again
+		_, err = client.Undo(1)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"term": 1}, markWindows(t, client))
+
+		assert.Equal(
+			t,
+			[]string{"undo", "redo", "redo", "undo", "undo", "delete_window", "add"},
+			operations(t, client),
+		)
+	})
+
+	t.Run("drops the undone changes on a new change", func(t *testing.T) {
+		client := newMarkClient(t)
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+		_, err := client.Undo(1)
+		require.NoError(t, err)
+		require.NoError(t, client.AddMark(2, "mail", storage.WindowMetadata{}))
+
+		_, err = client.Redo()
+		require.ErrorIs(t, err, storage.ErrNothingToRedo)
+
+		history, err := client.GetHistory(0)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		// The dropped change stays undone in the history
+		assert.True(t, history[2].Undone)
+	})
+}
+
+func TestJournalRetention(t *testing.T) {
+	t.Run("keeps the last changes", func(t *testing.T) {
+		// maxJournalChanges, the first toggles are dropped
+		const kept, dropped = 1000, 5
+
+		client := newMarkClient(t)
+		for range kept + dropped {
+			require.NoError(t, client.ToggleMark(1, "term", storage.WindowMetadata{}))
+		}
+
+		history, err := client.GetHistory(0)
+		require.NoError(t, err)
+		require.Len(t, history, kept)
+		assert.Len(t, history[kept-1].Entries, 1)
+
+		// The marks are back to their state after the dropped toggles
+		reverted, err := client.Undo(kept + dropped)
+		require.NoError(t, err)
+		assert.Len(t, reverted, kept)
+		assert.Equal(t, map[string]int{"term": 1}, markWindows(t, client))
+
+		// The undone changes are kept to be redone
+		_, err = client.Redo()
+		require.NoError(t, err)
+		assert.Empty(t, markWindows(t, client))
+	})
+}
+
+func TestRefreshMarkWindow(t *testing.T) {
+	t.Run("isn't recorded in the journal", func(t *testing.T) {
+		client := newMarkClient(t)
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+		require.NoError(
+			t,
+			client.RefreshMarkWindow("term", 2, storage.WindowMetadata{AppName: "Alacritty"}),
+		)
+
+		assert.Equal(t, map[string]int{"term": 2}, markWindows(t, client))
+		assert.Equal(t, []string{"add"}, operations(t, client))
+
+		err := client.RefreshMarkWindow("missing", 2, storage.WindowMetadata{})
+		assert.Equal(t, errkind.MarkNotFound, errkind.Of(err))
+	})
+
+	t.Run("is kept when undoing a change of the same transaction", func(t *testing.T) {
+		client := newMarkClient(t)
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+		err := client.WithTransaction(func(tx storage.MarkStorage) error {
+			if _, err := tx.ReplaceAllMarks(3, "mail", storage.WindowMetadata{}); err != nil {
+				return err
+			}
+			return tx.RefreshMarkWindow("term", 2, storage.WindowMetadata{})
+		})
+		require.NoError(t, err)
+
+		history, err := client.GetHistory(1)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Len(t, history[0].Entries, 1)
+		assert.Equal(t, "mail", history[0].Entries[0].Mark)
+
+		_, err = client.Undo(1)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"term": 2}, markWindows(t, client))
+	})
+}
@@ -0,0 +1,372 @@
+package storage
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
+)
+
+// LazyMarkClient is a MarkStorage that opens the database the first time
+// it is used, so commands that don't need it, or that delegate to the
+// daemon, never open it.
+//
+// Failing to open the database is returned by each call as a Storage error.
+type LazyMarkClient struct {
+	mu sync.Mutex
+	// db is opened by Client without migrating it
+	db     *StorageClient
+	client *MarkStorageClient
+}
+
+// NewLazyMarkClient creates a LazyMarkClient for the database configured
+// with the environment, see GetDatabaseConfig.
+func NewLazyMarkClient() *LazyMarkClient {
+	return &LazyMarkClient{}
+}
+
+// connect opens and migrates the database unless already done.
+func (l *LazyMarkClient) connect() (*MarkStorageClient, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.client != nil {
+		return l.client, nil
+	}
+
+	db, err := l.open()
+	if err != nil {
+		return nil, storageError(err)
+	}
+	if err = db.migrate(); err != nil {
+		return nil, storageError(err)
+	}
+
+	client, err := NewMarkClient(db)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	l.client = client
+
+	return client, nil
+}
+
+// open opens the database unless already done, the lock must be held.
+func (l *LazyMarkClient) open() (*StorageClient, error) {
+	if l.db != nil {
+		return l.db, nil
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		return nil, err
+	}
+	l.db = db
+
+	return db, nil
+}
+
+// Client returns the database client without migrating the database, so
+// its version can be checked. When the database can't be opened, the
+// client returns the error from GetVersion.
+func (l *LazyMarkClient) Client() StorageDBClient {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	db, err := l.open()
+	if err != nil {
+		return &unavailableDBClient{dbConfig: GetDatabaseConfig(), err: err}
+	}
+
+	return db
+}
+
+// Close closes the database when it was opened.
+func (l *LazyMarkClient) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.db == nil {
+		return nil
+	}
+
+	return l.db.Close()
+}
+
+func (l *LazyMarkClient) WithTransaction(fn func(tx MarkStorage) error) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.WithTransaction(fn)
+}
+
+func (l *LazyMarkClient) AddMark(id int, mark string, metadata WindowMetadata) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.AddMark(id, mark, metadata)
+}
+
+func (l *LazyMarkClient) GetMarks() ([]queries.Mark, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetMarks()
+}
+
+func (l *LazyMarkClient) GetMarksByWindowID(id int) ([]queries.Mark, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetMarksByWindowID(id)
+}
+
+func (l *LazyMarkClient) GetWindowByMark(mark string) (*queries.Mark, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetWindowByMark(mark)
+}
+
+func (l *LazyMarkClient) GetWindowIDByMark(mark string) (int, error) {
+	client, err := l.connect()
+	if err != nil {
+		return 0, err
+	}
+	return client.GetWindowIDByMark(mark)
+}
+
+func (l *LazyMarkClient) ReplaceAllMarks(id int, mark string, metadata WindowMetadata) (int64, error) {
+	client, err := l.connect()
+	if err != nil {
+		return 0, err
+	}
+	return client.ReplaceAllMarks(id, mark, metadata)
+}
+
+func (l *LazyMarkClient) ToggleMark(id int, mark string, metadata WindowMetadata) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.ToggleMark(id, mark, metadata)
+}
+
+func (l *LazyMarkClient) UpdateMarkWindow(mark string, id int, metadata WindowMetadata) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.UpdateMarkWindow(mark, id, metadata)
+}
+
+func (l *LazyMarkClient) RefreshMarkWindow(mark string, id int, metadata WindowMetadata) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.RefreshMarkWindow(mark, id, metadata)
+}
+
+func (l *LazyMarkClient) DeleteByMark(mark string) (int64, error) {
+	client, err := l.connect()
+	if err != nil {
+		return 0, err
+	}
+	return client.DeleteByMark(mark)
+}
+
+func (l *LazyMarkClient) DeleteByWindow(windowID int) (int64, error) {
+	client, err := l.connect()
+	if err != nil {
+		return 0, err
+	}
+	return client.DeleteByWindow(windowID)
+}
+
+func (l *LazyMarkClient) DeleteAllMarks() (int64, error) {
+	client, err := l.connect()
+	if err != nil {
+		return 0, err
+	}
+	return client.DeleteAllMarks()
+}
+
+func (l *LazyMarkClient) SetMarkOrigin(mark string, workspace string) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.SetMarkOrigin(mark, workspace)
+}
+
+func (l *LazyMarkClient) GetMarkOrigin(mark string) (string, error) {
+	client, err := l.connect()
+	if err != nil {
+		return "", err
+	}
+	return client.GetMarkOrigin(mark)
+}
+
+func (l *LazyMarkClient) DeleteMarkOrigin(mark string) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.DeleteMarkOrigin(mark)
+}
+
+func (l *LazyMarkClient) PushFocusHistory(windowID int) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.PushFocusHistory(windowID)
+}
+
+func (l *LazyMarkClient) GetPreviousFocus(currentWindowID int) (int, error) {
+	client, err := l.connect()
+	if err != nil {
+		return 0, err
+	}
+	return client.GetPreviousFocus(currentWindowID)
+}
+
+func (l *LazyMarkClient) GetFocusRecency() (map[int]int64, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetFocusRecency()
+}
+
+func (l *LazyMarkClient) SetFocusCycle(cycle FocusCycle) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.SetFocusCycle(cycle)
+}
+
+func (l *LazyMarkClient) GetFocusCycle() (*FocusCycle, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetFocusCycle()
+}
+
+func (l *LazyMarkClient) SetMarkLauncher(mark string, command string, appBundleID string) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.SetMarkLauncher(mark, command, appBundleID)
+}
+
+func (l *LazyMarkClient) GetMarkLauncher(mark string) (*queries.MarkLauncher, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetMarkLauncher(mark)
+}
+
+func (l *LazyMarkClient) AddTag(windowID int, tag string, appBundleID string) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.AddTag(windowID, tag, appBundleID)
+}
+
+func (l *LazyMarkClient) GetTags() ([]queries.WindowTag, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetTags()
+}
+
+func (l *LazyMarkClient) GetWindowTagsByTag(tag string) ([]queries.WindowTag, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetWindowTagsByTag(tag)
+}
+
+func (l *LazyMarkClient) DeleteTag(tag string) (int64, error) {
+	client, err := l.connect()
+	if err != nil {
+		return 0, err
+	}
+	return client.DeleteTag(tag)
+}
+
+func (l *LazyMarkClient) DeleteWindowTag(windowID int, tag string) (int64, error) {
+	client, err := l.connect()
+	if err != nil {
+		return 0, err
+	}
+	return client.DeleteWindowTag(windowID, tag)
+}
+
+func (l *LazyMarkClient) MoveWindowTags(fromWindowID int, toWindowID int) error {
+	client, err := l.connect()
+	if err != nil {
+		return err
+	}
+	return client.MoveWindowTags(fromWindowID, toWindowID)
+}
+
+func (l *LazyMarkClient) GetHistory(limit int) ([]JournalChange, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetHistory(limit)
+}
+
+func (l *LazyMarkClient) Undo(n int) ([]JournalChange, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.Undo(n)
+}
+
+func (l *LazyMarkClient) Redo() (*JournalChange, error) {
+	client, err := l.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.Redo()
+}
+
+// unavailableDBClient is the client of a database that couldn't be opened.
+type unavailableDBClient struct {
+	dbConfig StorageConfig
+	err      error
+}
+
+func (c *unavailableDBClient) Close() error {
+	return nil
+}
+
+func (c *unavailableDBClient) GetStorageConfig() StorageConfig {
+	return c.dbConfig
+}
+
+func (c *unavailableDBClient) GetDB() *sql.DB {
+	return nil
+}
+
+func (c *unavailableDBClient) GetVersion() (int64, error) {
+	return 0, c.err
+}
@@ -0,0 +1,66 @@
+package storage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/constants"
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyMarkClient(t *testing.T) {
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+
+	t.Run("opens the database on first use", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "state")
+		t.Setenv(constants.EnvAeroSpaceMarksDBPath, dbPath)
+
+		client := storage.NewLazyMarkClient()
+		t.Cleanup(func() { _ = client.Close() })
+		_, err := os.Stat(dbPath)
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+		assert.Equal(t, []string{"term"}, markNames(t, client))
+	})
+
+	t.Run("checks the version without migrating", func(t *testing.T) {
+		t.Setenv(constants.EnvAeroSpaceMarksDBPath, t.TempDir())
+
+		client := storage.NewLazyMarkClient()
+		t.Cleanup(func() { _ = client.Close() })
+
+		version, err := client.Client().GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), version)
+
+		_, err = client.GetMarks()
+		require.NoError(t, err)
+		latest, err := storage.LatestMigrationVersion()
+		require.NoError(t, err)
+		version, err = client.Client().GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, latest, version)
+	})
+
+	t.Run("returns a storage error when the database can't be opened", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(file, nil, 0o600))
+		t.Setenv(constants.EnvAeroSpaceMarksDBPath, filepath.Join(file, "state"))
+
+		client := storage.NewLazyMarkClient()
+
+		_, err := client.GetMarks()
+		require.Error(t, err)
+		assert.Equal(t, errkind.Storage, errkind.Of(err))
+
+		_, err = client.Client().GetVersion()
+		require.Error(t, err)
+		require.NoError(t, client.Close())
+	})
+}
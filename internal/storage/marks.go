@@ -4,15 +4,35 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cristianoliveira/aerospace-marks/internal/errkind"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage/db/queries"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
+// ErrMarkExists is returned when adding a mark that is already set, marks
+// are unique.
+var ErrMarkExists = errors.New("mark already exists")
+
+// WindowMetadata is the window information stored alongside a mark.
+//
+// It allows re-binding a mark to a live window once the stored
+// window ID no longer exists (e.g. after AeroSpace or the app restarts).
+type WindowMetadata struct {
+	AppBundleID string
+	AppName     string
+	WindowTitle string
+	Workspace   string
+}
+
 type MarkStorage interface {
 	// AddMark adds a mark to the database
-	AddMark(id int, mark string) error
+	AddMark(id int, mark string, metadata WindowMetadata) error
 	// GetMarks returns all marks in the database
 	GetMarks() ([]queries.Mark, error)
 	// GetMarksByWindowID returns all marks for a given window ID
@@ -22,15 +42,61 @@ type MarkStorage interface {
 	// GetWindowIDByMark returns the window ID for a given mark
 	GetWindowIDByMark(mark string) (int, error)
 	// ReplaceAllMarks replaces all marks for a window with a new mark
-	ReplaceAllMarks(id int, mark string) (int64, error)
+	ReplaceAllMarks(id int, mark string, metadata WindowMetadata) (int64, error)
 	// ToggleMark toggles a mark for a window
-	ToggleMark(id int, mark string) error
+	ToggleMark(id int, mark string, metadata WindowMetadata) error
+	// UpdateMarkWindow re-binds a mark to another window and refreshes its metadata
+	UpdateMarkWindow(mark string, id int, metadata WindowMetadata) error
+	// RefreshMarkWindow follows the window of a mark that changed in AeroSpace,
+	// without recording it in the journal
+	RefreshMarkWindow(mark string, id int, metadata WindowMetadata) error
 	// DeleteByMark removes a mark from the database
 	DeleteByMark(mark string) (int64, error)
 	// DeleteByMark removes a mark from the database
 	DeleteByWindow(windowID int) (int64, error)
 	// DeleteAllMarks removes all marks from the database
 	DeleteAllMarks() (int64, error)
+	// SetMarkOrigin records the workspace the window of a mark was taken from
+	SetMarkOrigin(mark string, workspace string) error
+	// GetMarkOrigin returns the recorded origin workspace of a mark or "" if none
+	GetMarkOrigin(mark string) (string, error)
+	// DeleteMarkOrigin forgets the origin workspace of a mark
+	DeleteMarkOrigin(mark string) error
+	// PushFocusHistory records the window that had focus before a focus switch
+	PushFocusHistory(windowID int) error
+	// GetPreviousFocus returns the last recorded window other than the given one or 0 if none
+	GetPreviousFocus(currentWindowID int) (int, error)
+	// GetFocusRecency returns how recently each window in the focus history had focus
+	GetFocusRecency() (map[int]int64, error)
+	// SetFocusCycle records the order focus-next and focus-prev step through
+	SetFocusCycle(cycle FocusCycle) error
+	// GetFocusCycle returns the last recorded focus cycle or nil if none
+	GetFocusCycle() (*FocusCycle, error)
+	// SetMarkLauncher records the command that starts the application of a mark
+	SetMarkLauncher(mark string, command string, appBundleID string) error
+	// GetMarkLauncher returns the launch command of a mark or nil if none
+	GetMarkLauncher(mark string) (*queries.MarkLauncher, error)
+	// AddTag adds a tag to a window of an app, a tag can be set on many windows
+	AddTag(windowID int, tag string, appBundleID string) error
+	// GetTags returns all window tags
+	GetTags() ([]queries.WindowTag, error)
+	// GetWindowTagsByTag returns the windows with a tag, in tagging order
+	GetWindowTagsByTag(tag string) ([]queries.WindowTag, error)
+	// DeleteTag removes a tag from all windows
+	DeleteTag(tag string) (int64, error)
+	// DeleteWindowTag removes a tag from a window
+	DeleteWindowTag(windowID int, tag string) (int64, error)
+	// MoveWindowTags moves the tags of a window to the window that replaced it
+	MoveWindowTags(fromWindowID int, toWindowID int) error
+	// GetHistory returns the last limit changes of the marks, the most recent first
+	GetHistory(limit int) ([]JournalChange, error)
+	// Undo reverts the last n changes of the marks
+	Undo(n int) ([]JournalChange, error)
+	// Redo applies again the last undone change of the marks
+	Redo() (*JournalChange, error)
+	// WithTransaction runs fn with a storage whose changes are committed when fn
+	// returns nil and rolled back otherwise
+	WithTransaction(fn func(tx MarkStorage) error) error
 	// Close closes the database connection
 	Close() error
 	// Client returns the storage client
@@ -40,6 +106,10 @@ type MarkStorage interface {
 type MarkStorageClient struct {
 	storage StorageDBClient
 	queries *queries.Queries
+	// tx is the transaction of the storage given to WithTransaction
+	tx *sql.Tx
+	// journal collects the mark mutations of the transaction
+	journal *markJournal
 }
 
 func NewMarkClient(storageClient StorageDBClient) (*MarkStorageClient, error) {
@@ -55,19 +125,47 @@ func NewMarkClient(storageClient StorageDBClient) (*MarkStorageClient, error) {
 	return client, nil
 }
 
-func (c *MarkStorageClient) AddMark(id int, mark string) error {
+// AddMark adds a mark to a window.
+// Returns an error wrapping ErrMarkExists if the mark is already set.
+func (c *MarkStorageClient) AddMark(id int, mark string, metadata WindowMetadata) error {
+	err := c.record("add", func(tx *MarkStorageClient) error {
+		return tx.addMark(id, mark, metadata)
+	})
+
+	return storageError(err)
+}
+
+func (c *MarkStorageClient) addMark(id int, mark string, metadata WindowMetadata) error {
 	ctx := context.Background()
-	return c.queries.AddMark(ctx, id, mark)
+	err := c.retryWrite(func() error {
+		return c.queries.AddMark(ctx, queries.AddMarkParams{
+			WindowID:    id,
+			Mark:        mark,
+			AppBundleID: metadata.AppBundleID,
+			AppName:     metadata.AppName,
+			WindowTitle: metadata.WindowTitle,
+			Workspace:   metadata.Workspace,
+		})
+	})
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return errkind.Errorf(errkind.InvalidInput, "%w: '%s'", ErrMarkExists, mark)
+	}
+
+	return storageError(err)
 }
 
 func (c *MarkStorageClient) GetMarks() ([]queries.Mark, error) {
 	ctx := context.Background()
-	return c.queries.GetAllMarks(ctx)
+	marks, err := c.queries.GetAllMarks(ctx)
+	return marks, storageError(err)
 }
 
 func (c *MarkStorageClient) GetMarksByWindowID(id int) ([]queries.Mark, error) {
 	ctx := context.Background()
-	return c.queries.GetMarksByWindowID(ctx, id)
+	marks, err := c.queries.GetMarksByWindowID(ctx, id)
+	return marks, storageError(err)
 }
 
 // GetWindowByMark returns the window for a given mark
@@ -79,9 +177,9 @@ func (c *MarkStorageClient) GetWindowByMark(mark string) (*queries.Mark, error)
 	markedWindow, err := c.queries.GetWindowByMark(ctx, mark)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("no window found for mark %s", mark)
+			return nil, errkind.Errorf(errkind.MarkNotFound, "no window found for mark %s", mark)
 		}
-		return nil, err
+		return nil, storageError(err)
 	}
 
 	return &markedWindow, nil
@@ -96,9 +194,9 @@ func (c *MarkStorageClient) GetWindowIDByMark(markI string) (int, error) {
 	markedWindow, err := c.queries.GetWindowByMark(ctx, markI)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return 0, fmt.Errorf("no window found for mark %s", markI)
+			return 0, errkind.Errorf(errkind.MarkNotFound, "no window found for mark %s", markI)
 		}
-		return 0, err
+		return 0, storageError(err)
 	}
 
 	return markedWindow.WindowID, nil
@@ -106,26 +204,209 @@ func (c *MarkStorageClient) GetWindowIDByMark(markI string) (int, error) {
 
 // ReplaceAllMarks replaces all marks for a window with a new mark
 // This function will delete all marks for the specified window ID and
-// then add the new mark.
-func (c *MarkStorageClient) ReplaceAllMarks(id int, mark string) (int64, error) {
-	ctx := context.Background()
+// then add the new mark, in a single transaction.
+func (c *MarkStorageClient) ReplaceAllMarks(
+	id int,
+	mark string,
+	metadata WindowMetadata,
+) (int64, error) {
+	var rowsAffected int64
+	err := c.record("replace", func(tx *MarkStorageClient) error {
+		// Delete all marks for the window
+		res, err := tx.queries.DeleteMarksByWindowIDOrMark(context.Background(), id, mark)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err = res.RowsAffected()
+		if err != nil {
+			return err
+		}
 
-	// Delete all marks for the window
-	res, err := c.queries.DeleteMarksByWindowIDOrMark(ctx, id, mark)
+		return tx.addMark(id, mark, metadata)
+	})
 	if err != nil {
-		return 0, err
+		return 0, storageError(err)
+	}
+
+	return rowsAffected, nil
+}
+
+// UpdateMarkWindow re-binds a mark to the given window ID and
+// replaces the stored window metadata.
+func (c *MarkStorageClient) UpdateMarkWindow(mark string, id int, metadata WindowMetadata) error {
+	err := c.record("rebind", func(tx *MarkStorageClient) error {
+		return tx.updateMarkWindow(mark, id, metadata)
+	})
+
+	return storageError(err)
+}
+
+// RefreshMarkWindow binds a mark to the window that replaced its window, or
+// refreshes its window metadata, after the window changed in AeroSpace.
+//
+// Unlike UpdateMarkWindow it isn't recorded in the journal: it doesn't
+// change which window is marked. Undoing a change made earlier in the same
+// transaction restores the mark to the refreshed window.
+func (c *MarkStorageClient) RefreshMarkWindow(mark string, id int, metadata WindowMetadata) error {
+	err := c.retryWrite(func() error {
+		return c.updateMarkWindow(mark, id, metadata)
+	})
+	if err != nil {
+		return storageError(err)
+	}
+
+	if c.journal != nil {
+		if before, ok := c.journal.before[mark]; ok {
+			before.WindowID = id
+			before.AppBundleID = metadata.AppBundleID
+			before.AppName = metadata.AppName
+			before.WindowTitle = metadata.WindowTitle
+			before.Workspace = metadata.Workspace
+			c.journal.before[mark] = before
+		}
 	}
+
+	return nil
+}
+
+func (c *MarkStorageClient) updateMarkWindow(mark string, id int, metadata WindowMetadata) error {
+	res, err := c.queries.UpdateMarkWindow(context.Background(), queries.UpdateMarkWindowParams{
+		WindowID:    id,
+		AppBundleID: metadata.AppBundleID,
+		AppName:     metadata.AppName,
+		WindowTitle: metadata.WindowTitle,
+		Workspace:   metadata.Workspace,
+		Mark:        mark,
+	})
+	if err != nil {
+		return err
+	}
+
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		return 0, err
+		return err
+	}
+	if rowsAffected == 0 {
+		return errkind.Errorf(errkind.MarkNotFound, "no window found for mark %s", mark)
 	}
 
-	err = c.AddMark(id, mark)
+	return nil
+}
+
+// WithTransaction runs fn with a storage bound to a new transaction. The
+// changes made through it are committed when fn returns nil and rolled back
+// when it returns an error, which is returned as is.
+//
+// Transactions don't nest: WithTransaction on the storage given to fn runs
+// in the same transaction.
+func (c *MarkStorageClient) WithTransaction(fn func(tx MarkStorage) error) error {
+	return c.withTx(func(tx *MarkStorageClient) error {
+		return fn(tx)
+	})
+}
+
+// withTx runs fn in a new transaction, or in the transaction of c if any.
+// The mark mutations recorded by fn are written to the journal, and the
+// oldest changes trimmed, before the transaction is committed.
+func (c *MarkStorageClient) withTx(fn func(tx *MarkStorageClient) error) error {
+	if c.tx != nil {
+		return fn(c)
+	}
+
+	var tx *sql.Tx
+	err := c.retryWrite(func() (err error) {
+		tx, err = c.storage.GetDB().BeginTx(context.Background(), nil)
+		return err
+	})
 	if err != nil {
-		return rowsAffected, err
+		return storageError(err)
 	}
 
-	return rowsAffected, nil
+	txClient := &MarkStorageClient{
+		storage: c.storage,
+		queries: c.queries.WithTx(tx),
+		tx:      tx,
+		journal: &markJournal{},
+	}
+	err = fn(txClient)
+	if err == nil {
+		err = txClient.deleteUnmarkedRows()
+	}
+	if err == nil {
+		err = txClient.flushJournal()
+	}
+	if err == nil {
+		err = txClient.trimJournal()
+	}
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return storageError(errors.Join(err, rollbackErr))
+		}
+		return err
+	}
+
+	return storageError(tx.Commit())
+}
+
+// deleteUnmarkedRows deletes the origins and launch commands of the marks
+// that no longer exist, so a mark set again later doesn't inherit them.
+func (c *MarkStorageClient) deleteUnmarkedRows() error {
+	ctx := context.Background()
+	if err := c.queries.DeleteUnmarkedOrigins(ctx); err != nil {
+		return err
+	}
+
+	return c.queries.DeleteUnmarkedLaunchers(ctx)
+}
+
+// Writes failing because another process holds the database are retried
+// with an exponential backoff, see retryWrite.
+const (
+	maxWriteAttempts = 5
+	writeRetryDelay  = 20 * time.Millisecond
+)
+
+// retryWrite runs write until it doesn't fail because the database is
+// locked by another process, at most maxWriteAttempts times. The busy
+// timeout of the connection already waits for the lock, retrying covers
+// the locks SQLite gives up on without waiting.
+//
+// Writes in a transaction run once: the transaction holds the write lock.
+func (c *MarkStorageClient) retryWrite(write func() error) error {
+	if c.tx != nil {
+		return write()
+	}
+
+	delay := writeRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if !isBusy(err) || attempt == maxWriteAttempts {
+			return err
+		}
+
+		logger.GetDefaultLogger().LogDebug("database is busy, retrying write", "attempt", attempt)
+		// Jitter keeps processes started together from retrying together
+		//nolint:gosec // the jitter doesn't need a secure random number
+		time.Sleep(delay + rand.N(delay))
+		delay *= 2
+	}
+}
+
+// isBusy reports whether err is SQLite failing because the database is
+// locked by another connection.
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
+
+// storageError marks err as a failure of the marks database, see
+// errkind.Storage. Errors already classified keep their kind.
+func storageError(err error) error {
+	return errkind.Wrap(errkind.Storage, err)
 }
 
 func (c *MarkStorageClient) Close() error {
@@ -137,70 +418,340 @@ func (c *MarkStorageClient) Client() StorageDBClient {
 	return c.storage
 }
 
-// ToggleMark toggles a mark for a window
+// ToggleMark toggles a mark for a window in a single transaction
 // If the mark exists, it will be deleted
 // If the mark does not exist, it will be added.
-func (c *MarkStorageClient) ToggleMark(id int, mark string) error {
-	rowsAffected, err := c.DeleteByMark(mark)
+func (c *MarkStorageClient) ToggleMark(id int, mark string, metadata WindowMetadata) error {
+	err := c.record("toggle", func(tx *MarkStorageClient) error {
+		rowsAffected, err := tx.deleteByMark(mark)
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected > 0 {
+			// Mark was deleted
+			return nil
+		}
+
+		// Mark was not deleted, so add it
+		return tx.addMark(id, mark, metadata)
+	})
+
+	return storageError(err)
+}
+
+// DeleteAllMarks removes all marks from the database.
+func (c *MarkStorageClient) DeleteAllMarks() (int64, error) {
+	var rowsAffected int64
+	err := c.record("delete_all", func(tx *MarkStorageClient) error {
+		res, err := tx.queries.DeleteAllMarks(context.Background())
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err = res.RowsAffected()
+		return err
+	})
 	if err != nil {
+		return 0, storageError(err)
+	}
+
+	return rowsAffected, nil
+}
+
+// DeleteByMark deletes a mark from the database
+// This function will delete the mark from the database.
+func (c *MarkStorageClient) DeleteByMark(mark string) (int64, error) {
+	var rowsAffected int64
+	err := c.record("delete", func(tx *MarkStorageClient) (err error) {
+		rowsAffected, err = tx.deleteByMark(mark)
 		return err
+	})
+	if err != nil {
+		return 0, storageError(err)
 	}
 
-	if rowsAffected > 0 {
-		// Mark was deleted
-		return nil
+	return rowsAffected, nil
+}
+
+func (c *MarkStorageClient) deleteByMark(mark string) (int64, error) {
+	res, err := c.queries.DeleteByMark(context.Background(), mark)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// DeleteByWindow deletes a mark from the database
+// This function will delete the mark from the database.
+func (c *MarkStorageClient) DeleteByWindow(windowID int) (int64, error) {
+	var rowsAffected int64
+	err := c.record("delete_window", func(tx *MarkStorageClient) error {
+		res, err := tx.queries.DeleteByWindow(context.Background(), windowID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, storageError(err)
 	}
 
-	// Mark was not deleted, so add it
-	err = c.AddMark(id, mark)
+	return rowsAffected, nil
+}
+
+// SetMarkOrigin records the workspace the window of a mark was taken from.
+// A previously recorded origin is replaced.
+func (c *MarkStorageClient) SetMarkOrigin(mark string, workspace string) error {
+	ctx := context.Background()
+	err := c.retryWrite(func() error {
+		return c.queries.SetMarkOrigin(ctx, queries.SetMarkOriginParams{
+			Mark:      mark,
+			Workspace: workspace,
+		})
+	})
+	return storageError(err)
+}
+
+// GetMarkOrigin returns the recorded origin workspace of a mark.
+// Returns an empty string when no origin was recorded.
+func (c *MarkStorageClient) GetMarkOrigin(mark string) (string, error) {
+	ctx := context.Background()
+	origin, err := c.queries.GetMarkOrigin(ctx, mark)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", storageError(err)
+	}
+
+	return origin.Workspace, nil
+}
+
+// DeleteMarkOrigin forgets the origin workspace of a mark.
+func (c *MarkStorageClient) DeleteMarkOrigin(mark string) error {
+	ctx := context.Background()
+	err := c.retryWrite(func() error {
+		_, err := c.queries.DeleteMarkOrigin(ctx, mark)
 		return err
+	})
+	return storageError(err)
+}
+
+// maxFocusHistory is how many focus switches are kept.
+const maxFocusHistory = 100
+
+// PushFocusHistory records the window that had focus before a focus switch.
+// Only the last maxFocusHistory entries are kept.
+func (c *MarkStorageClient) PushFocusHistory(windowID int) error {
+	err := c.withTx(func(tx *MarkStorageClient) error {
+		ctx := context.Background()
+		if err := tx.queries.AddFocusHistory(ctx, windowID); err != nil {
+			return err
+		}
+
+		return tx.queries.TrimFocusHistory(ctx, maxFocusHistory)
+	})
+
+	return storageError(err)
+}
+
+// GetPreviousFocus returns the most recently recorded window that is not
+// currentWindowID. Returns 0 when there is none.
+func (c *MarkStorageClient) GetPreviousFocus(currentWindowID int) (int, error) {
+	ctx := context.Background()
+	previous, err := c.queries.GetPreviousFocus(ctx, currentWindowID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, storageError(err)
 	}
 
-	return nil
+	return previous.WindowID, nil
 }
 
-// DeleteAllMarks removes all marks from the database.
-func (c *MarkStorageClient) DeleteAllMarks() (int64, error) {
+// GetFocusRecency returns, for each window in the focus history, a number
+// that is higher the more recently the window had focus.
+func (c *MarkStorageClient) GetFocusRecency() (map[int]int64, error) {
 	ctx := context.Background()
-	res, err := c.queries.DeleteAllMarks(ctx)
+	rows, err := c.queries.GetFocusRecency(ctx)
 	if err != nil {
-		return 0, err
+		return nil, storageError(err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	recency := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		recency[row.WindowID] = row.LastID
+	}
+
+	return recency, nil
+}
+
+// FocusCycle is the order of the windows focus-next and focus-prev step
+// through for a pattern, and the window the last step focused.
+type FocusCycle struct {
+	Pattern   string
+	WindowIDs []int
+	WindowID  int
+}
+
+// SetFocusCycle records the order of a focus cycle, replacing the previous
+// one.
+func (c *MarkStorageClient) SetFocusCycle(cycle FocusCycle) error {
+	ids := make([]string, 0, len(cycle.WindowIDs))
+	for _, id := range cycle.WindowIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	ctx := context.Background()
+	err := c.retryWrite(func() error {
+		return c.queries.SetFocusCycle(ctx, queries.SetFocusCycleParams{
+			Pattern:   cycle.Pattern,
+			WindowIds: strings.Join(ids, ","),
+			WindowID:  cycle.WindowID,
+		})
+	})
+	return storageError(err)
+}
+
+// GetFocusCycle returns the last recorded focus cycle.
+// Returns nil when none was recorded.
+func (c *MarkStorageClient) GetFocusCycle() (*FocusCycle, error) {
+	ctx := context.Background()
+	row, err := c.queries.GetFocusCycle(ctx)
 	if err != nil {
-		return 0, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil // no cycle yet is not an error
+		}
+		return nil, storageError(err)
 	}
-	return rowsAffected, nil
+
+	cycle := &FocusCycle{Pattern: row.Pattern, WindowID: row.WindowID}
+	for _, field := range strings.Split(row.WindowIds, ",") {
+		id, convErr := strconv.Atoi(field)
+		if convErr != nil {
+			continue
+		}
+		cycle.WindowIDs = append(cycle.WindowIDs, id)
+	}
+
+	return cycle, nil
 }
 
-// DeleteByMark deletes a mark from the database
-// This function will delete the mark from the database.
-func (c *MarkStorageClient) DeleteByMark(mark string) (int64, error) {
+// SetMarkLauncher records the command that starts the application of a mark
+// and the bundle ID of its windows. A previously recorded command is replaced.
+func (c *MarkStorageClient) SetMarkLauncher(mark string, command string, appBundleID string) error {
+	ctx := context.Background()
+	err := c.retryWrite(func() error {
+		return c.queries.SetMarkLauncher(ctx, queries.SetMarkLauncherParams{
+			Mark:        mark,
+			Command:     command,
+			AppBundleID: appBundleID,
+		})
+	})
+	return storageError(err)
+}
+
+// GetMarkLauncher returns the launch command of a mark.
+// Returns nil when no command was recorded.
+func (c *MarkStorageClient) GetMarkLauncher(mark string) (*queries.MarkLauncher, error) {
 	ctx := context.Background()
-	res, err := c.queries.DeleteByMark(ctx, mark)
+	launcher, err := c.queries.GetMarkLauncher(ctx, mark)
 	if err != nil {
-		return 0, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil // a mark without launch command is not an error
+		}
+		return nil, storageError(err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	return &launcher, nil
+}
+
+// AddTag adds a tag to a window of the app appBundleID. Tagging a window
+// twice only updates its app.
+func (c *MarkStorageClient) AddTag(windowID int, tag string, appBundleID string) error {
+	ctx := context.Background()
+	err := c.retryWrite(func() error {
+		return c.queries.AddTag(ctx, queries.AddTagParams{
+			WindowID:    windowID,
+			Tag:         tag,
+			AppBundleID: appBundleID,
+		})
+	})
+	return storageError(err)
+}
+
+// GetTags returns all window tags ordered by tag.
+func (c *MarkStorageClient) GetTags() ([]queries.WindowTag, error) {
+	ctx := context.Background()
+	tags, err := c.queries.GetAllTags(ctx)
+	return tags, storageError(err)
+}
+
+// GetWindowTagsByTag returns the windows with a tag, in the order they
+// were tagged.
+func (c *MarkStorageClient) GetWindowTagsByTag(tag string) ([]queries.WindowTag, error) {
+	ctx := context.Background()
+	windowTags, err := c.queries.GetWindowTagsByTag(ctx, tag)
+	return windowTags, storageError(err)
+}
+
+// DeleteTag removes a tag from all windows.
+func (c *MarkStorageClient) DeleteTag(tag string) (int64, error) {
+	ctx := context.Background()
+	var result sql.Result
+	err := c.retryWrite(func() (err error) {
+		result, err = c.queries.DeleteTag(ctx, tag)
+		return err
+	})
 	if err != nil {
-		return 0, err
+		return 0, storageError(err)
 	}
 
-	return rowsAffected, nil
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected, storageError(err)
 }
 
-// DeleteByWindow deletes a mark from the database
-// This function will delete the mark from the database.
-func (c *MarkStorageClient) DeleteByWindow(windowID int) (int64, error) {
+// DeleteWindowTag removes a tag from a window.
+func (c *MarkStorageClient) DeleteWindowTag(windowID int, tag string) (int64, error) {
 	ctx := context.Background()
-	res, err := c.queries.DeleteByWindow(ctx, windowID)
+	var result sql.Result
+	err := c.retryWrite(func() (err error) {
+		result, err = c.queries.DeleteWindowTag(ctx, queries.DeleteWindowTagParams{
+			WindowID: windowID,
+			Tag:      tag,
+		})
+		return err
+	})
 	if err != nil {
-		return 0, err
+		return 0, storageError(err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
-	return rowsAffected, err
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected, storageError(err)
+}
+
+// MoveWindowTags moves the tags of a window to the window that replaced it,
+// e.g. when a mark is re-bound to a window with a new ID. Tags the other
+// window already has are dropped.
+func (c *MarkStorageClient) MoveWindowTags(fromWindowID int, toWindowID int) error {
+	err := c.withTx(func(tx *MarkStorageClient) error {
+		ctx := context.Background()
+		err := tx.queries.MoveWindowTags(ctx, queries.MoveWindowTagsParams{
+			ToWindowID:   toWindowID,
+			FromWindowID: fromWindowID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.queries.DeleteWindowTags(ctx, fromWindowID)
+		return err
+	})
+
+	return storageError(err)
 }
@@ -0,0 +1,251 @@
+package storage_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cristianoliveira/aerospace-marks/internal/constants"
+	"github.com/cristianoliveira/aerospace-marks/internal/logger"
+	"github.com/cristianoliveira/aerospace-marks/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMarkClient connects to a new database in a temporary directory.
+func newMarkClient(t *testing.T) *storage.MarkStorageClient {
+	t.Helper()
+	logger.SetDefaultLogger(&logger.EmptyLogger{})
+	t.Setenv(constants.EnvAeroSpaceMarksDBPath, t.TempDir())
+
+	return connectMarkClient(t)
+}
+
+// connectMarkClient opens another connection to the database of the test,
+// like another aerospace-marks process would.
+func connectMarkClient(t *testing.T) *storage.MarkStorageClient {
+	t.Helper()
+
+	dbClient, err := storage.DefaultConnector.Connect()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dbClient.Close() })
+
+	client, err := storage.NewMarkClient(dbClient)
+	require.NoError(t, err)
+
+	return client
+}
+
+func markNames(t *testing.T, client storage.MarkStorage) []string {
+	t.Helper()
+	marks, err := client.GetMarks()
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(marks))
+	for _, mark := range marks {
+		names = append(names, mark.Mark)
+	}
+
+	return names
+}
+
+func TestWithTransaction(t *testing.T) {
+	t.Run("commits the changes when the function succeeds", func(t *testing.T) {
+		client := newMarkClient(t)
+
+		err := client.WithTransaction(func(tx storage.MarkStorage) error {
+			if err := tx.AddMark(1, "term", storage.WindowMetadata{}); err != nil {
+				return err
+			}
+			return tx.AddMark(2, "mail", storage.WindowMetadata{})
+		})
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"term", "mail"}, markNames(t, client))
+	})
+
+	t.Run("rolls back every change when the function fails", func(t *testing.T) {
+		client := newMarkClient(t)
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+
+		errAbort := errors.New("abort")
+		err := client.WithTransaction(func(tx storage.MarkStorage) error {
+			if _, err := tx.DeleteAllMarks(); err != nil {
+				return err
+			}
+			if err := tx.AddMark(2, "mail", storage.WindowMetadata{}); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+
+		assert.Equal(t, []string{"term"}, markNames(t, client))
+	})
+
+	t.Run("runs nested transactions in the outer one", func(t *testing.T) {
+		client := newMarkClient(t)
+
+		err := client.WithTransaction(func(tx storage.MarkStorage) error {
+			if err := tx.ToggleMark(1, "term", storage.WindowMetadata{}); err != nil {
+				return err
+			}
+			return tx.AddMark(2, "term", storage.WindowMetadata{})
+		})
+		require.ErrorIs(t, err, storage.ErrMarkExists)
+
+		assert.Empty(t, markNames(t, client))
+	})
+}
+
+// TestConcurrentWrites runs many clients at once against the same database,
+// like aerospace-marks processes started by hotkeys pressed in a row.
+func TestMarkCompanions(t *testing.T) {
+	t.Run("deletes the origin and launch command with the mark", func(t *testing.T) {
+		client := newMarkClient(t)
+
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+		require.NoError(t, client.SetMarkOrigin("term", "2"))
+		require.NoError(t, client.SetMarkLauncher("term", "open -a Alacritty", "io.alacritty"))
+
+		_, err := client.DeleteByMark("term")
+		require.NoError(t, err)
+
+		origin, err := client.GetMarkOrigin("term")
+		require.NoError(t, err)
+		assert.Empty(t, origin)
+		launcher, err := client.GetMarkLauncher("term")
+		require.NoError(t, err)
+		assert.Nil(t, launcher)
+	})
+
+	t.Run("keeps them when the mark is set again in the transaction", func(t *testing.T) {
+		client := newMarkClient(t)
+
+		require.NoError(t, client.AddMark(1, "term", storage.WindowMetadata{}))
+		require.NoError(t, client.SetMarkLauncher("term", "open -a Alacritty", "io.alacritty"))
+
+		err := client.WithTransaction(func(tx storage.MarkStorage) error {
+			if _, err := tx.DeleteByMark("term"); err != nil {
+				return err
+			}
+			return tx.AddMark(2, "term", storage.WindowMetadata{})
+		})
+		require.NoError(t, err)
+
+		launcher, err := client.GetMarkLauncher("term")
+		require.NoError(t, err)
+		require.NotNil(t, launcher)
+		assert.Equal(t, "open -a Alacritty", launcher.Command)
+	})
+}
+
+func TestMoveWindowTags(t *testing.T) {
+	client := newMarkClient(t)
+
+	require.NoError(t, client.AddTag(1, "meeting", "us.zoom.xos"))
+	require.NoError(t, client.AddTag(1, "work", "us.zoom.xos"))
+	require.NoError(t, client.AddTag(2, "work", "us.zoom.xos"))
+
+	require.NoError(t, client.MoveWindowTags(1, 2))
+
+	tags, err := client.GetTags()
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	for _, tag := range tags {
+		assert.Equal(t, 2, tag.WindowID)
+		assert.Equal(t, "us.zoom.xos", tag.AppBundleID)
+	}
+}
+
+func TestFocusCycle(t *testing.T) {
+	t.Run("returns nil before any cycle", func(t *testing.T) {
+		client := newMarkClient(t)
+
+		cycle, err := client.GetFocusCycle()
+		require.NoError(t, err)
+		assert.Nil(t, cycle)
+	})
+
+	t.Run("replaces the recorded cycle", func(t *testing.T) {
+		client := newMarkClient(t)
+
+		require.NoError(t, client.SetFocusCycle(storage.FocusCycle{
+			Pattern:   "web:",
+			WindowIDs: []int{3, 1, 2},
+			WindowID:  1,
+		}))
+		require.NoError(t, client.SetFocusCycle(storage.FocusCycle{
+			Pattern:   "term",
+			WindowIDs: []int{4, 5},
+			WindowID:  5,
+		}))
+
+		cycle, err := client.GetFocusCycle()
+		require.NoError(t, err)
+		assert.Equal(t, &storage.FocusCycle{
+			Pattern:   "term",
+			WindowIDs: []int{4, 5},
+			WindowID:  5,
+		}, cycle)
+	})
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	const (
+		clients    = 8
+		iterations = 25
+	)
+
+	first := newMarkClient(t)
+	latest, err := storage.LatestMigrationVersion()
+	require.NoError(t, err)
+
+	markClients := []*storage.MarkStorageClient{first}
+	for range clients - 1 {
+		// Migrated already, the other clients skip the migrations
+		client := connectMarkClient(t)
+		version, versionErr := client.Client().GetVersion()
+		require.NoError(t, versionErr)
+		require.Equal(t, latest, version)
+		markClients = append(markClients, client)
+	}
+
+	// Each iteration makes 4 writes
+	errs := make(chan error, clients*iterations*4)
+	var wg sync.WaitGroup
+	for c, client := range markClients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range iterations {
+				windowID := c*1000 + i
+				added := fmt.Sprintf("added-%d-%d", c, i)
+				errs <- client.AddMark(windowID, added, storage.WindowMetadata{})
+				_, replaceErr := client.ReplaceAllMarks(
+					windowID,
+					fmt.Sprintf("replaced-%d-%d", c, i),
+					storage.WindowMetadata{},
+				)
+				errs <- replaceErr
+				// Every client toggles the same mark an even number of times
+				errs <- client.ToggleMark(windowID, "shared", storage.WindowMetadata{})
+				errs <- client.PushFocusHistory(windowID)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	names := markNames(t, first)
+	assert.Len(t, names, clients*iterations)
+	for _, name := range names {
+		assert.True(t, strings.HasPrefix(name, "replaced-"), "unexpected mark %s", name)
+	}
+}
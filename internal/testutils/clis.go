@@ -108,11 +108,33 @@ func readFileToString(f *os.File) (string, error) {
 
 type MockEmptyAerspaceMarkWindows struct{}
 
-func (d *MockEmptyAerspaceMarkWindows) Client() *aerospacecli.AeroSpaceWM {
-	return &aerospacecli.AeroSpaceWM{}
+func (d *MockEmptyAerspaceMarkWindows) Client() (*aerospacecli.AeroSpaceWM, error) {
+	return &aerospacecli.AeroSpaceWM{}, nil
 }
 
 func (d *MockEmptyAerspaceMarkWindows) GetWindowByID(windowID int) (*windows.Window, error) {
 	fmt.Fprintln(os.Stdout, "Mocked GetWindowByID called with windowID:", windowID)
 	return &windows.Window{}, nil
 }
+
+func (d *MockEmptyAerspaceMarkWindows) Connect() error {
+	return nil
+}
+
+// MockUnavailableAerospaceMarkWindows is an AeroSpace that can't be reached,
+// every call fails with Err.
+type MockUnavailableAerospaceMarkWindows struct {
+	Err error
+}
+
+func (d *MockUnavailableAerospaceMarkWindows) Client() (*aerospacecli.AeroSpaceWM, error) {
+	return nil, d.Err
+}
+
+func (d *MockUnavailableAerospaceMarkWindows) GetWindowByID(_ int) (*windows.Window, error) {
+	return nil, d.Err
+}
+
+func (d *MockUnavailableAerospaceMarkWindows) Connect() error {
+	return d.Err
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"os"
+
 	"github.com/cristianoliveira/aerospace-marks/cmd"
 	"github.com/cristianoliveira/aerospace-marks/internal/aerospace"
+	"github.com/cristianoliveira/aerospace-marks/internal/constants"
+	"github.com/cristianoliveira/aerospace-marks/internal/daemon"
 	"github.com/cristianoliveira/aerospace-marks/internal/logger"
 	"github.com/cristianoliveira/aerospace-marks/internal/stdout"
 	"github.com/cristianoliveira/aerospace-marks/internal/storage"
@@ -22,25 +26,20 @@ func main() {
 	logger.SetDefaultLogger(defaultLogger)
 	defaultLogger.LogInfo("Starting Aerospace Marks CLI")
 
-	connector := storage.MarksDatabaseConnector{}
-	conn, err := connector.Connect()
-	if err != nil {
-		stdout.ErrorAndExit(err)
-	}
-	markClient, err := storage.NewMarkClient(conn)
-	if err != nil {
-		stdout.ErrorAndExit(err)
+	// Commands delegate to the daemon only when the socket is explicitly set
+	if os.Getenv(constants.EnvAeroSpaceMarksSocket) != "" {
+		daemon.SetDefaultClient(daemon.NewClient(daemon.SocketPath()))
 	}
+
+	// The database and AeroSpace are only connected once a command uses them,
+	// commands delegated to the daemon don't
+	markClient := storage.NewLazyMarkClient()
 	defer func() {
-		if closeErr := conn.Close(); closeErr != nil {
+		if closeErr := markClient.Close(); closeErr != nil {
 			stdout.ErrorAndExit(closeErr)
 		}
 	}()
-
-	aerospaceMarkClient, err := aerospace.NewAeroSpaceClient()
-	if err != nil {
-		stdout.ErrorAndExit(err)
-	}
+	aerospaceMarkClient := aerospace.NewLazyAeroSpaceClient()
 
 	cmd.Run(markClient, aerospaceMarkClient)
 }
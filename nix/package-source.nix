@@ -9,7 +9,7 @@
     # sources that will be used for our derivation.
     src = ../.;
 
-    vendorHash = "sha256-9FNgpx4PtA9VCbeMdo+BQaED0MWGNmX8E0YjFNsSy04=";
+    vendorHash = "sha256-/Bsdi3k0THONSzLV2eUgahvOINJTfslvcr4Qwp38cUE=";
 
     ldflags = [
       "-s" "-w"